@@ -0,0 +1,178 @@
+package auth
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Canonical encoding tags, one per supported kind of value
+const (
+	canonicalStruct byte = 'S'
+	canonicalList   byte = 'L'
+	canonicalBytes  byte = 'B'
+	canonicalString byte = 'T'
+	canonicalUint   byte = 'U'
+	canonicalInt    byte = 'I'
+	canonicalBool   byte = 'b'
+	canonicalNil    byte = 'N'
+)
+
+// CanonicalEncode returns a deterministic byte representation of the provided structure,
+// that does not depend on the Go declaration of fields nor on the Go formatting rules,
+// so that other implementations can reproduce it from the message definitions alone.
+//
+// Every value is encoded as a one-byte tag followed by its content, all lengths and integers being 64-bit big-endian:
+//   - message: 'S', number of fields, then for each non-zero field sorted by field number: field number, value
+//   - repeated field: 'L', number of elements, then each element (bytes excepted)
+//   - bytes: 'B', length, raw bytes
+//   - string: 'T', length, raw UTF-8 bytes
+//   - unsigned and signed integers (enums included): 'U' or 'I', value (two's complement for negative ones)
+//   - bool: 'b', one byte (0 or 1)
+//   - missing message: 'N'
+//
+// Field numbers are the ones of the protobuf definition, read from the protobuf tag of generated structures.
+// Other structures must number their fields with a `canonical:"n"` tag, as they have no such definition.
+// Zero-valued fields (0, false, empty string, bytes or list, missing message) are skipped,
+// so that adding an optional field to a message does not change the encoding of existing values.
+// Protobuf internal fields (XXX_ prefix) are ignored.
+// Other kinds (maps, floats, interfaces...) and fields without number are not supported.
+func CanonicalEncode(structure interface{}) ([]byte, error) {
+	b := new(bytes.Buffer)
+	err := canonicalEncodeValue(b, reflect.ValueOf(structure))
+	if err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
+
+func canonicalEncodeValue(b *bytes.Buffer, v reflect.Value) error {
+	switch v.Kind() {
+	case reflect.Invalid:
+		b.WriteByte(canonicalNil)
+	case reflect.Ptr:
+		if v.IsNil() {
+			b.WriteByte(canonicalNil)
+			return nil
+		}
+		return canonicalEncodeValue(b, v.Elem())
+	case reflect.Struct:
+		return canonicalEncodeStruct(b, v)
+	case reflect.Slice, reflect.Array:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			data := make([]byte, v.Len())
+			reflect.Copy(reflect.ValueOf(data), v)
+			b.WriteByte(canonicalBytes)
+			canonicalWriteBytes(b, data)
+			return nil
+		}
+		b.WriteByte(canonicalList)
+		canonicalWriteUint(b, uint64(v.Len()))
+		for i := 0; i < v.Len(); i++ {
+			if err := canonicalEncodeValue(b, v.Index(i)); err != nil {
+				return err
+			}
+		}
+	case reflect.String:
+		b.WriteByte(canonicalString)
+		canonicalWriteBytes(b, []byte(v.String()))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		b.WriteByte(canonicalUint)
+		canonicalWriteUint(b, v.Uint())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		b.WriteByte(canonicalInt)
+		canonicalWriteUint(b, uint64(v.Int()))
+	case reflect.Bool:
+		b.WriteByte(canonicalBool)
+		if v.Bool() {
+			b.WriteByte(1)
+		} else {
+			b.WriteByte(0)
+		}
+	default:
+		return fmt.Errorf("Unsupported kind for canonical encoding: %v", v.Kind())
+	}
+	return nil
+}
+
+// canonicalField is a non-zero field of a structure, with its field number
+type canonicalField struct {
+	number uint64
+	value  reflect.Value
+}
+
+func canonicalEncodeStruct(b *bytes.Buffer, v reflect.Value) error {
+	t := v.Type()
+	var fields []canonicalField
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.PkgPath != "" || strings.HasPrefix(f.Name, "XXX_") {
+			continue
+		}
+		number, err := fieldNumber(f)
+		if err != nil {
+			return fmt.Errorf("Field %s.%s cannot be canonically encoded: %v", t.Name(), f.Name, err)
+		}
+		if !isZeroValue(v.Field(i)) {
+			fields = append(fields, canonicalField{number, v.Field(i)})
+		}
+	}
+	sort.Slice(fields, func(i, j int) bool { return fields[i].number < fields[j].number })
+
+	b.WriteByte(canonicalStruct)
+	canonicalWriteUint(b, uint64(len(fields)))
+	for i, f := range fields {
+		if i > 0 && f.number == fields[i-1].number {
+			return fmt.Errorf("Duplicate field number %d in %s", f.number, t.Name())
+		}
+		canonicalWriteUint(b, f.number)
+		if err := canonicalEncodeValue(b, f.value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// fieldNumber returns the number of a field, from its protobuf tag ("wiretype,number,...") or its canonical tag
+func fieldNumber(f reflect.StructField) (uint64, error) {
+	tag := f.Tag.Get("canonical")
+	if p := strings.Split(f.Tag.Get("protobuf"), ","); len(p) > 1 {
+		tag = p[1]
+	}
+	if tag == "" {
+		return 0, errors.New("missing field number")
+	}
+	number, err := strconv.ParseUint(tag, 10, 64)
+	if err != nil || number == 0 {
+		return 0, fmt.Errorf("invalid field number %q", tag)
+	}
+	return number, nil
+}
+
+func isZeroValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface, reflect.Map:
+		return v.IsNil()
+	case reflect.Slice, reflect.String:
+		return v.Len() == 0
+	case reflect.Struct:
+		return false
+	}
+	return reflect.DeepEqual(v.Interface(), reflect.Zero(v.Type()).Interface())
+}
+
+func canonicalWriteUint(b *bytes.Buffer, n uint64) {
+	var buf [8]byte
+	binary.BigEndian.PutUint64(buf[:], n)
+	b.Write(buf[:])
+}
+
+func canonicalWriteBytes(b *bytes.Buffer, data []byte) {
+	canonicalWriteUint(b, uint64(len(data)))
+	b.Write(data)
+}
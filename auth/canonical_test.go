@@ -0,0 +1,74 @@
+package auth
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type canonicalA struct {
+	Name  string      `canonical:"1"`
+	Items []uint32    `canonical:"2"`
+	Data  []byte      `canonical:"3"`
+	Next  *canonicalA `canonical:"4"`
+}
+
+type canonicalB struct {
+	Next    *canonicalA `canonical:"4"`
+	Data    []byte      `canonical:"3"`
+	Label   string      `canonical:"1"`
+	Numbers []uint32    `canonical:"2"`
+	Extra   int64       `canonical:"5"`
+}
+
+func TestCanonicalEncode(t *testing.T) {
+	a := canonicalA{Name: "a", Items: []uint32{1, 2}, Data: []byte{0x42}, Next: &canonicalA{Name: "b"}}
+	b := canonicalB{Label: "a", Numbers: []uint32{1, 2}, Data: []byte{0x42}, Next: &canonicalA{Name: "b"}}
+
+	resA, err := CanonicalEncode(a)
+	assert.Nil(t, err)
+	resB, err := CanonicalEncode(&b)
+	assert.Nil(t, err)
+
+	// Field names, order and pointers do not matter, neither do zero fields
+	assert.Equal(t, resA, resB)
+
+	b.Extra = 1
+	resB, _ = CanonicalEncode(b)
+	assert.NotEqual(t, resA, resB)
+
+	// Different values must not collide
+	a.Items = []uint32{1}
+	a.Data = []byte{0x42, 0, 0, 0, 0, 0, 0, 0}
+	resC, _ := CanonicalEncode(a)
+	assert.NotEqual(t, resA, resC)
+
+	_, err = CanonicalEncode(map[string]int{})
+	assert.NotNil(t, err)
+
+	// Fields must be numbered, even when they are zero
+	_, err = CanonicalEncode(struct{ Name string }{})
+	assert.NotNil(t, err)
+	_, err = CanonicalEncode(struct {
+		A string `canonical:"1"`
+		B string `canonical:"1"`
+	}{"a", "b"})
+	assert.NotNil(t, err)
+}
+
+// Test the encoding of a protobuf message, whose fields are numbered by their protobuf tag
+func TestCanonicalEncodeProtobuf(t *testing.T) {
+	type message struct {
+		Hash             []byte `protobuf:"bytes,2,opt,name=hash,proto3" json:"hash,omitempty"`
+		Code             int32  `protobuf:"varint,1,opt,name=code" json:"code,omitempty"`
+		XXX_unrecognized []byte `json:"-"`
+	}
+
+	res, err := CanonicalEncode(message{Code: -1, Hash: []byte{7}, XXX_unrecognized: []byte{1}})
+	assert.Nil(t, err)
+	assert.Equal(t, []byte{
+		'S', 0, 0, 0, 0, 0, 0, 0, 2,
+		0, 0, 0, 0, 0, 0, 0, 1, 'I', 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+		0, 0, 0, 0, 0, 0, 0, 2, 'B', 0, 0, 0, 0, 0, 0, 0, 1, 7,
+	}, res)
+}
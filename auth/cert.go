@@ -1,8 +1,9 @@
 package auth
 
 import (
+	"bytes"
+	"crypto"
 	"crypto/rand"
-	"crypto/rsa"
 	"crypto/sha512"
 	"crypto/x509"
 	"crypto/x509/pkix"
@@ -18,7 +19,7 @@ import (
 // GetCertificateRequest creates a request to be sent to any authoritative signer, as a PEM-encoded array of bytes.
 //
 // It can be safely sent via the network.
-func GetCertificateRequest(country, organization, unit, mail string, key *rsa.PrivateKey) ([]byte, error) {
+func GetCertificateRequest(country, organization, unit, mail string, key crypto.Signer) ([]byte, error) {
 	template := &x509.CertificateRequest{
 		Subject: pkix.Name{
 			Country:            []string{country},
@@ -57,7 +58,7 @@ func PEMToCertificateRequest(data []byte) (*x509.CertificateRequest, error) {
 // The serial has to be unique and positive.
 //
 // The generated certificate can safely be distributed to unknown actors.
-func GetCertificate(days int, serial uint64, req *x509.CertificateRequest, parent *x509.Certificate, key *rsa.PrivateKey) ([]byte, error) {
+func GetCertificate(days int, serial uint64, req *x509.CertificateRequest, parent *x509.Certificate, key crypto.Signer) ([]byte, error) {
 
 	template := &x509.Certificate{
 		SerialNumber: new(big.Int).SetUint64(serial),
@@ -85,7 +86,7 @@ func GetCertificate(days int, serial uint64, req *x509.CertificateRequest, paren
 // The serial has to be unique and positive.
 //
 // The generated certificate should be distributed to any other actor in the network under this CA.
-func GetSelfSignedCertificate(days int, serial uint64, country, organization, unit, cn string, key *rsa.PrivateKey) ([]byte, error) {
+func GetSelfSignedCertificate(days int, serial uint64, country, organization, unit, cn string, key crypto.Signer) ([]byte, error) {
 
 	template := &x509.Certificate{
 		SerialNumber: new(big.Int).SetUint64(serial),
@@ -102,7 +103,7 @@ func GetSelfSignedCertificate(days int, serial uint64, country, organization, un
 		DNSNames: []string{"*"},
 	}
 
-	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	der, err := x509.CreateCertificate(rand.Reader, template, template, key.Public(), key)
 
 	if err != nil {
 		return nil, err
@@ -130,6 +131,27 @@ func GetCertificateHash(cert *x509.Certificate) []byte {
 	return h[:]
 }
 
+// CheckCertificate verifies that the certificate is signed by the provided ca, and that its SHA512 hash is the expected one.
+func CheckCertificate(cert, ca *x509.Certificate, hash []byte) error {
+	return CheckCertificateAt(cert, ca, hash, time.Time{})
+}
+
+// CheckCertificateAt is CheckCertificate, the validity periods being checked at the provided time (the current one if zero).
+func CheckCertificateAt(cert, ca *x509.Certificate, hash []byte, at time.Time) error {
+	if !bytes.Equal(GetCertificateHash(cert), hash) {
+		return errors.New("Certificate hash mismatch")
+	}
+
+	pool := x509.NewCertPool()
+	pool.AddCert(ca)
+	_, err := cert.Verify(x509.VerifyOptions{
+		Roots:       pool,
+		KeyUsages:   []x509.ExtKeyUsage{x509.ExtKeyUsageAny},
+		CurrentTime: at,
+	})
+	return err
+}
+
 // GenerateUID generates a unique identifier as a uint64
 func GenerateUID() uint64 {
 	// Generating and converting the uuid to fit our needs: an 8 bytes unsigned integer
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"os"
 	"testing"
+	"time"
 )
 
 var pkey *rsa.PrivateKey
@@ -155,6 +156,33 @@ func TestGetCertificateHash(t *testing.T) {
 	}
 }
 
+func TestCheckCertificate(t *testing.T) {
+	req, _ := PEMToCertificateRequest([]byte(csrFixture))
+	ca, _ := PEMToCertificate([]byte(crtFixture))
+	key, _ := PEMToPrivateKey([]byte(keyFixture))
+
+	res, _ := GetCertificate(10, 22, req, ca, key)
+	crt, _ := PEMToCertificate(res)
+
+	if err := CheckCertificate(crt, ca, GetCertificateHash(crt)); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := CheckCertificate(crt, ca, GetCertificateHash(ca)); err == nil {
+		t.Fatal("Bad hash not detected")
+	}
+
+	other, _ := GetSelfSignedCertificate(1, 0, "", "", "", "other", pkey)
+	otherCA, _ := PEMToCertificate(other)
+	if err := CheckCertificate(crt, otherCA, GetCertificateHash(crt)); err == nil {
+		t.Fatal("Bad issuer not detected")
+	}
+
+	if err := CheckCertificateAt(crt, ca, GetCertificateHash(crt), crt.NotAfter.Add(time.Hour)); err == nil {
+		t.Fatal("Expired certificate not detected")
+	}
+}
+
 func ExampleGetCertificate() {
 
 	// Load elements from PEM files
@@ -0,0 +1,70 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"time"
+)
+
+// GetRevocationList builds a certificate revocation list (CRL) signed by an authoritative certificate (CA), as a PEM-encoded array of bytes.
+//
+// The number has to be increased each time the list of revoked certificates changes.
+// The returned list should be refreshed by its users once the validity duration is elapsed.
+func GetRevocationList(number uint64, revoked []x509.RevocationListEntry, validity time.Duration, ca *x509.Certificate, key crypto.Signer) ([]byte, error) {
+	template := &x509.RevocationList{
+		Number:                    new(big.Int).SetUint64(number),
+		ThisUpdate:                time.Now(),
+		NextUpdate:                time.Now().Add(validity),
+		RevokedCertificateEntries: revoked,
+	}
+
+	// CAs generated by earlier versions neither declare key usages, which allows every usage,
+	// nor a subject key identifier. Both are required by the x509 package to issue a CRL.
+	issuer := *ca
+	if issuer.KeyUsage == 0 {
+		issuer.KeyUsage = x509.KeyUsageCRLSign
+	}
+	if len(issuer.SubjectKeyId) == 0 {
+		pub, err := x509.MarshalPKIXPublicKey(ca.PublicKey)
+		if err != nil {
+			return nil, err
+		}
+		h := sha1.Sum(pub)
+		issuer.SubjectKeyId = h[:]
+	}
+
+	der, err := x509.CreateRevocationList(rand.Reader, template, &issuer, key)
+	if err != nil {
+		return nil, err
+	}
+
+	return pem.EncodeToMemory(&pem.Block{
+		Type:  "X509 CRL",
+		Bytes: der,
+	}), nil
+}
+
+// PEMToRevocationList tries to decode a PEM-encoded array of bytes to a certificate revocation list,
+// and checks that it has been signed by the provided ca.
+func PEMToRevocationList(data []byte, ca *x509.Certificate) (*x509.RevocationList, error) {
+	block, _ := pem.Decode(data)
+	if block == nil || block.Type != "X509 CRL" {
+		return nil, errors.New("Data is not a valid pem-encoded revocation list")
+	}
+
+	list, err := x509.ParseRevocationList(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+
+	err = list.CheckSignatureFrom(ca)
+	if err != nil {
+		return nil, err
+	}
+	return list, nil
+}
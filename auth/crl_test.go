@@ -0,0 +1,43 @@
+package auth
+
+import (
+	"crypto/x509"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRevocationList(t *testing.T) {
+	caKey, ca := newTypedActor(t, KeyTypeRSA, "ca", nil, nil)
+	_, cert := newTypedActor(t, KeyTypeRSA, "user", ca, caKey)
+
+	revoked := []x509.RevocationListEntry{
+		{SerialNumber: cert.SerialNumber, RevocationTime: time.Now()},
+	}
+
+	data, err := GetRevocationList(3, revoked, time.Hour, ca, caKey)
+	assert.Nil(t, err)
+
+	list, err := PEMToRevocationList(data, ca)
+	assert.Nil(t, err)
+	assert.Equal(t, big.NewInt(3), list.Number)
+	assert.Equal(t, 1, len(list.RevokedCertificateEntries))
+	assert.Equal(t, cert.SerialNumber, list.RevokedCertificateEntries[0].SerialNumber)
+	assert.True(t, list.NextUpdate.After(time.Now()))
+
+	// Bad signer
+	otherKey, other := newTypedActor(t, KeyTypeEd25519, "other", nil, nil)
+	_, err = PEMToRevocationList(data, other)
+	assert.NotNil(t, err)
+
+	data, err = GetRevocationList(1, nil, time.Hour, other, otherKey)
+	assert.Nil(t, err)
+	_, err = PEMToRevocationList(data, ca)
+	assert.NotNil(t, err)
+
+	// Bad encoding
+	_, err = PEMToRevocationList([]byte(crtFixture), ca)
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,58 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/asn1"
+	"errors"
+	"io"
+)
+
+// envelope is the wire format of data encrypted for the owner of a certificate.
+type envelope struct {
+	Key        []byte // Content key, encrypted for the recipient (see encryptKey)
+	Ciphertext []byte // AES-256-GCM ciphertext, see encryptGCM
+}
+
+// envelopeLabel binds the content keys to their usage.
+var envelopeLabel = []byte("dfss envelope")
+
+// EncryptFor encrypts data for the owner of a certificate, so that it can be stored by an untrusted third party.
+// The label is authenticated but not encrypted: the recipient must provide the same one to decrypt the data.
+func EncryptFor(cert *x509.Certificate, data, label []byte) ([]byte, error) {
+	key := make([]byte, pcsKeySize)
+	if _, err := io.ReadFull(rand.Reader, key); err != nil {
+		return nil, err
+	}
+
+	var e envelope
+	var err error
+	e.Key, err = encryptKey(cert.PublicKey, key, envelopeLabel)
+	if err != nil {
+		return nil, err
+	}
+
+	e.Ciphertext, err = encryptGCM(key, data, label)
+	if err != nil {
+		return nil, err
+	}
+
+	return asn1.Marshal(e)
+}
+
+// DecryptWith decrypts data produced by EncryptFor, using the private key of the recipient.
+func DecryptWith(key crypto.Signer, data, label []byte) ([]byte, error) {
+	var e envelope
+	rest, err := asn1.Unmarshal(data, &e)
+	if err != nil || len(rest) > 0 {
+		return nil, errors.New("Invalid envelope")
+	}
+
+	contentKey, err := decryptKey(key, e.Key, envelopeLabel)
+	if err != nil {
+		return nil, errors.New("Unable to decrypt content key")
+	}
+
+	return decryptGCM(contentKey, e.Ciphertext, label)
+}
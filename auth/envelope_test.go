@@ -0,0 +1,31 @@
+package auth
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEnvelope(t *testing.T) {
+	data := []byte("evidence")
+	label := []byte("label")
+
+	for _, keyType := range KeyTypes {
+		rKey, rCert := newTypedActor(t, keyType, "recipient", nil, nil)
+		oKey, _ := newTypedActor(t, keyType, "other", nil, nil)
+
+		encrypted, err := EncryptFor(rCert, data, label)
+		assert.Nil(t, err, keyType)
+
+		decrypted, err := DecryptWith(rKey, encrypted, label)
+		assert.Nil(t, err, keyType)
+		assert.Equal(t, data, decrypted, keyType)
+
+		_, err = DecryptWith(rKey, encrypted, []byte("other label"))
+		assert.NotNil(t, err, keyType)
+		_, err = DecryptWith(oKey, encrypted, label)
+		assert.NotNil(t, err, keyType)
+		_, err = DecryptWith(rKey, encrypted[:len(encrypted)-1], label)
+		assert.NotNil(t, err, keyType)
+	}
+}
@@ -0,0 +1,919 @@
+package auth
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha512"
+	"crypto/x509"
+	"encoding/asn1"
+	"errors"
+	"math/big"
+
+	"filippo.io/bigmod"
+	"filippo.io/edwards25519"
+)
+
+// A verifiable escrow encrypts the signature of a statement for the TTP, and proves to the designated recipient
+// that the TTP will be able to recover it, without revealing the signature to the recipient.
+//
+// Construction
+//
+// This is the escrow of the private contract signatures of Mukhamedov and Ryan ("Fair multi-party contract signing
+// using private contract signatures", Information and Computation 206(2), 2008), who follow the abuse-free PCS of
+// Garay, Jakobsson and MacKenzie (CRYPTO '99). It is instantiated with the cut-and-choose verifiable escrow of
+// homomorphic inverse images of Asokan, Shoup and Waidner ("Optimistic fair exchange of digital signatures",
+// IEEE JSAC 18(4), 2000), and made designated-verifier by an OR-composition with a proof of knowledge of the key of
+// the recipient (Jakobsson, Sako and Impagliazzo, EUROCRYPT '96; Cramer, Damgard and Schoenmakers, CRYPTO '94):
+//
+//  1. Every supported signature is a witness w of a relation phi(w) = target, phi being a group homomorphism:
+//     w^e mod N for RSA PKCS#1 v1.5, [s]R for ECDSA, [S]B for Ed25519 (see rsaRelation, ecdsaRelation and
+//     ed25519Relation). The public part R of ECDSA and Ed25519 signatures is published as the commitment.
+//  2. In each of the pcsRounds rounds, w is split in two uniformly random shares a and b (w = a*b mod N for RSA,
+//     w = a+b mod the group order otherwise). Both shares are encrypted for the TTP with KEM + AES-256-GCM, the
+//     additional data binding them to the statement, the label, the round and the side. phi(a) is published.
+//  3. The sender simulates a proof of knowledge of the private key of the recipient for a random challenge c' (see
+//     simulateRecipientProof). The challenge c = SHA-512(escrow context and content) XOR c' selects one share per
+//     round, whose KEM randomness is revealed.
+//  4. The recipient re-derives every revealed key, decrypts the selected shares and checks phi(a) = image, or
+//     phi(b) * image = target. The TTP decrypts both shares of any round, combines them and keeps the first
+//     combination that is a valid signature (VerifyStatement).
+//
+// Security argument
+//
+//   - Completeness: honest shares pass step 4 and combine into w in every round.
+//   - Soundness: the KEM randomness determines the encrypted shares, so a sender unable to make the TTP recover a
+//     signature cannot have both shares of a round valid, and passes a round with probability at most 1/2. As the
+//     challenge is a random oracle output XOR c', and c' is fixed by the hashed commitments of the recipient proof,
+//     which is special sound (Guillou-Quisquater or Schnorr), cheating requires q*2^-pcsRounds for q hash queries
+//     unless the sender knows the private key of the recipient.
+//   - Privacy: a revealed share is uniformly distributed whatever w is, and the unrevealed one is protected by the
+//     IND-CCA security of the KEM (RSA-KEM or ECDH, the key being derived with HMAC-SHA512) and of AES-GCM.
+//   - Non-transferability: knowing their private key, the recipient can pick the revealed shares, derive c' from c
+//     and answer the recipient proof, building an indistinguishable escrow of any statement without any signature.
+//     The escrow therefore convinces nobody else.
+//   - Conversion only returns a signature that passes VerifyStatement, so the TTP never outputs garbage.
+//
+// The group arithmetic relies on constant-time implementations: filippo.io/bigmod for shares and for the RSA
+// private operation of the TTP, filippo.io/nistec and filippo.io/edwards25519 for curve points, crypto/ecdh for the
+// KEM. math/big is only used on public values.
+//
+// Size and cost
+//
+// An escrow holds pcsRounds images, 2*pcsRounds encapsulations and ciphertexts of shares, and pcsRounds openings.
+// With 2048-bit RSA for every party, an escrow takes about 210 KB; with P-256, 48 KB; with P-384, 66 KB; with
+// Ed25519, 35 KB. Creating an escrow costs 2*pcsRounds encapsulations and pcsRounds evaluations of phi, verifying
+// it pcsRounds of each, and converting it at least two decapsulations. On a single core, this is about
+// 200 ms to create, 20 ms to verify and 12 ms to convert with RSA-2048, and below 50 ms to create or verify with
+// P-256 and Ed25519 (300 ms with P-384).
+
+// pcsRounds is the number of cut-and-choose rounds of a verifiable escrow.
+const pcsRounds = 128
+
+// pcsChallengeSize is the size in bytes of the challenges of a verifiable escrow, one bit per round.
+const pcsChallengeSize = pcsRounds / 8
+
+// escrowDomain separates the challenges of verifiable escrows from other hashes.
+var escrowDomain = []byte("dfss verifiable escrow")
+
+// escrow is the wire format of a verifiable escrow.
+type escrow struct {
+	Commitment []byte        // Public part of the signature, R for ECDSA and Ed25519 (empty for RSA)
+	Rounds     []escrowRound // Exactly pcsRounds rounds
+	Proof      recipientProof
+}
+
+type escrowRound struct {
+	Image   []byte        // Image of the first share by phi
+	Shares  []escrowShare // Both shares, encrypted for the TTP
+	Opening []byte        // Encapsulation randomness of the share selected by the challenge
+}
+
+type escrowShare struct {
+	Encapsulation []byte // Encapsulated key, see kemEncapsulate
+	Ciphertext    []byte // Share encrypted with the encapsulated key, see encryptGCM
+}
+
+// recipientProof is a proof of knowledge of the private key of the recipient, for the challenge it contains.
+type recipientProof struct {
+	Challenge   []byte
+	Commitments [][]byte
+	Responses   [][]byte
+}
+
+// createEscrow builds the verifiable escrow of a signature of the statement, made with the key of the certificate.
+func createEscrow(cert *x509.Certificate, signature, statement, label []byte, recipient, ttp crypto.PublicKey) ([]byte, error) {
+	hash := sha512.Sum512(statement)
+	commitment, w, err := signatureWitness(cert.PublicKey, hash[:], signature)
+	if err != nil {
+		return nil, err
+	}
+	r, err := newRelation(cert.PublicKey, hash[:], commitment)
+	if err != nil {
+		return nil, err
+	}
+
+	e := &escrow{Commitment: commitment, Rounds: make([]escrowRound, pcsRounds)}
+	openings := make([][2][]byte, pcsRounds)
+	for i := range e.Rounds {
+		a, b, err := r.split(w)
+		if err != nil {
+			return nil, err
+		}
+
+		round := &e.Rounds[i]
+		round.Image, err = r.phi(a)
+		if err != nil {
+			return nil, err
+		}
+		round.Shares = make([]escrowShare, 2)
+		for side, share := range []*bigmod.Nat{a, b} {
+			randomness, encapsulation, key, err := kemEncapsulate(ttp)
+			if err != nil {
+				return nil, err
+			}
+			ciphertext, err := encryptGCM(key, share.Bytes(r.order), shareData(statement, label, i, side))
+			if err != nil {
+				return nil, err
+			}
+			round.Shares[side] = escrowShare{Encapsulation: encapsulation, Ciphertext: ciphertext}
+			openings[i][side] = randomness
+		}
+	}
+
+	e.Proof, err = simulateRecipientProof(recipient)
+	if err != nil {
+		return nil, err
+	}
+
+	challenge, err := escrowChallenge(e, cert, statement, label, recipient, ttp)
+	if err != nil {
+		return nil, err
+	}
+	for i := range e.Rounds {
+		e.Rounds[i].Opening = openings[i][challengeBit(challenge, i)]
+	}
+
+	return asn1.Marshal(*e)
+}
+
+// verifyEscrow checks a verifiable escrow as its designated recipient.
+func verifyEscrow(data []byte, cert *x509.Certificate, statement, label []byte, recipient, ttp crypto.PublicKey) error {
+	e, err := parseEscrow(data)
+	if err != nil {
+		return err
+	}
+
+	hash := sha512.Sum512(statement)
+	r, err := newRelation(cert.PublicKey, hash[:], e.Commitment)
+	if err != nil {
+		return err
+	}
+
+	if err = verifyRecipientProof(recipient, e.Proof); err != nil {
+		return err
+	}
+
+	challenge, err := escrowChallenge(e, cert, statement, label, recipient, ttp)
+	if err != nil {
+		return err
+	}
+
+	for i, round := range e.Rounds {
+		side := challengeBit(challenge, i)
+		share := round.Shares[side]
+		key, err := kemOpen(ttp, round.Opening, share.Encapsulation)
+		if err != nil {
+			return err
+		}
+		plaintext, err := decryptGCM(key, share.Ciphertext, shareData(statement, label, i, side))
+		if err != nil {
+			return errors.New("Unable to decrypt escrow share")
+		}
+		s, err := r.parseShare(plaintext)
+		if err != nil {
+			return err
+		}
+		if !r.check(round.Image, s, side) {
+			return errors.New("Invalid escrow share")
+		}
+	}
+
+	return nil
+}
+
+// convertEscrow opens a verifiable escrow as the TTP, and returns the escrowed signature.
+func convertEscrow(key crypto.Signer, data []byte, cert *x509.Certificate, statement, label []byte) ([]byte, error) {
+	e, err := parseEscrow(data)
+	if err != nil {
+		return nil, err
+	}
+
+	hash := sha512.Sum512(statement)
+	r, err := newRelation(cert.PublicKey, hash[:], e.Commitment)
+	if err != nil {
+		return nil, err
+	}
+
+	// Any round with two valid shares is enough
+	for i, round := range e.Rounds {
+		var shares [2]*bigmod.Nat
+		for side, share := range round.Shares {
+			secret, err := kemDecapsulate(key, share.Encapsulation)
+			if err != nil {
+				break
+			}
+			plaintext, err := decryptGCM(secret, share.Ciphertext, shareData(statement, label, i, side))
+			if err != nil {
+				break
+			}
+			if shares[side], err = r.parseShare(plaintext); err != nil {
+				break
+			}
+		}
+		if shares[0] == nil || shares[1] == nil {
+			continue
+		}
+
+		signature := r.signature(r.combine(shares[0], shares[1]))
+		if VerifyStatement(cert, statement, signature) == nil {
+			return signature, nil
+		}
+	}
+
+	return nil, errors.New("Unable to open escrow")
+}
+
+func parseEscrow(data []byte) (*escrow, error) {
+	e := new(escrow)
+	rest, err := asn1.Unmarshal(data, e)
+	if err != nil {
+		return nil, err
+	}
+	if len(rest) > 0 {
+		return nil, errors.New("Trailing data after escrow")
+	}
+
+	if len(e.Rounds) != pcsRounds {
+		return nil, errors.New("Invalid number of escrow rounds")
+	}
+	for _, round := range e.Rounds {
+		if len(round.Shares) != 2 {
+			return nil, errors.New("Invalid number of escrow shares")
+		}
+	}
+	return e, nil
+}
+
+// escrowChallenge computes the challenge selecting the shares to open, from the whole escrow and its context.
+// The hash is combined with the challenge of the recipient proof, see simulateRecipientProof.
+func escrowChallenge(e *escrow, cert *x509.Certificate, statement, label []byte, recipient, ttp crypto.PublicKey) ([]byte, error) {
+	if len(e.Proof.Challenge) != pcsChallengeSize {
+		return nil, errors.New("Invalid recipient proof")
+	}
+
+	recipientKey, err := x509.MarshalPKIXPublicKey(recipient)
+	if err != nil {
+		return nil, err
+	}
+	ttpKey, err := x509.MarshalPKIXPublicKey(ttp)
+	if err != nil {
+		return nil, err
+	}
+
+	h := sha512.New()
+	for _, data := range [][]byte{escrowDomain, statement, label, cert.Raw, recipientKey, ttpKey, e.Commitment} {
+		_, _ = h.Write(lengthPrefixed(data))
+	}
+	for _, round := range e.Rounds {
+		_, _ = h.Write(lengthPrefixed(round.Image))
+		for _, share := range round.Shares {
+			_, _ = h.Write(lengthPrefixed(share.Encapsulation))
+			_, _ = h.Write(lengthPrefixed(share.Ciphertext))
+		}
+	}
+	for _, commitment := range e.Proof.Commitments {
+		_, _ = h.Write(lengthPrefixed(commitment))
+	}
+
+	challenge := h.Sum(nil)[:pcsChallengeSize]
+	for i := range challenge {
+		challenge[i] ^= e.Proof.Challenge[i]
+	}
+	return challenge, nil
+}
+
+func challengeBit(challenge []byte, i int) int {
+	return int(challenge[i/8]>>uint(i%8)) & 1
+}
+
+// shareData binds an escrow share to its context and position.
+func shareData(statement, label []byte, round, side int) []byte {
+	return append(escrowData(statement, label), byte(round), byte(side))
+}
+
+// relation is the one-way relation phi(w) = target satisfied by the witness of a signature.
+// Shares are handled with filippo.io/bigmod, whose arithmetic is constant-time.
+type relation struct {
+	order          *bigmod.Modulus // Shares are integers modulo order
+	multiplicative bool            // Whether shares are combined by multiplication (RSA) or addition
+
+	phi       func(share *bigmod.Nat) ([]byte, error)
+	compose   func(x, y []byte) ([]byte, error) // Group operation on images
+	target    []byte
+	signature func(w *bigmod.Nat) []byte
+}
+
+// split returns two random shares a and b that combine into w.
+func (r *relation) split(w *bigmod.Nat) (*bigmod.Nat, *bigmod.Nat, error) {
+	for {
+		a, err := randomNat(r.order)
+		if err != nil {
+			return nil, nil, err
+		}
+		if !r.multiplicative {
+			return a, bigmod.NewNat().ExpandFor(r.order).Add(w, r.order).Sub(a, r.order), nil
+		}
+
+		inv, ok, err := inverse(a, r.order)
+		if err != nil {
+			return nil, nil, err
+		}
+		if ok {
+			return a, inv.Mul(w, r.order), nil
+		}
+	}
+}
+
+func (r *relation) combine(a, b *bigmod.Nat) *bigmod.Nat {
+	w := bigmod.NewNat().ExpandFor(r.order).Add(a, r.order)
+	if r.multiplicative {
+		return w.Mul(b, r.order)
+	}
+	return w.Add(b, r.order)
+}
+
+func (r *relation) parseShare(data []byte) (*bigmod.Nat, error) {
+	s, err := bigmod.NewNat().SetBytes(data, r.order)
+	if err != nil {
+		return nil, errors.New("Invalid escrow share")
+	}
+	return s, nil
+}
+
+// check verifies a share against the published image: phi(share) = image for the first share,
+// and phi(share) * image = target for the second one.
+func (r *relation) check(image []byte, share *bigmod.Nat, side int) bool {
+	y, err := r.phi(share)
+	if err != nil {
+		return false
+	}
+	if side == 0 {
+		return bytes.Equal(y, image)
+	}
+	composed, err := r.compose(y, image)
+	return err == nil && bytes.Equal(composed, r.target)
+}
+
+// newRelation builds the relation satisfied by the witness of a signature of the hash.
+func newRelation(pub crypto.PublicKey, hash, commitment []byte) (*relation, error) {
+	switch pub := pub.(type) {
+	case *rsa.PublicKey:
+		if len(commitment) > 0 {
+			return nil, errors.New("Unexpected commitment")
+		}
+		return rsaRelation(pub, hash)
+	case *ecdsa.PublicKey:
+		return ecdsaRelation(pub, hash, commitment)
+	case ed25519.PublicKey:
+		return ed25519Relation(pub, hash, commitment)
+	}
+	return nil, errors.New("Unsupported public key")
+}
+
+// signatureWitness extracts the commitment and the witness of a signature of the hash.
+func signatureWitness(pub crypto.PublicKey, hash, signature []byte) ([]byte, *bigmod.Nat, error) {
+	switch pub := pub.(type) {
+	case *rsa.PublicKey:
+		n, err := bigmod.NewModulus(pub.N.Bytes())
+		if err != nil {
+			return nil, nil, err
+		}
+		w, err := bigmod.NewNat().SetBytes(signature, n)
+		if err != nil {
+			return nil, nil, errors.New("Invalid RSA signature")
+		}
+		return nil, w, nil
+	case *ecdsa.PublicKey:
+		return ecdsaWitness(pub, hash, signature)
+	case ed25519.PublicKey:
+		if len(signature) != ed25519.SignatureSize {
+			return nil, nil, errors.New("Invalid Ed25519 signature")
+		}
+		s, err := bigmod.NewNat().SetBytes(reverse(signature[32:]), ed25519Group.order)
+		if err != nil {
+			return nil, nil, errors.New("Invalid Ed25519 signature")
+		}
+		return signature[:32], s, nil
+	}
+	return nil, nil, errors.New("Unsupported public key")
+}
+
+// sha512DigestInfo is the DER prefix of PKCS#1 v1.5 signatures of SHA-512 hashes.
+var sha512DigestInfo = []byte{0x30, 0x51, 0x30, 0x0d, 0x06, 0x09, 0x60, 0x86, 0x48, 0x01, 0x65, 0x03, 0x04, 0x02, 0x03, 0x05, 0x00, 0x04, 0x40}
+
+// rsaRelation is w^e = EM mod N, EM being the PKCS#1 v1.5 encoding of the hash.
+func rsaRelation(pub *rsa.PublicKey, hash []byte) (*relation, error) {
+	k := (pub.N.BitLen() + 7) / 8
+	if len(hash) != sha512.Size || k < len(sha512DigestInfo)+len(hash)+11 {
+		return nil, errors.New("Invalid RSA key size")
+	}
+
+	em := make([]byte, k)
+	em[1] = 1
+	for i := 2; i < k-len(sha512DigestInfo)-len(hash)-1; i++ {
+		em[i] = 0xff
+	}
+	copy(em[k-len(hash)-len(sha512DigestInfo):], sha512DigestInfo)
+	copy(em[k-len(hash):], hash)
+
+	n, err := bigmod.NewModulus(pub.N.Bytes())
+	if err != nil {
+		return nil, err
+	}
+	e := uint(pub.E)
+	parse := func(data []byte) (*bigmod.Nat, error) {
+		if len(data) != k {
+			return nil, errors.New("Invalid RSA element")
+		}
+		return bigmod.NewNat().SetBytes(data, n)
+	}
+
+	return &relation{
+		order:          n,
+		multiplicative: true,
+		phi: func(share *bigmod.Nat) ([]byte, error) {
+			return bigmod.NewNat().ExpShortVarTime(share, e, n).Bytes(n), nil
+		},
+		compose: func(x, y []byte) ([]byte, error) {
+			a, err := parse(x)
+			if err != nil {
+				return nil, err
+			}
+			b, err := parse(y)
+			if err != nil {
+				return nil, err
+			}
+			return a.Mul(b, n).Bytes(n), nil
+		},
+		target: em,
+		signature: func(w *bigmod.Nat) []byte {
+			return w.Bytes(n)
+		},
+	}, nil
+}
+
+type ecdsaSignature struct {
+	R, S *big.Int
+}
+
+// ecdsaRelation is [s]R = [e]G + [r]Q, r being the x-coordinate of the commitment R.
+func ecdsaRelation(pub *ecdsa.PublicKey, hash, commitment []byte) (*relation, error) {
+	g, q, err := ecdsaGroup(pub)
+	if err != nil {
+		return nil, err
+	}
+	r, err := ecdsaCommitment(g, commitment)
+	if err != nil {
+		return nil, err
+	}
+
+	eG, err := g.base(hashToScalar(hash, g.order))
+	if err != nil {
+		return nil, err
+	}
+	rQ, err := g.mul(q, r)
+	if err != nil {
+		return nil, err
+	}
+	target, err := g.add(eG, rQ)
+	if err != nil {
+		return nil, err
+	}
+
+	return &relation{
+		order: g.order,
+		phi: func(share *bigmod.Nat) ([]byte, error) {
+			return g.mul(commitment, share.Bytes(g.order))
+		},
+		compose: g.add,
+		target:  target,
+		signature: func(s *bigmod.Nat) []byte {
+			data, _ := asn1.Marshal(ecdsaSignature{R: new(big.Int).SetBytes(r), S: new(big.Int).SetBytes(s.Bytes(g.order))})
+			return data
+		},
+	}, nil
+}
+
+// ecdsaWitness recovers the commitment R = [e/s]G + [r/s]Q of an ECDSA signature, the witness being s.
+func ecdsaWitness(pub *ecdsa.PublicKey, hash, signature []byte) ([]byte, *bigmod.Nat, error) {
+	g, q, err := ecdsaGroup(pub)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	invalid := errors.New("Invalid ECDSA signature")
+	sig := new(ecdsaSignature)
+	if rest, err := asn1.Unmarshal(signature, sig); err != nil || len(rest) > 0 || sig.R.Sign() <= 0 || sig.S.Sign() <= 0 {
+		return nil, nil, invalid
+	}
+	r, err := bigmod.NewNat().SetBytes(sig.R.Bytes(), g.order)
+	if err != nil {
+		return nil, nil, invalid
+	}
+	s, err := bigmod.NewNat().SetBytes(sig.S.Bytes(), g.order)
+	if err != nil {
+		return nil, nil, invalid
+	}
+	sInv, ok, err := inverse(s, g.order)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !ok {
+		return nil, nil, invalid
+	}
+
+	e, _ := bigmod.NewNat().SetBytes(hashToScalar(hash, g.order), g.order)
+	u1, err := g.base(e.Mul(sInv, g.order).Bytes(g.order))
+	if err != nil {
+		return nil, nil, err
+	}
+	u2, err := g.mul(q, r.Mul(sInv, g.order).Bytes(g.order))
+	if err != nil {
+		return nil, nil, err
+	}
+	commitment, err := g.add(u1, u2)
+	if err != nil {
+		return nil, nil, err
+	}
+	return commitment, s, nil
+}
+
+// ecdsaCommitment checks the commitment R of an ECDSA signature, and returns r, its x-coordinate modulo the order.
+func ecdsaCommitment(g *group, commitment []byte) ([]byte, error) {
+	if err := g.decode(commitment); err != nil {
+		return nil, errors.New("Invalid commitment")
+	}
+
+	x := commitment[1 : 1+(len(commitment)-1)/2]
+	r, err := bigmod.NewNat().SetOverflowingBytes(x, g.order)
+	if err != nil || r.IsZero() == 1 {
+		return nil, errors.New("Invalid commitment")
+	}
+	return r.Bytes(g.order), nil
+}
+
+// hashToScalar converts a hash into a scalar the way ECDSA does, keeping its leftmost bits.
+func hashToScalar(hash []byte, order *bigmod.Modulus) []byte {
+	size := order.Size()
+	if len(hash) > size {
+		hash = hash[:size]
+	}
+
+	e := new(big.Int).SetBytes(hash)
+	if excess := len(hash)*8 - order.BitLen(); excess > 0 {
+		e.Rsh(e, uint(excess))
+	}
+	n, _ := bigmod.NewNat().SetOverflowingBytes(e.FillBytes(make([]byte, size)), order)
+	return n.Bytes(order)
+}
+
+// ed25519Relation is [S]B = R + [k]A, with k = SHA-512(R || A || M), as defined in RFC 8032.
+func ed25519Relation(pub ed25519.PublicKey, hash, commitment []byte) (*relation, error) {
+	g := ed25519Group
+	if len(pub) != ed25519.PublicKeySize {
+		return nil, errors.New("Invalid Ed25519 public key")
+	}
+	if err := g.decode(commitment); err != nil {
+		return nil, errors.New("Invalid commitment")
+	}
+
+	h := sha512.New()
+	_, _ = h.Write(commitment)
+	_, _ = h.Write(pub)
+	_, _ = h.Write(hash)
+	k, err := edwards25519.NewScalar().SetUniformBytes(h.Sum(nil))
+	if err != nil {
+		return nil, err
+	}
+
+	kA, err := g.mul(pub, reverse(k.Bytes()))
+	if err != nil {
+		return nil, err
+	}
+	target, err := g.add(commitment, kA)
+	if err != nil {
+		return nil, err
+	}
+
+	return &relation{
+		order: g.order,
+		phi: func(share *bigmod.Nat) ([]byte, error) {
+			return g.base(share.Bytes(g.order))
+		},
+		compose: g.add,
+		target:  target,
+		signature: func(s *bigmod.Nat) []byte {
+			return append(append([]byte{}, commitment...), reverse(s.Bytes(g.order))...)
+		},
+	}, nil
+}
+
+// kemEncapsulate generates a random key encapsulated for the owner of the public key.
+// The randomness reveals the key to anyone, who can check that it matches the encapsulation with kemOpen.
+//
+// RSA keys use RSA-KEM, elliptic-curve keys an ephemeral Diffie-Hellman key agreement (see encryptKey).
+func kemEncapsulate(pub crypto.PublicKey) (randomness, encapsulation, key []byte, err error) {
+	if rsaPub, ok := pub.(*rsa.PublicKey); ok {
+		n, err := bigmod.NewModulus(rsaPub.N.Bytes())
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		x, err := randomNat(n)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		randomness = x.Bytes(n)
+		key, encapsulation, err = kemOpenRSA(rsaPub, randomness)
+		return randomness, encapsulation, key, err
+	}
+
+	remote, err := ecdhPublicKey(pub)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	ephemeral, err := remote.Curve().GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	shared, err := ephemeral.ECDH(remote)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	encapsulation = ephemeral.PublicKey().Bytes()
+	return ephemeral.Bytes(), encapsulation, deriveKeyEncryptionKey(shared, encapsulation, pcsLabel), nil
+}
+
+// kemOpen recomputes the key encapsulated by kemEncapsulate from its randomness.
+func kemOpen(pub crypto.PublicKey, randomness, encapsulation []byte) ([]byte, error) {
+	if rsaPub, ok := pub.(*rsa.PublicKey); ok {
+		key, expected, err := kemOpenRSA(rsaPub, randomness)
+		if err != nil {
+			return nil, err
+		}
+		if !bytes.Equal(expected, encapsulation) {
+			return nil, errors.New("Invalid escrow opening")
+		}
+		return key, nil
+	}
+
+	remote, err := ecdhPublicKey(pub)
+	if err != nil {
+		return nil, err
+	}
+	ephemeral, err := remote.Curve().NewPrivateKey(randomness)
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.Equal(ephemeral.PublicKey().Bytes(), encapsulation) {
+		return nil, errors.New("Invalid escrow opening")
+	}
+	shared, err := ephemeral.ECDH(remote)
+	if err != nil {
+		return nil, err
+	}
+	return deriveKeyEncryptionKey(shared, encapsulation, pcsLabel), nil
+}
+
+func kemOpenRSA(pub *rsa.PublicKey, randomness []byte) (key, encapsulation []byte, err error) {
+	n, err := bigmod.NewModulus(pub.N.Bytes())
+	if err != nil {
+		return nil, nil, err
+	}
+	x, err := bigmod.NewNat().SetBytes(randomness, n)
+	if len(randomness) != n.Size() || err != nil {
+		return nil, nil, errors.New("Invalid escrow opening")
+	}
+
+	encapsulation = bigmod.NewNat().ExpShortVarTime(x, uint(pub.E), n).Bytes(n)
+	return deriveKeyEncryptionKey(randomness, encapsulation, pcsLabel), encapsulation, nil
+}
+
+// kemDecapsulate recovers the key encapsulated by kemEncapsulate with the private key.
+func kemDecapsulate(key crypto.Signer, encapsulation []byte) ([]byte, error) {
+	if rsaKey, ok := key.(*rsa.PrivateKey); ok {
+		n, err := bigmod.NewModulus(rsaKey.N.Bytes())
+		if err != nil {
+			return nil, err
+		}
+		c, err := bigmod.NewNat().SetBytes(encapsulation, n)
+		if len(encapsulation) != n.Size() || err != nil {
+			return nil, errors.New("Invalid encapsulation")
+		}
+		x := bigmod.NewNat().Exp(c, rsaKey.D.FillBytes(make([]byte, n.Size())), n).Bytes(n)
+		return deriveKeyEncryptionKey(x, encapsulation, pcsLabel), nil
+	}
+
+	local, err := ecdhPrivateKey(key)
+	if err != nil {
+		return nil, err
+	}
+	ephemeral, err := local.Curve().NewPublicKey(encapsulation)
+	if err != nil {
+		return nil, err
+	}
+	shared, err := local.ECDH(ephemeral)
+	if err != nil {
+		return nil, err
+	}
+	return deriveKeyEncryptionKey(shared, encapsulation, pcsLabel), nil
+}
+
+// simulateRecipientProof builds a proof of knowledge of the private key of the recipient for a random challenge,
+// without knowing this key. As only the recipient could answer any other challenge, the escrow challenge is free
+// for the sender only once combined with this one: the sender has to build valid rounds, whereas the recipient
+// could have chosen the opened shares and answered the remaining challenge with their key.
+//
+// RSA keys use Guillou-Quisquater proofs, elliptic-curve keys use Schnorr proofs.
+func simulateRecipientProof(pub crypto.PublicKey) (recipientProof, error) {
+	p := recipientProof{Challenge: make([]byte, pcsChallengeSize)}
+	if _, err := rand.Read(p.Challenge); err != nil {
+		return p, err
+	}
+	c := new(big.Int).SetBytes(p.Challenge)
+
+	switch pub := pub.(type) {
+	case *rsa.PublicKey:
+		y, chunks, chunkBits, err := guillouQuisquater(pub)
+		if err != nil {
+			return p, err
+		}
+		e := big.NewInt(int64(pub.E))
+		for i := 0; i < chunks; i++ {
+			z, err := rand.Int(rand.Reader, pub.N)
+			if err != nil {
+				return p, err
+			}
+			// T = z^e / y^c
+			t := new(big.Int).Exp(y, challengeChunk(c, i, chunkBits), pub.N)
+			if t.ModInverse(t, pub.N) == nil {
+				return p, errors.New("Invalid RSA key")
+			}
+			t.Mul(t, new(big.Int).Exp(z, e, pub.N))
+			t.Mod(t, pub.N)
+			p.Commitments = append(p.Commitments, t.Bytes())
+			p.Responses = append(p.Responses, z.Bytes())
+		}
+	case *ecdsa.PublicKey, ed25519.PublicKey:
+		g, q, err := schnorrKey(pub)
+		if err != nil {
+			return p, err
+		}
+		z, err := randomNat(g.order)
+		if err != nil {
+			return p, err
+		}
+		// T = [z]G - [c]Q
+		zG, err := g.base(z.Bytes(g.order))
+		if err != nil {
+			return p, err
+		}
+		c := schnorrChallenge(p.Challenge, g.order)
+		cQ, err := g.mul(q, bigmod.NewNat().ExpandFor(g.order).Sub(c, g.order).Bytes(g.order))
+		if err != nil {
+			return p, err
+		}
+		t, err := g.add(zG, cQ)
+		if err != nil {
+			return p, err
+		}
+		p.Commitments = [][]byte{t}
+		p.Responses = [][]byte{z.Bytes(g.order)}
+	default:
+		return p, errors.New("Unsupported public key")
+	}
+
+	return p, nil
+}
+
+// verifyRecipientProof checks a proof built by simulateRecipientProof, or by the recipient with their key.
+func verifyRecipientProof(pub crypto.PublicKey, p recipientProof) error {
+	c := new(big.Int).SetBytes(p.Challenge)
+	invalid := errors.New("Invalid recipient proof")
+
+	switch pub := pub.(type) {
+	case *rsa.PublicKey:
+		y, chunks, chunkBits, err := guillouQuisquater(pub)
+		if err != nil {
+			return err
+		}
+		if len(p.Commitments) != chunks || len(p.Responses) != chunks {
+			return invalid
+		}
+		e := big.NewInt(int64(pub.E))
+		for i := 0; i < chunks; i++ {
+			t := new(big.Int).SetBytes(p.Commitments[i])
+			z := new(big.Int).SetBytes(p.Responses[i])
+			if t.Sign() <= 0 || t.Cmp(pub.N) >= 0 || z.Sign() <= 0 || z.Cmp(pub.N) >= 0 {
+				return invalid
+			}
+			// z^e = T * y^c
+			expected := new(big.Int).Exp(y, challengeChunk(c, i, chunkBits), pub.N)
+			expected.Mul(expected, t)
+			if z.Exp(z, e, pub.N).Cmp(expected.Mod(expected, pub.N)) != 0 {
+				return invalid
+			}
+		}
+	case *ecdsa.PublicKey, ed25519.PublicKey:
+		g, q, err := schnorrKey(pub)
+		if err != nil {
+			return err
+		}
+		if len(p.Commitments) != 1 || len(p.Responses) != 1 || g.decode(p.Commitments[0]) != nil {
+			return invalid
+		}
+		z, err := bigmod.NewNat().SetBytes(p.Responses[0], g.order)
+		if len(p.Responses[0]) != g.order.Size() || err != nil {
+			return invalid
+		}
+		// [z]G = T + [c]Q
+		zG, err := g.base(z.Bytes(g.order))
+		if err != nil {
+			return err
+		}
+		cQ, err := g.mul(q, schnorrChallenge(p.Challenge, g.order).Bytes(g.order))
+		if err != nil {
+			return err
+		}
+		expected, err := g.add(p.Commitments[0], cQ)
+		if err != nil || !bytes.Equal(zG, expected) {
+			return invalid
+		}
+	default:
+		return errors.New("Unsupported public key")
+	}
+
+	return nil
+}
+
+// schnorrKey returns the group of an elliptic-curve key, and the encoding of its public point.
+func schnorrKey(pub crypto.PublicKey) (*group, []byte, error) {
+	if pub, ok := pub.(ed25519.PublicKey); ok {
+		if ed25519Group.decode(pub) != nil {
+			return nil, nil, errors.New("Invalid Ed25519 public key")
+		}
+		return ed25519Group, pub, nil
+	}
+	return ecdsaGroup(pub.(*ecdsa.PublicKey))
+}
+
+// schnorrChallenge converts a challenge into a scalar, the challenge being smaller than the order of every group.
+func schnorrChallenge(challenge []byte, order *bigmod.Modulus) *bigmod.Nat {
+	c, _ := bigmod.NewNat().SetOverflowingBytes(challenge, order)
+	return c
+}
+
+// gqDomain separates the Guillou-Quisquater public values from other hashes.
+var gqDomain = []byte("dfss guillou-quisquater")
+
+// guillouQuisquater returns the public value y of the Guillou-Quisquater proofs for an RSA key, y^d being only
+// known to the owner of the key. The challenge is split in chunks smaller than the prime exponent e.
+func guillouQuisquater(pub *rsa.PublicKey) (y *big.Int, chunks, chunkBits int, err error) {
+	e := big.NewInt(int64(pub.E))
+	if pub.E < 3 || !e.ProbablyPrime(20) {
+		return nil, 0, 0, errors.New("Unsupported RSA exponent")
+	}
+	chunkBits = e.BitLen() - 1
+	chunks = (pcsRounds + chunkBits - 1) / chunkBits
+
+	var expanded []byte
+	for i := 0; len(expanded) < (pub.N.BitLen()+7)/8+16; i++ {
+		h := sha512.New()
+		_, _ = h.Write(lengthPrefixed(gqDomain))
+		_, _ = h.Write([]byte{byte(i)})
+		_, _ = h.Write(pub.N.Bytes())
+		expanded = h.Sum(expanded)
+	}
+	y = new(big.Int).SetBytes(expanded)
+	return y.Mod(y, pub.N), chunks, chunkBits, nil
+}
+
+func challengeChunk(c *big.Int, i, chunkBits int) *big.Int {
+	chunk := new(big.Int).Rsh(c, uint(i*chunkBits))
+	mask := new(big.Int).Lsh(big.NewInt(1), uint(chunkBits))
+	return chunk.Mod(chunk, mask)
+}
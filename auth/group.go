@@ -0,0 +1,221 @@
+package auth
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"errors"
+	"io"
+
+	"filippo.io/bigmod"
+	"filippo.io/edwards25519"
+	"filippo.io/nistec"
+)
+
+// group is a prime-order elliptic-curve group, handling points as their canonical encodings and scalars as
+// big-endian integers of order.Size() bytes.
+//
+// The arithmetic is delegated to filippo.io/nistec (exported from crypto/internal/fips140/nistec) and
+// filippo.io/edwards25519 (exported from crypto/internal/fips140/edwards25519), both constant-time.
+type group struct {
+	order *bigmod.Modulus
+
+	base func(scalar []byte) ([]byte, error)        // [scalar]G
+	mul  func(point, scalar []byte) ([]byte, error) // [scalar]P
+	add  func(p, q []byte) ([]byte, error)          // P + Q
+
+	// decode returns an error if the point is not the canonical encoding of a point other than the identity.
+	decode func(point []byte) error
+}
+
+var (
+	p256Group       = nistGroup(nistec.NewP256Point, elliptic.P256())
+	p384Group       = nistGroup(nistec.NewP384Point, elliptic.P384())
+	p521Group       = nistGroup(nistec.NewP521Point, elliptic.P521())
+	ed25519Group    = newEd25519Group()
+	errInvalidPoint = errors.New("Invalid elliptic-curve point")
+)
+
+// ecdsaGroup returns the group of the curve of an ECDSA key, and the encoding of the public point in this group.
+func ecdsaGroup(pub *ecdsa.PublicKey) (*group, []byte, error) {
+	var g *group
+	switch pub.Curve {
+	case elliptic.P256():
+		g = p256Group
+	case elliptic.P384():
+		g = p384Group
+	case elliptic.P521():
+		g = p521Group
+	default:
+		return nil, nil, errors.New("Unsupported elliptic curve")
+	}
+
+	key, err := pub.ECDH()
+	if err != nil {
+		return nil, nil, err
+	}
+	return g, key.Bytes(), nil
+}
+
+// nistPoint is the interface of the points of filippo.io/nistec.
+type nistPoint[P any] interface {
+	Bytes() []byte
+	SetBytes([]byte) (P, error)
+	Add(P, P) P
+	ScalarMult(P, []byte) (P, error)
+	ScalarBaseMult([]byte) (P, error)
+}
+
+func nistGroup[P nistPoint[P]](newPoint func() P, curve elliptic.Curve) *group {
+	parse := func(data []byte) (P, error) {
+		return newPoint().SetBytes(data)
+	}
+
+	return &group{
+		order: mustModulus(curve.Params().N.Bytes()),
+		base: func(scalar []byte) ([]byte, error) {
+			p, err := newPoint().ScalarBaseMult(scalar)
+			if err != nil {
+				return nil, err
+			}
+			return p.Bytes(), nil
+		},
+		mul: func(point, scalar []byte) ([]byte, error) {
+			q, err := parse(point)
+			if err != nil {
+				return nil, err
+			}
+			p, err := newPoint().ScalarMult(q, scalar)
+			if err != nil {
+				return nil, err
+			}
+			return p.Bytes(), nil
+		},
+		add: func(a, b []byte) ([]byte, error) {
+			p, err := parse(a)
+			if err != nil {
+				return nil, err
+			}
+			q, err := parse(b)
+			if err != nil {
+				return nil, err
+			}
+			return newPoint().Add(p, q).Bytes(), nil
+		},
+		decode: func(point []byte) error {
+			// Only uncompressed encodings are canonical, the identity being encoded as a single byte
+			p, err := parse(point)
+			if err != nil || len(point) == 1 || len(p.Bytes()) != len(point) {
+				return errInvalidPoint
+			}
+			return nil
+		},
+	}
+}
+
+// edwardsL is the order of the base point of edwards25519, in big-endian
+var edwardsL = []byte{
+	0x10, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+	0x14, 0xde, 0xf9, 0xde, 0xa2, 0xf7, 0x9c, 0xd6, 0x58, 0x12, 0x63, 0x1a, 0x5c, 0xf5, 0xd3, 0xed,
+}
+
+func newEd25519Group() *group {
+	return &group{
+		order: mustModulus(edwardsL),
+		base: func(scalar []byte) ([]byte, error) {
+			s, err := edwardsScalar(scalar)
+			if err != nil {
+				return nil, err
+			}
+			return new(edwards25519.Point).ScalarBaseMult(s).Bytes(), nil
+		},
+		mul: func(point, scalar []byte) ([]byte, error) {
+			q, err := new(edwards25519.Point).SetBytes(point)
+			if err != nil {
+				return nil, err
+			}
+			s, err := edwardsScalar(scalar)
+			if err != nil {
+				return nil, err
+			}
+			return new(edwards25519.Point).ScalarMult(s, q).Bytes(), nil
+		},
+		add: func(a, b []byte) ([]byte, error) {
+			p, err := new(edwards25519.Point).SetBytes(a)
+			if err != nil {
+				return nil, err
+			}
+			q, err := new(edwards25519.Point).SetBytes(b)
+			if err != nil {
+				return nil, err
+			}
+			return new(edwards25519.Point).Add(p, q).Bytes(), nil
+		},
+		decode: func(point []byte) error {
+			// SetBytes accepts non-canonical encodings, that are rejected here
+			p, err := new(edwards25519.Point).SetBytes(point)
+			if err != nil || p.Equal(edwards25519.NewIdentityPoint()) == 1 || string(p.Bytes()) != string(point) {
+				return errInvalidPoint
+			}
+			return nil
+		},
+	}
+}
+
+// edwardsScalar converts a big-endian scalar of the edwards25519 group into the little-endian form of the library.
+func edwardsScalar(scalar []byte) (*edwards25519.Scalar, error) {
+	if len(scalar) != 32 {
+		return nil, errors.New("Invalid Ed25519 scalar")
+	}
+	return edwards25519.NewScalar().SetCanonicalBytes(reverse(scalar))
+}
+
+// reverse returns the bytes in reverse order, converting between big-endian and little-endian.
+func reverse(data []byte) []byte {
+	res := make([]byte, len(data))
+	for i := range data {
+		res[len(data)-1-i] = data[i]
+	}
+	return res
+}
+
+func mustModulus(b []byte) *bigmod.Modulus {
+	m, err := bigmod.NewModulus(b)
+	if err != nil {
+		panic(err)
+	}
+	return m
+}
+
+// randomNat returns a uniformly random integer in [1, m-1].
+func randomNat(m *bigmod.Modulus) (*bigmod.Nat, error) {
+	buf := make([]byte, m.Size())
+	excess := uint(m.Size()*8 - m.BitLen())
+	for {
+		if _, err := io.ReadFull(rand.Reader, buf); err != nil {
+			return nil, err
+		}
+		buf[0] &= byte(0xff >> excess)
+		n, err := bigmod.NewNat().SetBytes(buf, m)
+		if err == nil && n.IsZero() == 0 {
+			return n, nil
+		}
+	}
+}
+
+// inverse returns a^-1 mod m, or false if a is not invertible.
+// The variable-time inversion is blinded by a random factor, so that the timing does not depend on a.
+func inverse(a *bigmod.Nat, m *bigmod.Modulus) (*bigmod.Nat, bool, error) {
+	u, err := randomNat(m)
+	if err != nil {
+		return nil, false, err
+	}
+
+	// a^-1 = u * (a * u)^-1
+	blinded := bigmod.NewNat().ExpandFor(m).Add(a, m).Mul(u, m)
+	res, ok := bigmod.NewNat().InverseVarTime(blinded, m)
+	if !ok {
+		return nil, false, nil
+	}
+	return res.Mul(u, m), true, nil
+}
@@ -0,0 +1,192 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/asn1"
+	"errors"
+	"fmt"
+
+	"filippo.io/edwards25519"
+)
+
+// Supported types of private keys
+const (
+	KeyTypeRSA       = "rsa"
+	KeyTypeECDSAP256 = "ecdsa-p256"
+	KeyTypeECDSAP384 = "ecdsa-p384"
+	KeyTypeEd25519   = "ed25519"
+)
+
+// KeyTypes lists the supported types of private keys, the first one being the default one.
+var KeyTypes = []string{KeyTypeRSA, KeyTypeECDSAP256, KeyTypeECDSAP384, KeyTypeEd25519}
+
+// GenerateKey builds a private key of the given type from default random.
+// The size in bits is only used for RSA keys.
+func GenerateKey(keyType string, bits int) (crypto.Signer, error) {
+	switch keyType {
+	case KeyTypeRSA:
+		return GeneratePrivateKey(bits)
+	case KeyTypeECDSAP256:
+		return ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	case KeyTypeECDSAP384:
+		return ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	case KeyTypeEd25519:
+		_, key, err := ed25519.GenerateKey(rand.Reader)
+		return key, err
+	}
+	return nil, fmt.Errorf("Unsupported key type %q", keyType)
+}
+
+// signHash signs a SHA-512 hash with the private key.
+// RSA keys use PKCS#1 v1.5, ECDSA keys produce ASN.1 signatures, and Ed25519 keys sign the hash as a message.
+func signHash(key crypto.Signer, hash []byte) ([]byte, error) {
+	if _, ok := key.Public().(ed25519.PublicKey); ok {
+		return key.Sign(rand.Reader, hash, crypto.Hash(0))
+	}
+	return key.Sign(rand.Reader, hash, crypto.SHA512)
+}
+
+// verifyHash verifies a signature produced by signHash.
+func verifyHash(pub crypto.PublicKey, hash, signature []byte) error {
+	switch pub := pub.(type) {
+	case *rsa.PublicKey:
+		return rsa.VerifyPKCS1v15(pub, crypto.SHA512, hash, signature)
+	case *ecdsa.PublicKey:
+		if !ecdsa.VerifyASN1(pub, hash, signature) {
+			return errors.New("Invalid ECDSA signature")
+		}
+		return nil
+	case ed25519.PublicKey:
+		if !ed25519.Verify(pub, hash, signature) {
+			return errors.New("Invalid Ed25519 signature")
+		}
+		return nil
+	}
+	return errors.New("Unsupported public key")
+}
+
+// encryptedKey is the wire format of a symmetric key encrypted with an elliptic-curve public key.
+type encryptedKey struct {
+	Ephemeral  []byte // Ephemeral public key of the key agreement
+	Ciphertext []byte // Encrypted key, see encryptGCM
+}
+
+// encryptKey encrypts a small secret, typically a symmetric key, for the owner of the public key.
+//
+// RSA keys use OAEP with SHA-256. Elliptic-curve keys use an ephemeral Diffie-Hellman key agreement, the shared
+// secret being derived with HMAC-SHA512 into an AES-256-GCM key. Ed25519 keys are converted to X25519 ones.
+func encryptKey(pub crypto.PublicKey, secret, label []byte) ([]byte, error) {
+	if rsaPub, ok := pub.(*rsa.PublicKey); ok {
+		return rsa.EncryptOAEP(sha256.New(), rand.Reader, rsaPub, secret, label)
+	}
+
+	remote, err := ecdhPublicKey(pub)
+	if err != nil {
+		return nil, err
+	}
+
+	ephemeral, err := remote.Curve().GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+
+	shared, err := ephemeral.ECDH(remote)
+	if err != nil {
+		return nil, err
+	}
+
+	e := encryptedKey{Ephemeral: ephemeral.PublicKey().Bytes()}
+	e.Ciphertext, err = encryptGCM(deriveKeyEncryptionKey(shared, e.Ephemeral, label), secret, label)
+	if err != nil {
+		return nil, err
+	}
+
+	return asn1.Marshal(e)
+}
+
+// decryptKey decrypts a secret encrypted with encryptKey.
+func decryptKey(key crypto.Signer, data, label []byte) ([]byte, error) {
+	if rsaKey, ok := key.(*rsa.PrivateKey); ok {
+		return rsa.DecryptOAEP(sha256.New(), rand.Reader, rsaKey, data, label)
+	}
+
+	local, err := ecdhPrivateKey(key)
+	if err != nil {
+		return nil, err
+	}
+
+	e := new(encryptedKey)
+	rest, err := asn1.Unmarshal(data, e)
+	if err != nil {
+		return nil, err
+	}
+	if len(rest) > 0 {
+		return nil, errors.New("Trailing data after encrypted key")
+	}
+
+	ephemeral, err := local.Curve().NewPublicKey(e.Ephemeral)
+	if err != nil {
+		return nil, err
+	}
+
+	shared, err := local.ECDH(ephemeral)
+	if err != nil {
+		return nil, err
+	}
+
+	return decryptGCM(deriveKeyEncryptionKey(shared, e.Ephemeral, label), e.Ciphertext, label)
+}
+
+func deriveKeyEncryptionKey(shared, ephemeral, label []byte) []byte {
+	mac := hmac.New(sha512.New, shared)
+	_, _ = mac.Write(lengthPrefixed(ephemeral))
+	_, _ = mac.Write(lengthPrefixed(label))
+	return mac.Sum(nil)[:pcsKeySize]
+}
+
+// ecdhPublicKey converts a signature public key into a key agreement one.
+func ecdhPublicKey(pub crypto.PublicKey) (*ecdh.PublicKey, error) {
+	switch pub := pub.(type) {
+	case *ecdsa.PublicKey:
+		return pub.ECDH()
+	case ed25519.PublicKey:
+		u, err := edwardsToMontgomery(pub)
+		if err != nil {
+			return nil, err
+		}
+		return ecdh.X25519().NewPublicKey(u)
+	}
+	return nil, errors.New("Unsupported public key")
+}
+
+// ecdhPrivateKey converts a signature private key into a key agreement one.
+func ecdhPrivateKey(key crypto.Signer) (*ecdh.PrivateKey, error) {
+	switch key := key.(type) {
+	case *ecdsa.PrivateKey:
+		return key.ECDH()
+	case ed25519.PrivateKey:
+		// The X25519 scalar is the one used by Ed25519, see RFC 8032 section 5.1.5
+		h := sha512.Sum512(key.Seed())
+		return ecdh.X25519().NewPrivateKey(h[:32])
+	}
+	return nil, errors.New("Unsupported private key")
+}
+
+// edwardsToMontgomery converts an Ed25519 public key into the X25519 public key of the same scalar,
+// using the birational map u = (1 + y) / (1 - y) defined in RFC 7748.
+func edwardsToMontgomery(pub ed25519.PublicKey) ([]byte, error) {
+	p, err := new(edwards25519.Point).SetBytes(pub)
+	if err != nil || p.Equal(edwards25519.NewIdentityPoint()) == 1 {
+		return nil, errors.New("Invalid Ed25519 public key")
+	}
+	return p.BytesMontgomery(), nil
+}
@@ -0,0 +1,97 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/x509"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newTypedActor(t *testing.T, keyType, cn string, ca *x509.Certificate, caKey crypto.Signer) (crypto.Signer, *x509.Certificate) {
+	key, err := GenerateKey(keyType, 1024)
+	assert.Nil(t, err)
+
+	var certPem []byte
+	if ca == nil {
+		certPem, err = GetSelfSignedCertificate(1, 0, "", "", "", cn, key)
+	} else {
+		csrPem, _ := GetCertificateRequest("", "", "", cn, key)
+		csr, _ := PEMToCertificateRequest(csrPem)
+		certPem, err = GetCertificate(1, 1, csr, ca, caKey)
+	}
+	assert.Nil(t, err)
+
+	cert, err := PEMToCertificate(certPem)
+	assert.Nil(t, err)
+	return key, cert
+}
+
+func TestGenerateKey(t *testing.T) {
+	for _, keyType := range KeyTypes {
+		key, err := GenerateKey(keyType, 1024)
+		assert.Nil(t, err, keyType)
+
+		res, err := PrivateKeyToEncryptedPEM(key, "password")
+		assert.Nil(t, err, keyType)
+		key2, err := EncryptedPEMToPrivateKey(res, "password")
+		assert.Nil(t, err, keyType)
+		assert.True(t, reflect.DeepEqual(key, key2), keyType)
+	}
+
+	_, err := GenerateKey("dsa", 1024)
+	assert.NotNil(t, err)
+}
+
+func TestKeyTypes(t *testing.T) {
+	for _, keyType := range KeyTypes {
+		caKey, ca := newTypedActor(t, keyType, "ca", nil, nil)
+		sKey, sCert := newTypedActor(t, keyType, "sender", ca, caKey)
+		rKey, rCert := newTypedActor(t, keyType, "recipient", ca, caKey)
+		tKey, tCert := newTypedActor(t, keyType, "ttp", ca, caKey)
+
+		assert.Nil(t, CheckCertificate(sCert, ca, GetCertificateHash(sCert)), keyType)
+
+		// Seals
+		s := TestStructure{FieldA: 5}
+		seal, err := SignStructure(caKey, s)
+		assert.Nil(t, err, keyType)
+		valid, err := VerifyStructure(ca, s, seal)
+		assert.True(t, valid, keyType)
+		assert.Nil(t, err, keyType)
+		valid, _ = VerifyStructure(sCert, s, seal)
+		assert.False(t, valid, keyType)
+
+		// Private contract signatures
+		statement := []byte("statement")
+		label := []byte("label")
+		data, err := CreatePCS(sKey, sCert, statement, label, rCert, tCert)
+		assert.Nil(t, err, keyType)
+
+		_, err = VerifyPCS(rKey, data, statement, label, tCert)
+		assert.Nil(t, err, keyType)
+		_, err = VerifyPCS(rKey, data, []byte("other statement"), label, tCert)
+		assert.NotNil(t, err, keyType)
+		_, err = VerifyPCS(tKey, data, statement, label, tCert)
+		assert.NotNil(t, err, keyType)
+
+		_, signature, err := ConvertPCS(tKey, data, statement, label)
+		assert.Nil(t, err, keyType)
+		assert.Nil(t, VerifyStatement(sCert, statement, signature), keyType)
+		assert.NotNil(t, VerifyStatement(rCert, statement, signature), keyType)
+	}
+}
+
+func TestMixedKeyTypes(t *testing.T) {
+	sKey, sCert := newTypedActor(t, KeyTypeEd25519, "sender", nil, nil)
+	rKey, rCert := newTypedActor(t, KeyTypeECDSAP384, "recipient", nil, nil)
+	tKey, tCert := newTypedActor(t, KeyTypeRSA, "ttp", nil, nil)
+
+	data, err := CreatePCS(sKey, sCert, []byte("statement"), []byte("label"), rCert, tCert)
+	assert.Nil(t, err)
+	_, err = VerifyPCS(rKey, data, []byte("statement"), []byte("label"), tCert)
+	assert.Nil(t, err)
+	_, _, err = ConvertPCS(tKey, data, []byte("statement"), []byte("label"))
+	assert.Nil(t, err)
+}
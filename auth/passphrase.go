@@ -0,0 +1,120 @@
+package auth
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/asn1"
+	"errors"
+	"io"
+
+	"golang.org/x/crypto/argon2"
+)
+
+// Argon2id parameters used to derive keys from passphrases, as recommended by RFC 9106.
+// They are stored in each encrypted envelope, so that they can be increased without breaking existing files.
+var (
+	Argon2Time    = 3
+	Argon2Memory  = 64 * 1024 // KiB
+	Argon2Threads = 4
+)
+
+// Upper bounds of the accepted Argon2id parameters, to avoid resource exhaustion with crafted files.
+const (
+	maxArgon2Time    = 16
+	maxArgon2Memory  = 1024 * 1024 // KiB
+	maxArgon2Threads = 64
+)
+
+// passphraseMagic prefixes every envelope produced by EncryptWithPassphrase.
+var passphraseMagic = []byte("DFSS\x00ENC\x01")
+
+// passphraseEnvelope is the wire format of data encrypted with a passphrase.
+type passphraseEnvelope struct {
+	KDF        string // Key derivation function, only "argon2id" is supported
+	Time       int
+	Memory     int // in KiB
+	Threads    int
+	Salt       []byte
+	Ciphertext []byte // AES-256-GCM ciphertext, see encryptGCM
+}
+
+// EncryptWithPassphrase encrypts data with a key derived from the passphrase.
+//
+// The key is derived with Argon2id and a random salt, and the data is encrypted with AES-256-GCM.
+// The derivation parameters are authenticated along with the ciphertext.
+func EncryptWithPassphrase(data []byte, passphrase string) ([]byte, error) {
+	e := passphraseEnvelope{
+		KDF:     "argon2id",
+		Time:    Argon2Time,
+		Memory:  Argon2Memory,
+		Threads: Argon2Threads,
+		Salt:    make([]byte, 16),
+	}
+	if _, err := io.ReadFull(rand.Reader, e.Salt); err != nil {
+		return nil, err
+	}
+
+	header, err := asn1.Marshal(e)
+	if err != nil {
+		return nil, err
+	}
+
+	e.Ciphertext, err = encryptGCM(e.deriveKey(passphrase), data, header)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := asn1.Marshal(e)
+	if err != nil {
+		return nil, err
+	}
+	return append(append([]byte{}, passphraseMagic...), res...), nil
+}
+
+// DecryptWithPassphrase decrypts data encrypted with EncryptWithPassphrase.
+//
+// In case of wrong passphrase, the returned error will be equals to x509.IncorrectPasswordError
+func DecryptWithPassphrase(data []byte, passphrase string) ([]byte, error) {
+	if !IsPassphraseEncrypted(data) {
+		return nil, errors.New("Data is not a valid encrypted envelope")
+	}
+
+	e := passphraseEnvelope{}
+	rest, err := asn1.Unmarshal(data[len(passphraseMagic):], &e)
+	if err != nil {
+		return nil, err
+	}
+	if len(rest) > 0 {
+		return nil, errors.New("Trailing data after encrypted envelope")
+	}
+
+	if e.KDF != "argon2id" {
+		return nil, errors.New("Unsupported key derivation function " + e.KDF)
+	}
+	if e.Time < 1 || e.Time > maxArgon2Time || e.Memory < 8*e.Threads || e.Memory > maxArgon2Memory || e.Threads < 1 || e.Threads > maxArgon2Threads {
+		return nil, errors.New("Invalid key derivation parameters")
+	}
+
+	ciphertext := e.Ciphertext
+	e.Ciphertext = nil
+	header, err := asn1.Marshal(e)
+	if err != nil {
+		return nil, err
+	}
+
+	plaintext, err := decryptGCM(e.deriveKey(passphrase), ciphertext, header)
+	if err != nil {
+		return nil, x509.IncorrectPasswordError
+	}
+	return plaintext, nil
+}
+
+// IsPassphraseEncrypted tests whether data has been produced by EncryptWithPassphrase.
+func IsPassphraseEncrypted(data []byte) bool {
+	return bytes.HasPrefix(data, passphraseMagic)
+}
+
+func (e *passphraseEnvelope) deriveKey(passphrase string) []byte {
+	return argon2.IDKey([]byte(passphrase), e.Salt, uint32(e.Time), uint32(e.Memory), uint8(e.Threads), pcsKeySize)
+}
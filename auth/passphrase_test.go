@@ -0,0 +1,53 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEncryptWithPassphrase(t *testing.T) {
+	data := []byte("secret data")
+
+	res, err := EncryptWithPassphrase(data, "passphrase")
+	assert.Nil(t, err)
+	assert.True(t, IsPassphraseEncrypted(res))
+	assert.False(t, IsPassphraseEncrypted(data))
+
+	plain, err := DecryptWithPassphrase(res, "passphrase")
+	assert.Nil(t, err)
+	assert.Equal(t, data, plain)
+
+	_, err = DecryptWithPassphrase(res, "bad passphrase")
+	assert.Equal(t, x509.IncorrectPasswordError, err)
+
+	// Tampered envelope
+	res[len(res)-1]++
+	_, err = DecryptWithPassphrase(res, "passphrase")
+	assert.NotNil(t, err)
+
+	_, err = DecryptWithPassphrase(data, "passphrase")
+	assert.NotNil(t, err)
+}
+
+func TestLegacyPEM(t *testing.T) {
+	key, _ := GeneratePrivateKey(512)
+	block, err := x509.EncryptPEMBlock(rand.Reader, "RSA PRIVATE KEY", x509.MarshalPKCS1PrivateKey(key), []byte("password"), x509.PEMCipherAES256)
+	assert.Nil(t, err)
+	legacy := pem.EncodeToMemory(block)
+
+	assert.True(t, IsLegacyPEM(legacy))
+	assert.True(t, IsPEMEncrypted(legacy))
+	key2, err := EncryptedPEMToPrivateKey(legacy, "password")
+	assert.Nil(t, err)
+	assert.True(t, key.Equal(key2))
+
+	current, _ := PrivateKeyToEncryptedPEM(key, "password")
+	assert.False(t, IsLegacyPEM(current))
+	assert.True(t, IsPEMEncrypted(current))
+	assert.False(t, IsLegacyPEM(PrivateKeyToPEM(key)))
+	assert.True(t, IsLegacyPEM(pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})))
+}
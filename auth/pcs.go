@@ -0,0 +1,212 @@
+package auth
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha512"
+	"crypto/x509"
+	"encoding/asn1"
+	"errors"
+	"io"
+)
+
+// pcsKeySize is the size of the symmetric keys used in a private contract signature.
+const pcsKeySize = 32
+
+// pcsLabel is the label used to encrypt the keys of a private contract signature.
+var pcsLabel = []byte("dfss private contract signature")
+
+// pcs is the wire format of a private contract signature (see CreatePCS).
+type pcs struct {
+	Certificate  []byte // DER-encoded certificate of the signer
+	RecipientKey []byte // Tag key, encrypted for the designated verifier
+	Escrow       []byte // Verifiable escrow of the signature of the statement (empty if there is no TTP)
+	Tag          []byte // HMAC-SHA512 of the label and the escrow, computed with the tag key
+}
+
+// CreatePCS creates a private contract signature (PCS) of the provided statement, as presented by Mukhamedov and Ryan.
+//
+// The real signature of the statement (see SignStatement) is placed in a verifiable escrow for the TTP, so that only
+// the TTP is able to convert the PCS into a universally verifiable signature. The escrow proves to the designated
+// recipient that the TTP will be able to do so, but it could have been built by the recipient alone:
+// the PCS cannot be used to convince anyone else.
+// The escrow and the label (that should bind the PCS to its context) are also authenticated with a tag that can
+// only be checked by the designated recipient.
+//
+// If ttp is nil, no escrow is created and the PCS cannot be converted.
+func CreatePCS(key crypto.Signer, cert *x509.Certificate, statement, label []byte, recipient, ttp *x509.Certificate) ([]byte, error) {
+	p := pcs{Certificate: cert.Raw}
+
+	if ttp != nil {
+		signature, err := SignStatement(key, statement)
+		if err != nil {
+			return nil, err
+		}
+
+		p.Escrow, err = createEscrow(cert, signature, statement, label, recipient.PublicKey, ttp.PublicKey)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	tagKey := make([]byte, pcsKeySize)
+	if _, err := io.ReadFull(rand.Reader, tagKey); err != nil {
+		return nil, err
+	}
+
+	var err error
+	p.RecipientKey, err = encryptKey(recipient.PublicKey, tagKey, pcsLabel)
+	if err != nil {
+		return nil, err
+	}
+
+	p.Tag = computeTag(tagKey, label, p.Escrow)
+	return asn1.Marshal(p)
+}
+
+// VerifyPCS checks a private contract signature as its designated recipient, and returns the certificate of the signer.
+//
+// If ttp is not nil, the escrow is checked too: a valid PCS can then always be converted by this TTP.
+// The caller MUST check that the returned certificate is the expected one.
+func VerifyPCS(key crypto.Signer, data, statement, label []byte, ttp *x509.Certificate) (*x509.Certificate, error) {
+	p, cert, err := parsePCS(data)
+	if err != nil {
+		return nil, err
+	}
+
+	tagKey, err := decryptKey(key, p.RecipientKey, pcsLabel)
+	if err != nil {
+		return nil, errors.New("Unable to decrypt tag key")
+	}
+
+	if !hmac.Equal(computeTag(tagKey, label, p.Escrow), p.Tag) {
+		return nil, errors.New("Invalid tag")
+	}
+
+	if ttp != nil {
+		if len(p.Escrow) == 0 {
+			return nil, errors.New("No escrow for the ttp")
+		}
+		if err = verifyEscrow(p.Escrow, cert, statement, label, key.Public(), ttp.PublicKey); err != nil {
+			return nil, err
+		}
+	}
+
+	return cert, nil
+}
+
+// ConvertPCS opens the escrow of a private contract signature as the TTP, and converts it into the real signature
+// of the statement. It returns the certificate of the signer and the signature, that can be checked with VerifyStatement.
+//
+// The caller MUST check that the returned certificate is the expected one.
+func ConvertPCS(key crypto.Signer, data, statement, label []byte) (*x509.Certificate, []byte, error) {
+	p, cert, err := parsePCS(data)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if len(p.Escrow) == 0 {
+		return nil, nil, errors.New("No escrow for the ttp")
+	}
+
+	signature, err := convertEscrow(key, p.Escrow, cert, statement, label)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return cert, signature, nil
+}
+
+// SignStatement signs the SHA-512 hash of the provided statement with the private key.
+// RSA keys use PKCS#1 v1.5, ECDSA keys produce ASN.1 signatures, and Ed25519 keys sign the hash as a message.
+func SignStatement(key crypto.Signer, statement []byte) ([]byte, error) {
+	hash := sha512.Sum512(statement)
+	return signHash(key, hash[:])
+}
+
+// VerifyStatement verifies the signature of the statement according to the provided certificate.
+// See SignStatement for protocol definition.
+func VerifyStatement(cert *x509.Certificate, statement, signature []byte) error {
+	hash := sha512.Sum512(statement)
+	return verifyHash(cert.PublicKey, hash[:], signature)
+}
+
+func parsePCS(data []byte) (*pcs, *x509.Certificate, error) {
+	p := new(pcs)
+	rest, err := asn1.Unmarshal(data, p)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(rest) > 0 {
+		return nil, nil, errors.New("Trailing data after pcs")
+	}
+
+	cert, err := x509.ParseCertificate(p.Certificate)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return p, cert, nil
+}
+
+func computeTag(tagKey, label, escrow []byte) []byte {
+	mac := hmac.New(sha512.New, tagKey)
+	_, _ = mac.Write(lengthPrefixed(label))
+	_, _ = mac.Write(lengthPrefixed(escrow))
+	return mac.Sum(nil)
+}
+
+// escrowData binds the escrow to both the statement and the label, so that it cannot be moved to another context.
+func escrowData(statement, label []byte) []byte {
+	return append(lengthPrefixed(statement), lengthPrefixed(label)...)
+}
+
+func lengthPrefixed(data []byte) []byte {
+	var b bytes.Buffer
+	l := len(data)
+	b.Write([]byte{byte(l >> 24), byte(l >> 16), byte(l >> 8), byte(l)})
+	b.Write(data)
+	return b.Bytes()
+}
+
+// encryptGCM encrypts the plaintext with AES-256-GCM, the nonce is prepended to the ciphertext.
+func encryptGCM(key, plaintext, additionalData []byte) ([]byte, error) {
+	aead, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, err = io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+
+	return aead.Seal(nonce, nonce, plaintext, additionalData), nil
+}
+
+// decryptGCM decrypts a ciphertext produced by encryptGCM.
+func decryptGCM(key, ciphertext, additionalData []byte) ([]byte, error) {
+	aead, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(ciphertext) < aead.NonceSize() {
+		return nil, errors.New("Ciphertext too short")
+	}
+
+	n := aead.NonceSize()
+	return aead.Open(nil, ciphertext[:n], ciphertext[n:], additionalData)
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,133 @@
+package auth
+
+import (
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/asn1"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newPCSActor(t *testing.T, cn string) (*rsa.PrivateKey, *x509.Certificate) {
+	key, err := GeneratePrivateKey(1024)
+	assert.Nil(t, err)
+	selfSigned, err := GetSelfSignedCertificate(1, 0, "", "", "", cn, key)
+	assert.Nil(t, err)
+	cert, err := PEMToCertificate(selfSigned)
+	assert.Nil(t, err)
+	return key, cert
+}
+
+func TestPCS(t *testing.T) {
+	sKey, sCert := newPCSActor(t, "sender")
+	rKey, rCert := newPCSActor(t, "recipient")
+	tKey, tCert := newPCSActor(t, "ttp")
+
+	statement := []byte("statement")
+	label := []byte("label")
+
+	data, err := CreatePCS(sKey, sCert, statement, label, rCert, tCert)
+	assert.Nil(t, err)
+
+	// Designated verifier
+	cert, err := VerifyPCS(rKey, data, statement, label, tCert)
+	assert.Nil(t, err)
+	assert.Equal(t, sCert.Raw, cert.Raw)
+
+	_, err = VerifyPCS(rKey, data, statement, []byte("other label"), tCert)
+	assert.NotNil(t, err)
+	_, err = VerifyPCS(rKey, data, []byte("other statement"), label, tCert)
+	assert.NotNil(t, err)
+	_, err = VerifyPCS(rKey, data, statement, label, rCert)
+	assert.NotNil(t, err)
+	_, err = VerifyPCS(tKey, data, statement, label, tCert)
+	assert.NotNil(t, err)
+	_, err = VerifyPCS(sKey, data, statement, label, tCert)
+	assert.NotNil(t, err)
+
+	// Conversion by the TTP
+	cert, signature, err := ConvertPCS(tKey, data, statement, label)
+	assert.Nil(t, err)
+	assert.Equal(t, sCert.Raw, cert.Raw)
+	assert.Nil(t, VerifyStatement(sCert, statement, signature))
+
+	_, _, err = ConvertPCS(tKey, data, []byte("other statement"), label)
+	assert.NotNil(t, err)
+	_, _, err = ConvertPCS(tKey, data, statement, []byte("other label"))
+	assert.NotNil(t, err)
+	_, _, err = ConvertPCS(rKey, data, statement, label)
+	assert.NotNil(t, err)
+
+	// Corrupted data
+	_, err = VerifyPCS(rKey, data[:len(data)-1], statement, label, tCert)
+	assert.NotNil(t, err)
+}
+
+func TestPCSInvalidEscrow(t *testing.T) {
+	sKey, sCert := newPCSActor(t, "sender")
+	rKey, rCert := newPCSActor(t, "recipient")
+	tKey, tCert := newPCSActor(t, "ttp")
+
+	statement := []byte("statement")
+	label := []byte("label")
+
+	// The escrowed signature is not the one of the statement
+	signature, err := SignStatement(sKey, []byte("other statement"))
+	assert.Nil(t, err)
+	escrowed, err := createEscrow(sCert, signature, statement, label, rCert.PublicKey, tCert.PublicKey)
+	assert.Nil(t, err)
+
+	tagKey := make([]byte, pcsKeySize)
+	p := pcs{Certificate: sCert.Raw, Escrow: escrowed, Tag: computeTag(tagKey, label, escrowed)}
+	p.RecipientKey, err = encryptKey(rCert.PublicKey, tagKey, pcsLabel)
+	assert.Nil(t, err)
+	data, err := asn1.Marshal(p)
+	assert.Nil(t, err)
+
+	// Rejected by the recipient, who would otherwise alert the TTP with an unconvertible promise
+	_, err = VerifyPCS(rKey, data, statement, label, tCert)
+	assert.NotNil(t, err)
+	_, _, err = ConvertPCS(tKey, data, statement, label)
+	assert.NotNil(t, err)
+
+	// Any altered share changes the challenge
+	signature, err = SignStatement(sKey, statement)
+	assert.Nil(t, err)
+	escrowed, err = createEscrow(sCert, signature, statement, label, rCert.PublicKey, tCert.PublicKey)
+	assert.Nil(t, err)
+	assert.Nil(t, verifyEscrow(escrowed, sCert, statement, label, rCert.PublicKey, tCert.PublicKey))
+
+	e, err := parseEscrow(escrowed)
+	assert.Nil(t, err)
+	e.Rounds[0].Shares[1].Ciphertext[0] ^= 1
+	altered, err := asn1.Marshal(*e)
+	assert.Nil(t, err)
+	assert.NotNil(t, verifyEscrow(altered, sCert, statement, label, rCert.PublicKey, tCert.PublicKey))
+
+	// A valid escrow only convinces its designated recipient
+	assert.NotNil(t, verifyEscrow(escrowed, sCert, statement, label, tCert.PublicKey, tCert.PublicKey))
+}
+
+func TestPCSWithoutTTP(t *testing.T) {
+	sKey, sCert := newPCSActor(t, "sender")
+	rKey, rCert := newPCSActor(t, "recipient")
+
+	data, err := CreatePCS(sKey, sCert, []byte("statement"), []byte("label"), rCert, nil)
+	assert.Nil(t, err)
+
+	_, err = VerifyPCS(rKey, data, []byte("statement"), []byte("label"), nil)
+	assert.Nil(t, err)
+
+	_, _, err = ConvertPCS(rKey, data, []byte("statement"), []byte("label"))
+	assert.NotNil(t, err)
+}
+
+func TestVerifyStatement(t *testing.T) {
+	key, cert := newPCSActor(t, "test")
+
+	signature, err := SignStatement(key, []byte("statement"))
+	assert.Nil(t, err)
+	assert.Nil(t, VerifyStatement(cert, []byte("statement"), signature))
+	assert.NotNil(t, VerifyStatement(cert, []byte("statemenT"), signature))
+}
@@ -2,6 +2,7 @@
 package auth
 
 import (
+	"crypto"
 	"crypto/rand"
 	"crypto/rsa"
 	"crypto/x509"
@@ -9,26 +10,32 @@ import (
 	"fmt"
 )
 
-// Cipher used to encrypt private key content in PEM format
-var Cipher = x509.PEMCipherAES256
+// encryptedKeyPEMType is the PEM type of private keys encrypted with EncryptWithPassphrase.
+const encryptedKeyPEMType = "DFSS ENCRYPTED PRIVATE KEY"
 
-// GeneratePrivateKey builds a private key of given size from default random
+// GeneratePrivateKey builds a RSA private key of given size from default random.
+// See GenerateKey for other types of keys.
 func GeneratePrivateKey(bits int) (*rsa.PrivateKey, error) {
 	return rsa.GenerateKey(rand.Reader, bits)
 }
 
-// PrivateKeyToEncryptedPEM builds a PEM-encoded array of bytes from a private key and a password.
+// PrivateKeyToEncryptedPEM builds a PKCS#8 PEM-encoded array of bytes from a private key and a password.
 // If pwd is empty, then the resulting PEM will not be encrypted.
-func PrivateKeyToEncryptedPEM(key *rsa.PrivateKey, pwd string) ([]byte, error) {
-	var err error
+// Otherwise, the PKCS#8 structure is encrypted with EncryptWithPassphrase.
+func PrivateKeyToEncryptedPEM(key crypto.Signer, pwd string) ([]byte, error) {
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return nil, err
+	}
 
 	block := &pem.Block{
-		Type:  "RSA PRIVATE KEY",
-		Bytes: x509.MarshalPKCS1PrivateKey(key),
+		Type:  "PRIVATE KEY",
+		Bytes: der,
 	}
 
 	if pwd != "" {
-		block, err = x509.EncryptPEMBlock(rand.Reader, block.Type, block.Bytes, []byte(pwd), Cipher)
+		block.Type = encryptedKeyPEMType
+		block.Bytes, err = EncryptWithPassphrase(der, pwd)
 		if err != nil {
 			return nil, err
 		}
@@ -38,16 +45,18 @@ func PrivateKeyToEncryptedPEM(key *rsa.PrivateKey, pwd string) ([]byte, error) {
 }
 
 // PrivateKeyToPEM produces a unencrypted PEM-encoded array of bytes from a private key.
-func PrivateKeyToPEM(key *rsa.PrivateKey) []byte {
+func PrivateKeyToPEM(key crypto.Signer) []byte {
 	p, _ := PrivateKeyToEncryptedPEM(key, "")
 	return p
 }
 
 // EncryptedPEMToPrivateKey tries to decrypt and decode a PEM-encoded array of bytes to a private key.
 // If pwd is empty, then the function will not try to decrypt the PEM block.
+// Both PKCS#8 and legacy PKCS#1 (RSA) or SEC 1 (ECDSA) encodings are supported,
+// as well as the legacy PEM encryption (see IsLegacyPEM).
 //
 // In case of wrong password, the returned error will be equals to x509.IncorrectPasswordError
-func EncryptedPEMToPrivateKey(data []byte, pwd string) (*rsa.PrivateKey, error) {
+func EncryptedPEMToPrivateKey(data []byte, pwd string) (crypto.Signer, error) {
 	var err error
 
 	block, _ := pem.Decode(data)
@@ -56,7 +65,12 @@ func EncryptedPEMToPrivateKey(data []byte, pwd string) (*rsa.PrivateKey, error)
 	}
 	decodedData := block.Bytes
 
-	if pwd != "" {
+	if block.Type == encryptedKeyPEMType {
+		decodedData, err = DecryptWithPassphrase(decodedData, pwd)
+		if err != nil {
+			return nil, err
+		}
+	} else if pwd != "" {
 		decodedData, err = x509.DecryptPEMBlock(block, []byte(pwd))
 
 		if err != nil {
@@ -64,17 +78,47 @@ func EncryptedPEMToPrivateKey(data []byte, pwd string) (*rsa.PrivateKey, error)
 		}
 	}
 
-	return x509.ParsePKCS1PrivateKey(decodedData)
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		return x509.ParsePKCS1PrivateKey(decodedData)
+	case "EC PRIVATE KEY":
+		return x509.ParseECPrivateKey(decodedData)
+	}
+
+	key, err := x509.ParsePKCS8PrivateKey(decodedData)
+	if err != nil {
+		return nil, err
+	}
 
+	signer, ok := key.(crypto.Signer)
+	if !ok {
+		return nil, fmt.Errorf("Unsupported private key")
+	}
+	return signer, nil
 }
 
 // PEMToPrivateKey tries to decode a plain PEM-encoded array of bytes to a private key.
-func PEMToPrivateKey(data []byte) (*rsa.PrivateKey, error) {
+func PEMToPrivateKey(data []byte) (crypto.Signer, error) {
 	return EncryptedPEMToPrivateKey(data, "")
 }
 
 // IsPEMEncrypted tests whether a PEM-encoded array of bytes is encrypted or not.
 func IsPEMEncrypted(data []byte) bool {
 	var block, _ = pem.Decode(data)
-	return x509.IsEncryptedPEMBlock(block)
+	if block == nil {
+		return false
+	}
+	return block.Type == encryptedKeyPEMType || x509.IsEncryptedPEMBlock(block)
+}
+
+// IsLegacyPEM tests whether a PEM-encoded private key uses a legacy format, and should be migrated
+// by decoding it and encoding it again with PrivateKeyToEncryptedPEM.
+//
+// Legacy formats are PKCS#1 and SEC 1 encodings, and the MD5-based PEM encryption.
+func IsLegacyPEM(data []byte) bool {
+	var block, _ = pem.Decode(data)
+	if block == nil {
+		return false
+	}
+	return block.Type == "RSA PRIVATE KEY" || block.Type == "EC PRIVATE KEY" || x509.IsEncryptedPEMBlock(block)
 }
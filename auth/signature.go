@@ -2,40 +2,144 @@ package auth
 
 import (
 	"crypto"
-	"crypto/rand"
 	"crypto/rsa"
 	"crypto/sha512"
 	"crypto/x509"
+	"encoding/asn1"
+	"errors"
 	"fmt"
 )
 
+// SealVersion is the version of the seals produced by SignStructure.
+// It is the first byte of every versioned seal, and is also part of the signed data.
+const SealVersion byte = 1
+
+// AcceptLegacySeals enables the verification of unversioned seals, computed on the string representation of the structure.
+// It is only kept during the transition period, and will be removed in a future release.
+var AcceptLegacySeals = true
+
 // SignStructure signs the provided structure with the private key.
-// The used protocol is RSA PKCS#1 v1.5 with SHA-512 hash.
-// The structure is serialized to a string representation using the fmt package.
-func SignStructure(key *rsa.PrivateKey, structure interface{}) ([]byte, error) {
-	hash, err := hashStruct(structure)
+// The SHA-512 hash of the structure is signed as in SignStatement.
+// The structure is serialized using CanonicalEncode, prefixed by the SealVersion byte.
+// The returned seal is the SealVersion byte followed by the signature.
+func SignStructure(key crypto.Signer, structure interface{}) ([]byte, error) {
+	hash, err := hashStruct(SealVersion, structure)
+	if err != nil {
+		return nil, err
+	}
+
+	signature, err := signHash(key, hash)
 	if err != nil {
 		return nil, err
 	}
 
-	return rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA512, hash)
+	return append([]byte{SealVersion}, signature...), nil
 }
 
 // VerifyStructure verifies the signed message according to the provided structure and certificate.
 // See SignStructure for protocol definition.
+//
+// Legacy seals are accepted as long as AcceptLegacySeals is true: they have no version byte,
+// and are only tried as a fallback, once the verification of the seal as a versioned one failed.
+// As they were produced before the support of other key types, only RSA certificates can verify them.
 func VerifyStructure(cert *x509.Certificate, structure interface{}, signed []byte) (bool, error) {
-	hash, err := hashStruct(structure)
-	if err != nil {
-		return false, err
+	if len(signed) == 0 {
+		return false, errors.New("Empty seal")
+	}
+
+	hash, err := hashStruct(signed[0], structure)
+	if err == nil {
+		err = verifyHash(cert.PublicKey, hash, signed[1:])
 	}
 
-	err = rsa.VerifyPKCS1v15(cert.PublicKey.(*rsa.PublicKey), crypto.SHA512, hash, signed)
+	if err != nil && AcceptLegacySeals && verifyLegacyStructure(cert, structure, signed) == nil {
+		return true, nil
+	}
 	return err == nil, err
 }
 
-func hashStruct(structure interface{}) (hash []byte, err error) {
-	data := []byte(fmt.Sprintf("%v", structure))
+// verifyLegacyStructure verifies an unversioned seal, computed on the string representation of the structure
+func verifyLegacyStructure(cert *x509.Certificate, structure interface{}, signed []byte) error {
+	if _, ok := cert.PublicKey.(*rsa.PublicKey); !ok {
+		return errors.New("Legacy seals are only produced by RSA keys")
+	}
+
+	hash := sha512.Sum512([]byte(fmt.Sprintf("%v", structure)))
+	return verifyHash(cert.PublicKey, hash[:], signed)
+}
+
+// hashStruct computes the hash of the structure to seal, encoded according to the seal version.
+// Legacy seals have no version, see verifyLegacyStructure.
+func hashStruct(version byte, structure interface{}) (hash []byte, err error) {
+	var data []byte
+	switch version {
+	case 1:
+		data, err = CanonicalEncode(structure)
+		if err != nil {
+			return
+		}
+		data = append([]byte{version}, data...)
+	default:
+		err = fmt.Errorf("Unknown seal version %d", version)
+		return
+	}
+
 	rawHash := sha512.Sum512(data)
 	hash = rawHash[:]
 	return
 }
+
+// CertifiedSignature is the signature of a statement, bundled with the certificate of its signer,
+// so that it can be checked by anyone trusting the root certificate authority.
+type CertifiedSignature struct {
+	Certificate []byte // DER-encoded certificate of the signer
+	Signature   []byte // Signature of the statement, see SignStatement
+}
+
+// NewCertifiedSignature signs the statement with the private key, and bundles the signature with the provided certificate.
+func NewCertifiedSignature(key crypto.Signer, cert *x509.Certificate, statement []byte) (*CertifiedSignature, error) {
+	signature, err := SignStatement(key, statement)
+	if err != nil {
+		return nil, err
+	}
+
+	return &CertifiedSignature{
+		Certificate: cert.Raw,
+		Signature:   signature,
+	}, nil
+}
+
+// ParseCertifiedSignature decodes a certified signature encoded with Marshal.
+func ParseCertifiedSignature(data []byte) (*CertifiedSignature, error) {
+	s := new(CertifiedSignature)
+	rest, err := asn1.Unmarshal(data, s)
+	if err != nil {
+		return nil, err
+	}
+	if len(rest) > 0 {
+		return nil, errors.New("Trailing data after signature")
+	}
+	return s, nil
+}
+
+// Marshal encodes the certified signature as an array of bytes.
+func (s *CertifiedSignature) Marshal() ([]byte, error) {
+	return asn1.Marshal(*s)
+}
+
+// Verify checks the signature of the statement against the bundled certificate, and returns this certificate.
+//
+// The caller MUST check that the returned certificate is the expected one (see CheckCertificate).
+func (s *CertifiedSignature) Verify(statement []byte) (*x509.Certificate, error) {
+	cert, err := x509.ParseCertificate(s.Certificate)
+	if err != nil {
+		return nil, err
+	}
+
+	err = VerifyStatement(cert, statement, s.Signature)
+	if err != nil {
+		return nil, err
+	}
+
+	return cert, nil
+}
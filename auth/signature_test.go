@@ -1,15 +1,20 @@
 package auth
 
 import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha512"
+	"fmt"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 )
 
 type TestStructure struct {
-	FieldA int64
-	FieldB []byte
-	FieldC *TestStructure
+	FieldA int64          `canonical:"1"`
+	FieldB []byte         `canonical:"2"`
+	FieldC *TestStructure `canonical:"3"`
 }
 
 func TestSignStructure(t *testing.T) {
@@ -45,3 +50,67 @@ func TestVerifyStructure(t *testing.T) {
 	valid, _ = VerifyStructure(cert, s, res)
 	assert.False(t, valid)
 }
+
+func TestCertifiedSignature(t *testing.T) {
+	key, err := GeneratePrivateKey(1024)
+	assert.Nil(t, err)
+	selfSigned, err := GetSelfSignedCertificate(1, 0, "", "", "", "test", key)
+	assert.Nil(t, err)
+	cert, err := PEMToCertificate(selfSigned)
+	assert.Nil(t, err)
+
+	s, err := NewCertifiedSignature(key, cert, []byte("statement"))
+	assert.Nil(t, err)
+	data, err := s.Marshal()
+	assert.Nil(t, err)
+
+	s, err = ParseCertifiedSignature(data)
+	assert.Nil(t, err)
+	signer, err := s.Verify([]byte("statement"))
+	assert.Nil(t, err)
+	assert.Equal(t, cert.Raw, signer.Raw)
+
+	_, err = s.Verify([]byte("other statement"))
+	assert.NotNil(t, err)
+
+	_, err = ParseCertifiedSignature(data[1:])
+	assert.NotNil(t, err)
+}
+
+func TestVerifyLegacyStructure(t *testing.T) {
+	key, _ := GeneratePrivateKey(1024)
+	selfSigned, _ := GetSelfSignedCertificate(1, 0, "", "", "", "test", key)
+	cert, _ := PEMToCertificate(selfSigned)
+
+	s := TestStructure{FieldA: 5}
+	hash := sha512.Sum512([]byte(fmt.Sprintf("%v", s)))
+	legacy, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA512, hash[:])
+	assert.Nil(t, err)
+
+	valid, err := VerifyStructure(cert, s, legacy)
+	assert.Nil(t, err)
+	assert.True(t, valid)
+
+	AcceptLegacySeals = false
+	valid, _ = VerifyStructure(cert, s, legacy)
+	assert.False(t, valid)
+	AcceptLegacySeals = true
+
+	// A versioned seal is not a legacy one
+	res, _ := SignStructure(key, s)
+	assert.Equal(t, SealVersion, res[0])
+	valid, _ = VerifyStructure(cert, s, res[1:])
+	assert.False(t, valid)
+
+	// A legacy seal cannot be passed off as a versioned one
+	valid, _ = VerifyStructure(cert, s, append([]byte{0}, legacy...))
+	assert.False(t, valid)
+
+	// Only RSA keys produced legacy seals
+	ecKey, _ := GenerateKey(KeyTypeECDSAP256, 0)
+	ecCert, _ := GetSelfSignedCertificate(1, 0, "", "", "", "test", ecKey)
+	ec, _ := PEMToCertificate(ecCert)
+	ecLegacy, _ := ecKey.Sign(rand.Reader, hash[:], crypto.SHA512)
+	valid, _ = VerifyStructure(ec, s, ecLegacy)
+	assert.False(t, valid)
+}
@@ -9,5 +9,8 @@ go get -u github.com/stretchr/testify/assert
 go get -u golang.org/x/crypto/ssh/terminal
 go get -u github.com/spf13/viper
 go get -u github.com/spf13/cobra
+go get -u filippo.io/bigmod
+go get -u filippo.io/nistec
+go get -u filippo.io/edwards25519
 
 go get -u github.com/inconshreveable/mousetrap # required by cobra for win builds
@@ -13,6 +13,9 @@ It has these top-level messages:
 	Promise
 	Signature
 	Hello
+	ReadySignal
+	Envelope
+	SignedEnvelope
 */
 package api
 
@@ -56,8 +59,13 @@ type Context struct {
 	TtpAddrPort string `protobuf:"bytes,7,opt,name=ttpAddrPort" json:"ttpAddrPort,omitempty"`
 	// / The TTP certificate SHA-512 hash
 	TtpHash []byte `protobuf:"bytes,8,opt,name=ttpHash,proto3" json:"ttpHash,omitempty"`
+	// / The name of the generator used to build the signing sequence, as provided by the platform
+	SequenceGenerator string `protobuf:"bytes,9,opt,name=sequenceGenerator" json:"sequenceGenerator,omitempty"`
 	// / The signed metadata seal, as provided by the platform during the ready signal
 	Seal []byte `protobuf:"bytes,10,opt,name=seal,proto3" json:"seal,omitempty"`
+	// / The deadline of an asynchronous signature (unix time in seconds), as provided by the platform.
+	// / Zero for a synchronous signature.
+	Deadline int64 `protobuf:"varint,11,opt,name=deadline" json:"deadline,omitempty"`
 }
 
 func (m *Context) Reset()                    { *m = Context{} }
@@ -106,9 +114,21 @@ func (m *Signature) GetContext() *Context {
 }
 
 // / Hello message is used when discovering peers.
+// / It binds the connection to a contract and a signer, and negotiates the version of the signature protocol.
 type Hello struct {
 	// / Used version of DFSS client
 	Version string `protobuf:"bytes,1,opt,name=version" json:"version,omitempty"`
+	// / The contract UUID being signed
+	ContractUuid string `protobuf:"bytes,2,opt,name=contractUuid" json:"contractUuid,omitempty"`
+	// / The certificate hash of the sender
+	KeyHash []byte `protobuf:"bytes,3,opt,name=keyHash,proto3" json:"keyHash,omitempty"`
+	// / The range of signature protocol versions supported by the sender
+	MinProtocol uint32 `protobuf:"varint,4,opt,name=minProtocol" json:"minProtocol,omitempty"`
+	MaxProtocol uint32 `protobuf:"varint,5,opt,name=maxProtocol" json:"maxProtocol,omitempty"`
+	// / The negotiated protocol version, only set in answers
+	Protocol uint32 `protobuf:"varint,6,opt,name=protocol" json:"protocol,omitempty"`
+	// / The result of the handshake, only set in answers
+	ErrorCode *api1.ErrorCode `protobuf:"bytes,7,opt,name=errorCode" json:"errorCode,omitempty"`
 }
 
 func (m *Hello) Reset()                    { *m = Hello{} }
@@ -116,11 +136,80 @@ func (m *Hello) String() string            { return proto.CompactTextString(m) }
 func (*Hello) ProtoMessage()               {}
 func (*Hello) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{3} }
 
+func (m *Hello) GetErrorCode() *api1.ErrorCode {
+	if m != nil {
+		return m.ErrorCode
+	}
+	return nil
+}
+
+// / ReadySignal is sent by a signer to its peers before the first promise round.
+type ReadySignal struct {
+	// / The unique signature attemp ID, as provided by the platform during the ready signal
+	SignatureUUID string `protobuf:"bytes,1,opt,name=signatureUUID" json:"signatureUUID,omitempty"`
+}
+
+func (m *ReadySignal) Reset()                    { *m = ReadySignal{} }
+func (m *ReadySignal) String() string            { return proto.CompactTextString(m) }
+func (*ReadySignal) ProtoMessage()               {}
+func (*ReadySignal) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{4} }
+
+// / Envelope is the content of an evidence exchanged through the platform, in its mailboxes or its relay.
+// Exactly one of promise, signature or ready is set.
+type Envelope struct {
+	Promise   *Promise     `protobuf:"bytes,1,opt,name=promise" json:"promise,omitempty"`
+	Signature *Signature   `protobuf:"bytes,2,opt,name=signature" json:"signature,omitempty"`
+	Ready     *ReadySignal `protobuf:"bytes,3,opt,name=ready" json:"ready,omitempty"`
+}
+
+func (m *Envelope) Reset()                    { *m = Envelope{} }
+func (m *Envelope) String() string            { return proto.CompactTextString(m) }
+func (*Envelope) ProtoMessage()               {}
+func (*Envelope) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{5} }
+
+func (m *Envelope) GetPromise() *Promise {
+	if m != nil {
+		return m.Promise
+	}
+	return nil
+}
+
+func (m *Envelope) GetSignature() *Signature {
+	if m != nil {
+		return m.Signature
+	}
+	return nil
+}
+
+func (m *Envelope) GetReady() *ReadySignal {
+	if m != nil {
+		return m.Ready
+	}
+	return nil
+}
+
+// / SignedEnvelope is an envelope signed by its sender, before its encryption for the recipient.
+// The platform carrying it is not trusted to authenticate the sender.
+type SignedEnvelope struct {
+	// / The encoded Envelope
+	Envelope []byte `protobuf:"bytes,1,opt,name=envelope,proto3" json:"envelope,omitempty"`
+	// / The certified signature of the encoded envelope by its sender (see auth.CertifiedSignature)
+	Signature []byte `protobuf:"bytes,2,opt,name=signature,proto3" json:"signature,omitempty"`
+}
+
+func (m *SignedEnvelope) Reset()                    { *m = SignedEnvelope{} }
+func (m *SignedEnvelope) String() string            { return proto.CompactTextString(m) }
+func (*SignedEnvelope) ProtoMessage()               {}
+func (*SignedEnvelope) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{6} }
+
 func init() {
 	proto.RegisterType((*Context)(nil), "api.Context")
 	proto.RegisterType((*Promise)(nil), "api.Promise")
 	proto.RegisterType((*Signature)(nil), "api.Signature")
 	proto.RegisterType((*Hello)(nil), "api.Hello")
+	proto.RegisterType((*ReadySignal)(nil), "api.ReadySignal")
+	proto.RegisterType((*Envelope)(nil), "api.Envelope")
+	proto.RegisterType((*SignedEnvelope)(nil), "api.SignedEnvelope")
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -140,6 +229,9 @@ type ClientClient interface {
 	TreatSignature(ctx context.Context, in *Signature, opts ...grpc.CallOption) (*api1.ErrorCode, error)
 	// / Permits initial handshake for P2P between clients.
 	Discover(ctx context.Context, in *Hello, opts ...grpc.CallOption) (*Hello, error)
+	// / Handle readiness signals, sent before the first promise round.
+	// A signer answers SUCCESS once it accepts the evidence of the signature.
+	Ready(ctx context.Context, in *ReadySignal, opts ...grpc.CallOption) (*api1.ErrorCode, error)
 }
 
 type clientClient struct {
@@ -177,6 +269,15 @@ func (c *clientClient) Discover(ctx context.Context, in *Hello, opts ...grpc.Cal
 	return out, nil
 }
 
+func (c *clientClient) Ready(ctx context.Context, in *ReadySignal, opts ...grpc.CallOption) (*api1.ErrorCode, error) {
+	out := new(api1.ErrorCode)
+	err := grpc.Invoke(ctx, "/api.Client/Ready", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // Server API for Client service
 
 type ClientServer interface {
@@ -186,6 +287,9 @@ type ClientServer interface {
 	TreatSignature(context.Context, *Signature) (*api1.ErrorCode, error)
 	// / Permits initial handshake for P2P between clients.
 	Discover(context.Context, *Hello) (*Hello, error)
+	// / Handle readiness signals, sent before the first promise round.
+	// A signer answers SUCCESS once it accepts the evidence of the signature.
+	Ready(context.Context, *ReadySignal) (*api1.ErrorCode, error)
 }
 
 func RegisterClientServer(s *grpc.Server, srv ClientServer) {
@@ -246,6 +350,24 @@ func _Client_Discover_Handler(srv interface{}, ctx context.Context, dec func(int
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Client_Ready_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReadySignal)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ClientServer).Ready(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.Client/Ready",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ClientServer).Ready(ctx, req.(*ReadySignal))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _Client_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "api.Client",
 	HandlerType: (*ClientServer)(nil),
@@ -262,36 +384,52 @@ var _Client_serviceDesc = grpc.ServiceDesc{
 			MethodName: "Discover",
 			Handler:    _Client_Discover_Handler,
 		},
+		{
+			MethodName: "Ready",
+			Handler:    _Client_Ready_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{},
 }
 
 var fileDescriptor0 = []byte{
-	// 404 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0x8c, 0x52, 0xdd, 0xca, 0xda, 0x40,
-	0x10, 0xf5, 0x3f, 0x3a, 0x46, 0x29, 0x8b, 0x17, 0x21, 0xa5, 0x60, 0x83, 0x14, 0xe9, 0x45, 0x04,
-	0xfb, 0x04, 0x45, 0x0b, 0x96, 0x52, 0x90, 0xb4, 0x3e, 0xc0, 0x76, 0x33, 0xb6, 0x0b, 0x31, 0x9b,
-	0xee, 0xae, 0x45, 0x5f, 0xa3, 0xaf, 0xd3, 0x97, 0xeb, 0x66, 0x62, 0xfc, 0x94, 0xcf, 0x8b, 0xef,
-	0x26, 0xec, 0x39, 0x73, 0x76, 0xce, 0xec, 0xc9, 0xc0, 0xeb, 0x74, 0x6f, 0xcc, 0xa2, 0xfc, 0x88,
-	0x05, 0x2f, 0xe4, 0x42, 0x64, 0x12, 0x73, 0x1b, 0x17, 0x5a, 0x59, 0xc5, 0xda, 0x8e, 0x09, 0xdf,
-	0x5c, 0x15, 0x05, 0x29, 0x8a, 0x8c, 0xdb, 0xbd, 0xd2, 0x87, 0x4a, 0x13, 0xfd, 0x6b, 0x81, 0xb7,
-	0x52, 0xb9, 0xc5, 0x93, 0x65, 0xef, 0xe1, 0x95, 0x46, 0x21, 0x8b, 0xb2, 0xc5, 0x17, 0x3c, 0x6f,
-	0xb8, 0xf9, 0x15, 0x34, 0xa7, 0xcd, 0xb9, 0x9f, 0x3c, 0xe3, 0xd9, 0x0c, 0x46, 0x06, 0xf3, 0x14,
-	0x75, 0x2d, 0x6c, 0x91, 0xf0, 0x9e, 0x64, 0x21, 0xf4, 0x0d, 0xfe, 0x3e, 0x62, 0x2e, 0x30, 0x68,
-	0x4f, 0xdb, 0xf3, 0x51, 0x72, 0xc5, 0x2c, 0x00, 0xcf, 0xc8, 0x9f, 0x39, 0x6a, 0x13, 0x74, 0x5c,
-	0xc9, 0x4f, 0x6a, 0xc8, 0x96, 0x30, 0x11, 0x6e, 0x24, 0xcd, 0x85, 0x5d, 0x2b, 0x71, 0x3c, 0x38,
-	0x5b, 0xb2, 0xe8, 0x92, 0xc5, 0xc3, 0x1a, 0xcd, 0xe3, 0xae, 0x73, 0x7b, 0xd4, 0xb8, 0xdb, 0x7d,
-	0x5e, 0x07, 0x3d, 0x27, 0x1e, 0x24, 0xf7, 0x24, 0x9b, 0xc2, 0xd0, 0xda, 0xe2, 0x63, 0x9a, 0xea,
-	0xad, 0xd2, 0x36, 0xf0, 0x48, 0x73, 0x4b, 0x95, 0x53, 0x39, 0x48, 0x76, 0x7d, 0xb2, 0xab, 0x21,
-	0x63, 0xd0, 0x31, 0xc8, 0xb3, 0x00, 0x88, 0xa6, 0x73, 0xc4, 0xc1, 0xdb, 0x6a, 0x75, 0x90, 0x06,
-	0xd9, 0x3b, 0xf0, 0x44, 0x95, 0x23, 0x65, 0x36, 0x5c, 0xfa, 0xb1, 0x8b, 0x3b, 0xbe, 0x64, 0x9b,
-	0xd4, 0x45, 0x36, 0x81, 0xae, 0x74, 0x11, 0x9d, 0x28, 0xb0, 0x51, 0x52, 0x81, 0xd2, 0xb6, 0xe0,
-	0xe7, 0x4c, 0xf1, 0xd4, 0xe5, 0x44, 0xb6, 0x17, 0x18, 0x7d, 0x85, 0xc1, 0xb7, 0xfa, 0x0d, 0x2f,
-	0x36, 0xb9, 0x69, 0xd7, 0xba, 0x6f, 0xf7, 0x16, 0xba, 0x1b, 0xcc, 0x32, 0x55, 0x4a, 0xfe, 0xb8,
-	0xb0, 0xa5, 0xca, 0xa9, 0xd5, 0x20, 0xa9, 0xe1, 0xf2, 0x6f, 0x13, 0x7a, 0x2b, 0xda, 0x23, 0x16,
-	0x83, 0xff, 0x5d, 0x23, 0xb7, 0xf5, 0x23, 0x2b, 0xbb, 0x0b, 0x0a, 0xc7, 0x84, 0x3e, 0x69, 0xad,
-	0xf4, 0x4a, 0xa5, 0x18, 0x35, 0xdc, 0x9f, 0x1b, 0x93, 0xfe, 0x69, 0xe2, 0x4a, 0x73, 0xc5, 0x0f,
-	0xee, 0xcc, 0xa0, 0xbf, 0x96, 0x46, 0x28, 0x67, 0xcf, 0x80, 0xaa, 0x34, 0x60, 0x78, 0x73, 0x8e,
-	0x1a, 0x3f, 0x7a, 0xb4, 0xae, 0x1f, 0xfe, 0x07, 0x00, 0x00, 0xff, 0xff, 0x63, 0xf5, 0x69, 0x69,
-	0xf1, 0x02, 0x00, 0x00,
+	// 608 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0x8c, 0x54, 0x6d, 0x4e, 0xdb, 0x40,
+	0x10, 0xc5, 0x98, 0x7c, 0x4d, 0x02, 0xa2, 0x2b, 0x7e, 0x58, 0x69, 0x2b, 0x45, 0x16, 0x42, 0x51,
+	0x8b, 0x82, 0x14, 0x4e, 0x50, 0x01, 0x2a, 0x6d, 0x55, 0x09, 0x6d, 0xcb, 0x01, 0xb6, 0xf6, 0x40,
+	0x57, 0x75, 0x76, 0xb7, 0xeb, 0x0d, 0x82, 0x03, 0x54, 0xea, 0xad, 0x7a, 0xa2, 0xde, 0xa1, 0xda,
+	0xb1, 0xd7, 0x71, 0x48, 0x7e, 0xf4, 0x0f, 0xda, 0x37, 0xf3, 0x3c, 0xf3, 0xe6, 0xcd, 0x10, 0x78,
+	0x99, 0xdf, 0x95, 0xe5, 0x99, 0xff, 0x93, 0x9d, 0x09, 0x23, 0xcf, 0xb2, 0x42, 0xa2, 0x72, 0x33,
+	0x63, 0xb5, 0xd3, 0x2c, 0x16, 0x46, 0x8e, 0x5f, 0x37, 0x0c, 0x43, 0x0c, 0x53, 0x08, 0x77, 0xa7,
+	0xed, 0xa2, 0xe2, 0xa4, 0xbf, 0x62, 0xe8, 0x5d, 0x68, 0xe5, 0xf0, 0xd1, 0xb1, 0x37, 0x70, 0x68,
+	0x31, 0x93, 0xc6, 0x97, 0xf8, 0x84, 0x4f, 0xd7, 0xa2, 0xfc, 0x9e, 0x44, 0x93, 0x68, 0x3a, 0xe2,
+	0x1b, 0x71, 0x76, 0x0c, 0xfb, 0x25, 0xaa, 0x1c, 0x6d, 0x20, 0xee, 0x12, 0x71, 0x3d, 0xc8, 0xc6,
+	0xd0, 0x2f, 0xf1, 0xe7, 0x12, 0x55, 0x86, 0x49, 0x3c, 0x89, 0xa7, 0xfb, 0xbc, 0xc1, 0x2c, 0x81,
+	0x5e, 0x29, 0xef, 0x15, 0xda, 0x32, 0xd9, 0x9b, 0xc4, 0xd3, 0x11, 0x0f, 0x90, 0xcd, 0xe1, 0x28,
+	0xd3, 0xca, 0x59, 0x91, 0xb9, 0x4b, 0x9d, 0x2d, 0x17, 0xa8, 0x1c, 0xb5, 0xe8, 0x50, 0x8b, 0xad,
+	0x39, 0xd2, 0x23, 0xef, 0x95, 0x70, 0x4b, 0x8b, 0xb7, 0xb7, 0x1f, 0x2e, 0x93, 0xee, 0x24, 0x9a,
+	0x0e, 0xf8, 0x7a, 0x90, 0x4d, 0x60, 0xe8, 0x9c, 0x79, 0x97, 0xe7, 0xf6, 0x46, 0x5b, 0x97, 0xf4,
+	0x88, 0xd3, 0x0e, 0x79, 0x55, 0xce, 0x19, 0x6a, 0xd7, 0xa7, 0x76, 0x01, 0xb2, 0x53, 0x78, 0x11,
+	0xb4, 0xbf, 0x47, 0x85, 0x56, 0x38, 0x6d, 0x93, 0x01, 0x55, 0xd8, 0x4c, 0x30, 0x06, 0x7b, 0x25,
+	0x8a, 0x22, 0x01, 0x2a, 0x42, 0x6f, 0xef, 0x46, 0x8e, 0x22, 0x2f, 0xa4, 0xc2, 0x64, 0x38, 0x89,
+	0xa6, 0x31, 0x6f, 0x70, 0x2a, 0xa0, 0x77, 0x63, 0xf5, 0x42, 0x96, 0xc8, 0x4e, 0xa0, 0x97, 0x55,
+	0x1b, 0x21, 0xf7, 0x87, 0xf3, 0xd1, 0x4c, 0x18, 0x39, 0xab, 0xb7, 0xc4, 0x43, 0x92, 0x1d, 0x41,
+	0x47, 0xaa, 0x1c, 0x1f, 0xc9, 0xfa, 0x7d, 0x5e, 0x01, 0x3f, 0x80, 0x11, 0x4f, 0x85, 0x16, 0x79,
+	0x12, 0x57, 0x03, 0xd4, 0x30, 0xfd, 0x0c, 0x83, 0x2f, 0xc1, 0x8d, 0xff, 0x6e, 0xd2, 0x2a, 0xb7,
+	0xbb, 0x5e, 0xee, 0x6f, 0x04, 0x9d, 0x6b, 0x2c, 0x0a, 0xed, 0x39, 0x0f, 0x68, 0x4b, 0xa9, 0x15,
+	0xd5, 0x1a, 0xf0, 0x00, 0x59, 0x0a, 0xa3, 0xb0, 0xad, 0xdb, 0xa5, 0xac, 0x4a, 0x0c, 0xf8, 0x5a,
+	0xcc, 0x7f, 0xfd, 0xa3, 0xbe, 0xa1, 0x5a, 0x70, 0x0d, 0xfd, 0xb6, 0x16, 0x52, 0xdd, 0xf8, 0x3b,
+	0xcd, 0x74, 0x91, 0xec, 0xd1, 0x98, 0xed, 0x10, 0x31, 0xc4, 0x63, 0xc3, 0xe8, 0xd4, 0x8c, 0x55,
+	0xc8, 0x7b, 0x6e, 0x42, 0xba, 0x4b, 0xe9, 0x06, 0xb3, 0x53, 0x18, 0xa0, 0xb5, 0xda, 0x5e, 0xe8,
+	0x1c, 0xe9, 0x16, 0x86, 0xf3, 0x03, 0x72, 0xe1, 0x2a, 0x44, 0xf9, 0x8a, 0x90, 0x9e, 0xc3, 0x90,
+	0xa3, 0xc8, 0x9f, 0xc8, 0xc3, 0x62, 0xf3, 0xe0, 0xa2, 0x2d, 0x07, 0x97, 0xfe, 0x8e, 0xa0, 0x7f,
+	0xa5, 0x1e, 0xb0, 0xd0, 0x86, 0x3c, 0x37, 0xd5, 0x8e, 0xd7, 0x3c, 0xaf, 0xf7, 0xce, 0x43, 0xd2,
+	0xeb, 0x6a, 0xaa, 0x24, 0xbb, 0x2d, 0x5d, 0xcd, 0xfa, 0xf8, 0x8a, 0xc0, 0x4e, 0xa0, 0x63, 0xbd,
+	0x2e, 0x72, 0x6f, 0x38, 0x3f, 0x24, 0x66, 0x4b, 0x29, 0xaf, 0xd2, 0xe9, 0x47, 0x38, 0xf0, 0x01,
+	0xcc, 0x1b, 0x3d, 0x63, 0xe8, 0x63, 0xfd, 0xae, 0xff, 0xcf, 0x1b, 0xcc, 0x5e, 0x3d, 0xd7, 0x30,
+	0x6a, 0xf5, 0x9c, 0xff, 0x89, 0xa0, 0x7b, 0x41, 0x3f, 0x35, 0x6c, 0x06, 0xa3, 0xaf, 0x16, 0x85,
+	0x0b, 0xd7, 0xbb, 0x36, 0xd3, 0xf8, 0x99, 0x9f, 0xe9, 0x0e, 0x9b, 0xc3, 0x01, 0xf1, 0x57, 0xa7,
+	0xf8, 0x6c, 0xb6, 0x2d, 0xdf, 0x1c, 0x43, 0xff, 0x52, 0x96, 0x99, 0x7e, 0x40, 0xcb, 0x80, 0xb2,
+	0x74, 0x78, 0xe3, 0xd6, 0x3b, 0xdd, 0x61, 0x6f, 0xa1, 0x43, 0x63, 0xb3, 0x0d, 0x0b, 0x36, 0x4b,
+	0x7e, 0xeb, 0xd2, 0x15, 0x9c, 0xff, 0x1b, 0x00, 0x7b, 0x81, 0x8e, 0xff, 0x41, 0x05, 0x00, 0x00,
 }
@@ -0,0 +1,77 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"dfss/dfssc/common"
+	"dfss/dfssp/contract"
+	"github.com/spf13/cobra"
+)
+
+var fairnessCmd = &cobra.Command{
+	Use:   "fairness [s]",
+	Short: "check the fairness of signing sequence s, a comma-separated list of signers indexes",
+	Long: `Explore every abort and resolve scenario of the signing protocol for a sequence,
+against every group of dishonest signers and every network failure.
+
+The sequence is either provided as a comma-separated list of signers indexes (0,1,2,0,1,2,0,1,2),
+or built by a generator of the platform for the number of signers set with the signers option.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		n, _ := cmd.Flags().GetInt("signers")
+		generator, _ := cmd.Flags().GetString("generator")
+
+		var sequence []uint32
+		var err error
+		if len(args) == 1 && generator == "" {
+			sequence, err = parseSequence(args[0])
+		} else if len(args) == 0 && generator != "" {
+			var g contract.SequenceGenerator
+			g, err = contract.GetSequenceGenerator(generator)
+			if err == nil {
+				sequence = g.Generate(n)
+			}
+		} else {
+			_ = cmd.Usage()
+			os.Exit(1)
+		}
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+
+		if n == 0 {
+			for _, s := range sequence {
+				if int(s) >= n {
+					n = int(s) + 1
+				}
+			}
+		}
+
+		report, err := common.CheckFairness(sequence, n)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+
+		fmt.Print(report.String())
+		if !report.Fair() || !report.AbuseFree() {
+			os.Exit(2)
+		}
+	},
+}
+
+// parseSequence reads a comma-separated list of signers indexes
+func parseSequence(s string) ([]uint32, error) {
+	var sequence []uint32
+	for _, v := range strings.Split(s, ",") {
+		i, err := strconv.ParseUint(strings.TrimSpace(v), 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid signer index %q in sequence", v)
+		}
+		sequence = append(sequence, uint32(i))
+	}
+	return sequence, nil
+}
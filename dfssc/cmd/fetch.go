@@ -21,10 +21,30 @@ var fetchCmd = &cobra.Command{
 		readStringParam("Save directory", ".", &directory)
 
 		path := filepath.Join(directory, uuid+".json")
-		err := sign.FetchContract(passphrase, uuid, path)
+		err := sign.FetchContract(sign.ConfigFromViper(), passphrase, uuid, path)
 		if err != nil {
 			fmt.Fprintln(os.Stderr, err)
 			os.Exit(1)
 		}
+
+		// Checking the seal of the stored file
+		c := getContract(path)
+		if c == nil {
+			os.Exit(1)
+		}
+
+		if document, _ := cmd.Flags().GetBool("document"); document {
+			if c.File == nil {
+				fmt.Fprintln(os.Stderr, "This contract has no document")
+				os.Exit(1)
+			}
+			documentPath := filepath.Join(directory, filepath.Base(c.File.Name))
+			err = sign.FetchDocument(sign.ConfigFromViper(), passphrase, c, documentPath)
+			if err != nil {
+				fmt.Fprintln(os.Stderr, err)
+				os.Exit(1)
+			}
+			fmt.Println("Document saved to " + documentPath)
+		}
 	},
 }
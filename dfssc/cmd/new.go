@@ -14,8 +14,11 @@ var newCmd = &cobra.Command{
 	Run: func(cmd *cobra.Command, args []string) {
 		fmt.Println("Creating a new contract")
 
+		sequence, _ := cmd.Flags().GetString("sequence")
+		deadline, _ := cmd.Flags().GetDuration("deadline")
+		upload, _ := cmd.Flags().GetBool("upload")
 		passphrase, filepath, comment, signers := getContractInfo()
-		err := sign.SendNewContract(passphrase, filepath, comment, signers)
+		err := sign.SendNewContract(sign.ConfigFromViper(), passphrase, filepath, comment, signers, sequence, deadline, upload)
 		if err != nil {
 			fmt.Fprintln(os.Stderr, err)
 			os.Exit(1)
@@ -24,7 +24,7 @@ func recover(cmd *cobra.Command, args []string) {
 	_ = readPassword(&passphrase, false)
 	filename := args[0]
 
-	err := sign.Recover(filename, passphrase)
+	err := sign.Recover(sign.ConfigFromViper(), filename, passphrase)
 	if err != nil {
 		fmt.Fprintln(os.Stderr, err)
 		os.Exit(1)
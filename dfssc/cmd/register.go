@@ -9,6 +9,7 @@ import (
 	"strconv"
 	"strings"
 
+	"dfss/auth"
 	"dfss/dfssc/user"
 	"github.com/spf13/cobra"
 	"golang.org/x/crypto/ssh/terminal"
@@ -22,6 +23,7 @@ var registerCmd = &cobra.Command{
 		// Initialize variables
 		var country, mail, organization, unit, passphrase string
 		var bits int
+		keyType, _ := cmd.Flags().GetString("type")
 
 		name := "Jon Doe"
 		u, err := osuser.Current()
@@ -34,7 +36,9 @@ var registerCmd = &cobra.Command{
 		readStringParam("Country", "FR", &country)
 		readStringParam("Organization", name, &organization)
 		readStringParam("Organizational unit", name, &unit)
-		readIntParam("Length of the key (2048 or 4096)", "2048", &bits)
+		if keyType == auth.KeyTypeRSA {
+			readIntParam("Length of the key (2048 or 4096)", "2048", &bits)
+		}
 		err = readPassword(&passphrase, true)
 		if err != nil {
 			fmt.Println("An error occurred:", err.Error())
@@ -43,7 +47,7 @@ var registerCmd = &cobra.Command{
 		}
 
 		recapUser(mail, country, organization, unit)
-		err = user.Register(passphrase, country, organization, unit, mail, bits)
+		err = user.Register(passphrase, country, organization, unit, mail, keyType, bits)
 		if err != nil {
 			fmt.Fprintln(os.Stderr, "An error occurred:", err.Error())
 			os.Exit(2)
@@ -0,0 +1,37 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"dfss/auth"
+	"dfss/dfssc/user"
+	"github.com/spf13/cobra"
+)
+
+var renewCmd = &cobra.Command{
+	Use:   "renew",
+	Short: "renew the client certificate before it expires",
+	Long: `Ask the platform for a new certificate, before the current one expires.
+
+The previous certificate is revoked on the first use of the new one, or after a grace period,
+and contracts whose signature has not been launched yet are updated with the new one.
+Use the type option to replace the private key by a new one.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		var passphrase string
+		var bits int
+		keyType, _ := cmd.Flags().GetString("type")
+
+		if keyType == auth.KeyTypeRSA {
+			readIntParam("Length of the new key (2048 or 4096)", "2048", &bits)
+		}
+		_ = readPassword(&passphrase, false)
+
+		err := user.Renew(passphrase, keyType, bits)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Cannot renew certificate:", err.Error())
+			os.Exit(2)
+		}
+		fmt.Println("Certificate successfully renewed")
+	},
+}
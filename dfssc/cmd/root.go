@@ -2,10 +2,13 @@
 package cmd
 
 import (
+	"strings"
 	"time"
 
 	"dfss"
+	"dfss/auth"
 	dapi "dfss/dfssd/api"
+	"dfss/dfssp/contract"
 	"dfss/net"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
@@ -37,26 +40,47 @@ func init() {
 	// Bind flags to the dfssc command
 	RootCmd.PersistentFlags().BoolP("verbose", "v", false, "print verbose messages")
 	RootCmd.PersistentFlags().String("ca", "ca.pem", "path to the root certificate")
+	RootCmd.PersistentFlags().String("crl", "crl.pem", "path to the cached certificate revocation list")
 	RootCmd.PersistentFlags().String("cert", "cert.pem", "path to the user's certificate")
 	RootCmd.PersistentFlags().String("key", "key.pem", "path to the user's private key")
 	RootCmd.PersistentFlags().StringP("demo", "d", "", "demonstrator address and port, empty will disable it")
 	RootCmd.PersistentFlags().String("host", "localhost:9000", "host of the dfss platform")
-	RootCmd.PersistentFlags().IntP("port", "p", 9005, "port to use for P2P communication between clients")
+	RootCmd.PersistentFlags().IntP("port", "p", 9005, "port to use for P2P communication between clients, 0 lets the system pick one")
+	RootCmd.PersistentFlags().String("bind", "", "address to bind for P2P communication between clients, empty listens on every interface")
+	RootCmd.PersistentFlags().StringSlice("advertise", nil, "addresses (host or host:port) announced to other clients for P2P communication, empty announces the local ones")
 	RootCmd.PersistentFlags().Duration("timeout", 10*time.Second, "time to wait for connection and evidences before failing")
 
 	signCmd.Flags().Duration("slowdown", 0, "delay between each promises round (test only)")
 	signCmd.Flags().Int("stopbefore", 0, "stop signature just before the promises round n, -1 to stop right before signature round (test only)")
 
+	newCmd.Flags().String("sequence", "", "generator of the signing sequence: "+strings.Join(contract.SequenceGenerators(), ", ")+" (empty uses the default one)")
+	newCmd.Flags().Duration("deadline", 0, "delay to sign asynchronously through the platform, for instance 72h (0 for a synchronous signature)")
+	newCmd.Flags().Bool("upload", false, "upload the document to the platform, for signers to download it with fetch")
+
+	fetchCmd.Flags().Bool("document", false, "also download the document of the contract, if hosted on the platform")
+
+	registerCmd.Flags().String("type", auth.KeyTypeRSA, "type of the private key: "+strings.Join(auth.KeyTypes, ", "))
+	renewCmd.Flags().String("type", "", "type of a new private key to replace the current one: "+strings.Join(auth.KeyTypes, ", ")+" (empty keeps the current key)")
+
+	fairnessCmd.Flags().Int("signers", 0, "number of signers, inferred from the sequence if 0")
+	fairnessCmd.Flags().String("generator", "", "name of the generator building the sequence: "+strings.Join(contract.SequenceGenerators(), ", "))
+
+	verifyCmd.Flags().String("contract", "", "path to the contract document, to check its hash against the proof")
+	verifyCmd.Flags().String("at", "", "time of the signature in RFC 3339 format, to check the certificates at (empty uses the creation date of the contract)")
+
 	// Store flag values into viper
 	_ = viper.BindPFlag("verbose", RootCmd.PersistentFlags().Lookup("verbose"))
 	_ = viper.BindPFlag("file_ca", RootCmd.PersistentFlags().Lookup("ca"))
+	_ = viper.BindPFlag("file_crl", RootCmd.PersistentFlags().Lookup("crl"))
 	_ = viper.BindPFlag("file_cert", RootCmd.PersistentFlags().Lookup("cert"))
 	_ = viper.BindPFlag("file_key", RootCmd.PersistentFlags().Lookup("key"))
 	_ = viper.BindPFlag("demo", RootCmd.PersistentFlags().Lookup("demo"))
 	_ = viper.BindPFlag("local_port", RootCmd.PersistentFlags().Lookup("port"))
+	_ = viper.BindPFlag("local_host", RootCmd.PersistentFlags().Lookup("bind"))
+	_ = viper.BindPFlag("local_advertise", RootCmd.PersistentFlags().Lookup("advertise"))
 	_ = viper.BindPFlag("platform_addrport", RootCmd.PersistentFlags().Lookup("host"))
 	_ = viper.BindPFlag("timeout", RootCmd.PersistentFlags().Lookup("timeout"))
 
 	// Bind subcommands to root
-	RootCmd.AddCommand(dfss.VersionCmd, registerCmd, authCmd, newCmd, showCmd, fetchCmd, importCmd, exportCmd, signCmd, unregisterCmd, recoverCmd)
+	RootCmd.AddCommand(dfss.VersionCmd, registerCmd, authCmd, newCmd, showCmd, fetchCmd, importCmd, exportCmd, signCmd, unregisterCmd, renewCmd, recoverCmd, verifyCmd, fairnessCmd)
 }
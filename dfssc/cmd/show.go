@@ -4,18 +4,21 @@ import (
 	"bytes"
 	"fmt"
 	"io/ioutil"
+	"os"
 	"text/template"
 
 	"dfss/dfssc/common"
 	"dfss/dfssp/contract"
 	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
 )
 
 const contractShowTemplate = `UUID       : {{.UUID}}
 Filename   : {{.File.Name}}
 Filehash   : {{.File.Hash}}
 Created on : {{.Date.Format "2006-01-02 15:04:05 MST"}}
-
+{{if .Deadline}}Deadline   : {{.Deadline.Format "2006-01-02 15:04:05 MST"}} (asynchronous signature)
+{{end}}
 Comment    :
   {{.Comment}}
 
@@ -40,7 +43,10 @@ func showContract(cmd *cobra.Command, args []string) {
 	if c == nil {
 		return
 	}
+	printContract(c)
+}
 
+func printContract(c *contract.JSON) {
 	b := new(bytes.Buffer)
 	tmpl, err := template.New("contract").Parse(contractShowTemplate)
 	if err != nil {
@@ -67,5 +73,11 @@ func getContract(filename string) *contract.JSON {
 		fmt.Println("Corrupted file:", err)
 		return nil
 	}
+
+	// Forged files are only detected at the ready signal otherwise
+	err = common.CheckDFSSFile(c, viper.GetString("file_ca"))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Warning:", err)
+	}
 	return c
 }
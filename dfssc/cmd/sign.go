@@ -3,6 +3,8 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"os/signal"
+	"sync"
 
 	"dfss/dfssc/sign"
 	"github.com/spf13/cobra"
@@ -22,14 +24,14 @@ var signCmd = &cobra.Command{
 		_ = viper.BindPFlag("stopbefore", cmd.Flags().Lookup("stopbefore"))
 
 		filename := args[0]
-		fmt.Println("You are going to sign the following contract:")
-		showContract(cmd, args)
-
 		contract := getContract(filename)
 		if contract == nil {
 			os.Exit(1)
 		}
 
+		fmt.Println("You are going to sign the following contract:")
+		printContract(contract)
+
 		var contractPath string
 		readStringParam("Local contract path [skip]", "", &contractPath)
 		if !checkContractHash(contractPath, contract.File.Hash) {
@@ -47,9 +49,23 @@ var signCmd = &cobra.Command{
 			os.Exit(1)
 		}
 
-		fmt.Println("Waiting for peers...")
 		manager.OnSignerStatusUpdate = signFeedbackFn
-		err = manager.ConnectToPeers()
+		manager.OnProgressUpdate = signProgressFn
+		interrupts := &interruptHandler{}
+		go interrupts.listen(manager)
+
+		// Resuming a signature interrupted by a crash, if any
+		if journal := manager.FindJournal(); journal != "" {
+			resumeSignature(manager, journal)
+			return
+		}
+
+		if contract.Deadline != nil {
+			fmt.Println("Asynchronous signature, the evidence is exchanged through the platform until", contract.Deadline.Format("2006-01-02 15:04:05 MST"))
+		} else {
+			fmt.Println("Waiting for peers...")
+		}
+		err = interrupts.cancellable(manager.ConnectToPeers)
 		if err != nil {
 			fmt.Fprintln(os.Stderr, err)
 			os.Exit(1)
@@ -65,7 +81,11 @@ var signCmd = &cobra.Command{
 
 		// Ignition
 		fmt.Println("Waiting for other signers to be ready...")
-		signatureUUID, err := manager.SendReadySign()
+		var signatureUUID string
+		err = interrupts.cancellable(func() (err error) {
+			signatureUUID, err = manager.SendReadySign()
+			return
+		})
 		if err != nil {
 			fmt.Fprintln(os.Stderr, err)
 			os.Exit(1)
@@ -82,14 +102,13 @@ var signCmd = &cobra.Command{
 		}
 
 		// Signature
-		manager.OnProgressUpdate = signProgressFn
-		err = manager.Sign()
+		err = interrupts.cancellable(manager.Sign)
 		if err != nil {
 			fmt.Fprintln(os.Stderr, err)
 			os.Exit(1)
 		}
 
-		fmt.Println("Signature complete! See .proof file for evidences.")
+		printOutcome(manager)
 
 		// deleting the recover data file
 		err = os.Remove(filename)
@@ -100,6 +119,83 @@ var signCmd = &cobra.Command{
 	},
 }
 
+func resumeSignature(manager *sign.SignatureManager, journal string) {
+	fmt.Println("Resuming the interrupted signature from", journal)
+	err := manager.Resume(journal)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	printOutcome(manager)
+
+	// deleting the recover data file
+	err = os.Remove(journal)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+// interruptHandler cancels the signature on interruption, during the phases in which the manager reads its Cancel channel
+type interruptHandler struct {
+	mutex sync.Mutex
+	phase chan interface{} // closed at the end of the current cancellable phase, nil outside of them
+}
+
+// cancellable runs a phase of the signature that can be cancelled through the Cancel channel of the manager
+func (h *interruptHandler) cancellable(phase func() error) error {
+	done := make(chan interface{})
+	h.mutex.Lock()
+	h.phase = done
+	h.mutex.Unlock()
+
+	err := phase()
+
+	h.mutex.Lock()
+	h.phase = nil
+	h.mutex.Unlock()
+	close(done)
+	return err
+}
+
+// listen cancels the signature on the first interruption signal, and exits on the second one.
+// Once the signature is started, the cancellation goes through the ttp.
+// Outside of the cancellable phases, nothing reads the Cancel channel: the first interruption exits immediately.
+func (h *interruptHandler) listen(manager *sign.SignatureManager) {
+	c := make(chan os.Signal, 1)
+	signal.Notify(c, os.Interrupt)
+	<-c
+
+	h.mutex.Lock()
+	phase := h.phase
+	h.mutex.Unlock()
+	if phase == nil {
+		fmt.Println("Signature interrupted")
+		os.Exit(1)
+	}
+
+	fmt.Println("Cancelling the signature, interrupt again to exit immediately...")
+	select {
+	case manager.Cancel <- true:
+		<-c
+	case <-phase:
+	case <-c:
+	}
+	os.Exit(1)
+}
+
+func printOutcome(manager *sign.SignatureManager) {
+	switch manager.Outcome() {
+	case sign.OutcomeAborted:
+		fmt.Println("Signature aborted by the TTP, the contract is not signed.")
+	case sign.OutcomeResolved:
+		fmt.Println("Signature completed by the TTP! See .proof file for evidences.")
+	default:
+		fmt.Println("Signature complete! See .proof file for evidences.")
+	}
+}
+
 func checkContractHash(filename string, expectedHash string) bool {
 	if filename == "" {
 		return true
@@ -0,0 +1,72 @@
+package cmd
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"time"
+
+	"dfss/dfssc/common"
+	"dfss/dfssc/security"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var verifyCmd = &cobra.Command{
+	Use:   "verify <p>",
+	Short: "check the signed contract stored in proof file p, without connecting to the platform",
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) != 1 {
+			_ = cmd.Usage()
+			os.Exit(1)
+		}
+
+		data, err := ioutil.ReadFile(args[0])
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Cannot open file:", err)
+			os.Exit(1)
+		}
+
+		proof, err := common.UnmarshalProofFile(data)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Corrupted file:", err)
+			os.Exit(1)
+		}
+
+		ca, err := security.GetCertificate(viper.GetString("file_ca"))
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Cannot load root certificate:", err)
+			os.Exit(1)
+		}
+
+		contractPath, _ := cmd.Flags().GetString("contract")
+		if !checkContractHash(contractPath, proof.Contract.File.Hash) {
+			fmt.Fprintln(os.Stderr, "Invalid proof: the contract document does not match")
+			os.Exit(2)
+		}
+
+		var at time.Time
+		if atFlag, _ := cmd.Flags().GetString("at"); atFlag != "" {
+			at, err = time.Parse(time.RFC3339, atFlag)
+			if err != nil {
+				fmt.Fprintln(os.Stderr, "Invalid time:", err)
+				os.Exit(1)
+			}
+		}
+
+		expired, err := common.VerifyProof(proof, ca, at)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Invalid proof:", err)
+			os.Exit(2)
+		}
+
+		fmt.Println("Valid proof for document", proof.Contract.File.Hash)
+		fmt.Println("Signed by:")
+		for _, s := range proof.Contract.Signers {
+			fmt.Println("  -", s.Email)
+		}
+		for _, cert := range expired {
+			fmt.Println("Note: the certificate of", cert.Subject.CommonName, "has expired on", cert.NotAfter.Format(time.RFC3339), "since the signature")
+		}
+	},
+}
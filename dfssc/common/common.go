@@ -5,6 +5,7 @@ import (
 	"bytes"
 	"io/ioutil"
 	"os"
+	"path/filepath"
 )
 
 // SaveToDisk saves the given array of bytes to disk with the given filename
@@ -18,6 +19,44 @@ func SaveStringToDisk(str, filename string) error {
 	return ioutil.WriteFile(filename, buffer.Bytes(), 0644)
 }
 
+// ReplaceFile saves the given array of bytes to disk with the given filename, readable by the user only.
+// The data is written to a temporary file which is then renamed, so that a crash never leaves a truncated file behind,
+// and both the file and the directory are synced, so that the new file survives a power loss.
+func ReplaceFile(data []byte, filename string) error {
+	tmp := filename + ".tmp"
+	f, err := os.OpenFile(tmp, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+
+	_, err = f.Write(data)
+	if err == nil {
+		err = f.Sync()
+	}
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp, filename)
+	}
+	if err != nil {
+		_ = os.Remove(tmp)
+		return err
+	}
+
+	return syncDir(filepath.Dir(filename))
+}
+
+// syncDir commits the entries of the directory to the disk
+func syncDir(dir string) error {
+	d, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = d.Close() }()
+	return d.Sync()
+}
+
 // DeleteQuietly try to delete a file, do not fail if an error is raised
 func DeleteQuietly(filename string) {
 	_ = os.Remove(filename)
@@ -0,0 +1,18 @@
+package common
+
+import (
+	"fmt"
+
+	cAPI "dfss/dfssc/api"
+)
+
+// SignatureStatement returns the statement signed by each signer of a contract.
+// It binds the hash of the contract document to this specific signature attempt, certified by the platform seal.
+func SignatureStatement(context *cAPI.Context) []byte {
+	return []byte(fmt.Sprintf("dfss signature\n%x\n%s\n%x", context.ContractDocumentHash, context.SignatureUUID, context.Seal))
+}
+
+// PromiseLabel returns the label of a promise, binding its private contract signature to its sender, recipient and sequence index.
+func PromiseLabel(promise *cAPI.Promise) []byte {
+	return []byte(fmt.Sprintf("dfss promise\n%s\n%x\n%x\n%d", promise.Context.SignatureUUID, promise.Context.SenderKeyHash, promise.Context.RecipientKeyHash, promise.Index))
+}
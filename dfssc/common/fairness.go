@@ -0,0 +1,509 @@
+package common
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// MaxFairnessSigners is the maximal number of signers supported by CheckFairness, as the number of scenarios grows exponentially.
+const MaxFairnessSigners = 5
+
+// MaxFairnessSequenceLength is the maximal length of a sequence supported by CheckFairness.
+const MaxFairnessSequenceLength = 100
+
+// FairnessReport is the result of the analysis of a signing sequence, see CheckFairness.
+type FairnessReport struct {
+	Sequence  []uint32
+	Signers   int
+	Scenarios []FairnessScenario // One scenario per set of dishonest signers, the first one having only honest signers
+}
+
+// FairnessScenario contains the analysis of a sequence for a specific set of dishonest signers.
+type FairnessScenario struct {
+	Dishonest      []uint32 // Indexes of the dishonest signers, acting together
+	States         int      // Number of reachable states
+	Outcomes       []FairnessOutcome
+	Fair           bool
+	AbuseFree      bool
+	Counterexample []string // Steps leading to a fairness or abuse-freeness violation, empty if none
+}
+
+// FairnessOutcome is a final state of the protocol, with the number of executions reaching it.
+type FairnessOutcome struct {
+	Description string
+	Count       int
+}
+
+// Fair returns true if the sequence is fair in every scenario.
+func (r *FairnessReport) Fair() bool {
+	for _, s := range r.Scenarios {
+		if !s.Fair {
+			return false
+		}
+	}
+	return true
+}
+
+// AbuseFree returns true if the sequence is abuse-free in every scenario.
+func (r *FairnessReport) AbuseFree() bool {
+	for _, s := range r.Scenarios {
+		if !s.AbuseFree {
+			return false
+		}
+	}
+	return true
+}
+
+// CheckFairness explores every execution of the signing protocol for the provided sequence and n signers,
+// against every set of dishonest signers and every failure of the network.
+//
+// Signers follow the protocol of the sign package: at each occurrence in the sequence, a signer waits for the promises
+// of the pending set (see GetPendingSet), then sends its promises to the send set (see GetSendSet).
+// After its last occurrence, it sends its signature to every other signer and waits for theirs.
+// An honest signer can time out at any moment, it then resolves with the ttp using the promises of the rounds it has completed.
+//
+// Dishonest signers share their knowledge, can stop sending messages at any moment and resolve with the ttp at any step
+// they have the evidence for. They never send their signature.
+//
+// The ttp follows the resolve package: it generates the signed contract when every signer has promised at least once,
+// and answers with an abort token otherwise. An abort token is final for its recipient, and can only be overturned later
+// if every aborted signer is proven dishonest, that is to say if a promise sent after its resolve step is received.
+//
+// The sequence is fair if no honest signer ends with an abort token while another signer holds the signed contract.
+// It is abuse-free if dishonest signers never hold the signature of an honest signer while still being able to choose,
+// on their own, between getting the signed contract and nobody getting it.
+func CheckFairness(sequence []uint32, n int) (*FairnessReport, error) {
+	if n < 1 || n > MaxFairnessSigners {
+		return nil, fmt.Errorf("The number of signers must be between 1 and %d", MaxFairnessSigners)
+	}
+	if len(sequence) == 0 || len(sequence) > MaxFairnessSequenceLength {
+		return nil, fmt.Errorf("The length of the sequence must be between 1 and %d", MaxFairnessSequenceLength)
+	}
+	for _, s := range sequence {
+		if int(s) >= n {
+			return nil, errors.New("The sequence contains an invalid signer index: " + fmt.Sprint(s))
+		}
+	}
+
+	report := &FairnessReport{
+		Sequence: sequence,
+		Signers:  n,
+	}
+
+	// Every subset of the signers but the whole group, by increasing size
+	var coalitions []uint32
+	for c := uint32(0); c < 1<<uint(n)-1; c++ {
+		coalitions = append(coalitions, c)
+	}
+	sort.SliceStable(coalitions, func(i, j int) bool {
+		return bitCount(coalitions[i]) < bitCount(coalitions[j])
+	})
+
+	for _, c := range coalitions {
+		m := newFairnessModel(sequence, n, c)
+		report.Scenarios = append(report.Scenarios, m.explore())
+	}
+
+	return report, nil
+}
+
+// String returns a human-readable summary of the report.
+func (r *FairnessReport) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Sequence %v for %d signers\n", r.Sequence, r.Signers)
+	for _, s := range r.Scenarios {
+		fmt.Fprintf(&b, "\nDishonest signers %v: %d states, fair: %s, abuse-free: %s\n", s.Dishonest, s.States, yesNo(s.Fair), yesNo(s.AbuseFree))
+		for _, o := range s.Outcomes {
+			fmt.Fprintf(&b, "  %6d x %s\n", o.Count, o.Description)
+		}
+		if len(s.Counterexample) > 0 {
+			fmt.Fprintln(&b, "  Counterexample:")
+			for i, step := range s.Counterexample {
+				fmt.Fprintf(&b, "    %d. %s\n", i+1, step)
+			}
+		}
+	}
+	fmt.Fprintf(&b, "\nFair: %s\nAbuse-free: %s\n", yesNo(r.Fair()), yesNo(r.AbuseFree()))
+	return b.String()
+}
+
+func yesNo(b bool) string {
+	if b {
+		return "yes"
+	}
+	return "no"
+}
+
+func bitCount(c uint32) int {
+	count := 0
+	for ; c > 0; c >>= 1 {
+		count += int(c & 1)
+	}
+	return count
+}
+
+// Status of a signer in a fairnessState
+const (
+	fairRunning  byte = iota // honest signer still following the protocol, or dishonest signer that has not resolved yet
+	fairSigned               // honest signer that received every signature
+	fairContract             // signer that received the signed contract from the ttp
+	fairAborted              // signer that received an abort token from the ttp
+)
+
+// fairnessModel contains the static data of the exploration for a set of dishonest signers
+type fairnessModel struct {
+	sequence  []uint32
+	n         int
+	dishonest uint32
+	occ       [][]int                  // occurrences of each signer in the sequence
+	pending   [][][]SequenceCoordinate // pending set of each occurrence of each signer
+}
+
+// fairnessState is a global state of the protocol, encoded for use as a map key:
+//   - phase of each signer (number of completed occurrences, only for honest signers)
+//   - status of each signer
+//   - ttp contract flag
+//   - signers that have promised, as seen by the ttp
+//   - highest promise index received by the ttp for each signer, plus one
+//   - resolve step of each aborted signer, plus one
+type fairnessState []byte
+
+func newFairnessModel(sequence []uint32, n int, dishonest uint32) *fairnessModel {
+	m := &fairnessModel{
+		sequence:  sequence,
+		n:         n,
+		dishonest: dishonest,
+		occ:       make([][]int, n),
+		pending:   make([][][]SequenceCoordinate, n),
+	}
+	for i, s := range sequence {
+		m.occ[s] = append(m.occ[s], i)
+		set, _ := GetPendingSet(sequence, s, i)
+		m.pending[s] = append(m.pending[s], set)
+	}
+	return m
+}
+
+func (m *fairnessModel) isDishonest(i int) bool {
+	return m.dishonest&(1<<uint(i)) != 0
+}
+
+func (m *fairnessModel) initial() fairnessState {
+	return make(fairnessState, 4*m.n+2)
+}
+
+func (m *fairnessModel) phase(s fairnessState, i int) int   { return int(s[i]) }
+func (m *fairnessModel) status(s fairnessState, i int) byte { return s[m.n+i] }
+func (m *fairnessModel) ttpContract(s fairnessState) bool   { return s[2*m.n] != 0 }
+
+// hasSent returns true if the honest signer i has sent its promises for the occurrence at index
+func (m *fairnessModel) hasSent(s fairnessState, i int, index uint32) bool {
+	for p := 0; p < m.phase(s, i); p++ {
+		if m.occ[i][p] == int(index) {
+			return true
+		}
+	}
+	return false
+}
+
+// received returns true if every promise of the pending set of the occurrence p of signer i is available
+func (m *fairnessModel) received(s fairnessState, i, p int) bool {
+	for _, c := range m.pending[i][p] {
+		if !m.isDishonest(int(c.Signer)) && !m.hasSent(s, int(c.Signer), c.Index) {
+			return false
+		}
+	}
+	return true
+}
+
+// coalitionHasContract returns true if the dishonest signers hold the signed contract,
+// either from the ttp or because every honest signer has sent its signature
+func (m *fairnessModel) coalitionHasContract(s fairnessState) bool {
+	if m.dishonest == 0 {
+		return false
+	}
+	allSignatures := true
+	for i := 0; i < m.n; i++ {
+		if m.isDishonest(i) {
+			if m.status(s, i) == fairContract {
+				return true
+			}
+		} else if m.phase(s, i) < len(m.occ[i]) {
+			allSignatures = false
+		}
+	}
+	return allSignatures
+}
+
+// alert applies the resolve protocol of the ttp to a new state, and returns true if the signed contract is sent back
+func (m *fairnessModel) alert(s fairnessState, signer, step int, rounds int) bool {
+	n := m.n
+	if m.ttpContract(s) {
+		return true
+	}
+
+	evidence := []SequenceCoordinate{{Signer: uint32(signer), Index: uint32(step)}}
+	for p := 0; p < rounds; p++ {
+		evidence = append(evidence, m.pending[signer][p]...)
+	}
+	for _, c := range evidence {
+		s[2*n+1] |= 1 << c.Signer
+		if int(s[2*n+2+int(c.Signer)]) < int(c.Index)+1 {
+			s[2*n+2+int(c.Signer)] = byte(c.Index) + 1
+		}
+	}
+
+	solved := int(s[2*n+1]) == 1<<uint(n)-1
+	for i := 0; i < n && solved; i++ {
+		// aborted signers must be proven dishonest
+		abort := s[3*n+2+i]
+		solved = abort == 0 || s[2*n+2+i] > abort
+	}
+
+	if solved {
+		s[2*n] = 1
+		return true
+	}
+	s[3*n+2+signer] = byte(step) + 1
+	return false
+}
+
+// fairnessEvent is a transition between two states
+type fairnessEvent struct {
+	next        fairnessState
+	description string
+}
+
+// events returns the possible transitions from a state
+func (m *fairnessModel) events(s fairnessState) []fairnessEvent {
+	var res []fairnessEvent
+	next := func() fairnessState { return append(fairnessState{}, s...) }
+
+	for i := 0; i < m.n; i++ {
+		if m.status(s, i) != fairRunning {
+			continue
+		}
+
+		if m.isDishonest(i) {
+			if m.coalitionHasContract(s) {
+				continue
+			}
+			// Resolve at any step the coalition has the evidence for
+			steps := []int{0}
+			if len(m.occ[i]) > 0 && m.occ[i][0] == 0 {
+				steps = nil
+			}
+			steps = append(steps, m.occ[i]...)
+			for _, step := range steps {
+				rounds := 0
+				for rounds < len(m.occ[i]) && m.occ[i][rounds] <= step {
+					rounds++
+				}
+				ok := true
+				for p := 0; p < rounds && ok; p++ {
+					ok = m.received(s, i, p)
+				}
+				if !ok {
+					break
+				}
+				t := next()
+				if m.alert(t, i, step, rounds) {
+					t[m.n+i] = fairContract
+					res = append(res, fairnessEvent{t, fmt.Sprintf("dishonest signer %d resolves at step %d and gets the signed contract", i, step)})
+				} else {
+					t[m.n+i] = fairAborted
+					res = append(res, fairnessEvent{t, fmt.Sprintf("dishonest signer %d resolves at step %d and gets an abort token", i, step)})
+				}
+			}
+			continue
+		}
+
+		p := m.phase(s, i)
+		if p < len(m.occ[i]) {
+			// Promise round
+			if m.received(s, i, p) {
+				t := next()
+				t[i]++
+				desc := fmt.Sprintf("signer %d completes step %d", i, m.occ[i][p])
+				if p+1 == len(m.occ[i]) {
+					desc += " and sends its signature"
+				}
+				res = append(res, fairnessEvent{t, desc})
+			}
+		} else {
+			// Signature round
+			all := true
+			for j := 0; j < m.n && all; j++ {
+				all = j == i || (!m.isDishonest(j) && m.phase(s, j) == len(m.occ[j]))
+			}
+			if all {
+				t := next()
+				t[m.n+i] = fairSigned
+				res = append(res, fairnessEvent{t, fmt.Sprintf("signer %d receives every signature", i)})
+			}
+		}
+
+		// Timeout
+		step := 0
+		if p > 0 {
+			step = m.occ[i][p-1]
+		}
+		t := next()
+		if m.alert(t, i, step, p) {
+			t[m.n+i] = fairContract
+			res = append(res, fairnessEvent{t, fmt.Sprintf("signer %d times out, resolves at step %d and gets the signed contract", i, step)})
+		} else {
+			t[m.n+i] = fairAborted
+			res = append(res, fairnessEvent{t, fmt.Sprintf("signer %d times out, resolves at step %d and gets an abort token", i, step)})
+		}
+	}
+
+	return res
+}
+
+// unfair returns true if an honest signer has been aborted while another signer holds the signed contract
+func (m *fairnessModel) unfair(s fairnessState) bool {
+	aborted, signed := false, m.coalitionHasContract(s)
+	for i := 0; i < m.n; i++ {
+		if m.isDishonest(i) {
+			continue
+		}
+		switch m.status(s, i) {
+		case fairAborted:
+			aborted = true
+		case fairSigned, fairContract:
+			signed = true
+		}
+	}
+	return aborted && signed
+}
+
+// outcome describes a final state
+func (m *fairnessModel) outcome(s fairnessState) string {
+	var parts []string
+	for i := 0; i < m.n; i++ {
+		if m.isDishonest(i) {
+			continue
+		}
+		switch m.status(s, i) {
+		case fairSigned:
+			parts = append(parts, fmt.Sprintf("%d signed", i))
+		case fairContract:
+			parts = append(parts, fmt.Sprintf("%d resolved", i))
+		case fairAborted:
+			parts = append(parts, fmt.Sprintf("%d aborted", i))
+		}
+	}
+	if m.dishonest != 0 {
+		if m.coalitionHasContract(s) {
+			parts = append(parts, "dishonest signers hold the contract")
+		} else {
+			parts = append(parts, "dishonest signers do not hold the contract")
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// explore performs an exhaustive search of the states reachable from the initial one
+func (m *fairnessModel) explore() FairnessScenario {
+	scenario := FairnessScenario{Fair: true, AbuseFree: true}
+	for i := 0; i < m.n; i++ {
+		if m.isDishonest(i) {
+			scenario.Dishonest = append(scenario.Dishonest, uint32(i))
+		}
+	}
+
+	type node struct {
+		parent      string
+		description string
+		successors  []string
+	}
+
+	initial := m.initial()
+	nodes := map[string]*node{string(initial): {}}
+	order := []fairnessState{initial}
+	var unfairState string
+
+	// Breadth-first search, to get the shortest counterexamples
+	for i := 0; i < len(order); i++ {
+		s := order[i]
+		key := string(s)
+		for _, e := range m.events(s) {
+			k := string(e.next)
+			nodes[key].successors = append(nodes[key].successors, k)
+			if _, ok := nodes[k]; ok {
+				continue
+			}
+			nodes[k] = &node{parent: key, description: e.description}
+			order = append(order, e.next)
+			if unfairState == "" && m.unfair(e.next) {
+				unfairState = k
+			}
+		}
+	}
+	scenario.States = len(order)
+
+	trace := func(k string) []string {
+		var res []string
+		for k != string(initial) {
+			res = append([]string{nodes[k].description}, res...)
+			k = nodes[k].parent
+		}
+		return res
+	}
+
+	// Outcomes, and abilities of the dishonest signers, in reverse topological order
+	// (every transition makes the protocol progress, so the state graph is acyclic)
+	canContract := make(map[string]bool, len(order))
+	canAbort := make(map[string]bool, len(order))
+	outcomes := make(map[string]int)
+	var abuseState string
+	for i := len(order) - 1; i >= 0; i-- {
+		s := order[i]
+		key := string(s)
+		succ := nodes[key].successors
+		if len(succ) == 0 {
+			outcomes[m.outcome(s)]++
+			nobody := !m.coalitionHasContract(s)
+			for j := 0; j < m.n && nobody; j++ {
+				nobody = m.status(s, j) == fairAborted || m.isDishonest(j)
+			}
+			canAbort[key] = nobody
+		}
+		canContract[key] = m.coalitionHasContract(s)
+		for _, k := range succ {
+			canContract[key] = canContract[key] || canContract[k]
+			canAbort[key] = canAbort[key] || canAbort[k]
+		}
+
+		if m.dishonest != 0 && canContract[key] && canAbort[key] {
+			for j := 0; j < m.n; j++ {
+				if !m.isDishonest(j) && m.phase(s, j) == len(m.occ[j]) {
+					abuseState = key
+				}
+			}
+		}
+	}
+
+	for d, c := range outcomes {
+		scenario.Outcomes = append(scenario.Outcomes, FairnessOutcome{Description: d, Count: c})
+	}
+	sort.Slice(scenario.Outcomes, func(i, j int) bool {
+		return scenario.Outcomes[i].Description < scenario.Outcomes[j].Description
+	})
+
+	if unfairState != "" {
+		scenario.Fair = false
+		scenario.Counterexample = append(trace(unfairState), "unfair: "+m.outcome(fairnessState(unfairState)))
+	}
+	if abuseState != "" {
+		scenario.AbuseFree = false
+		if unfairState == "" {
+			scenario.Counterexample = append(trace(abuseState), "abuse: dishonest signers hold a signature, and can still choose between the signed contract and a global abort")
+		}
+	}
+
+	return scenario
+}
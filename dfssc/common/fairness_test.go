@@ -0,0 +1,113 @@
+package common
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckFairnessInvalid(t *testing.T) {
+	_, err := CheckFairness([]uint32{0, 1, 0}, 0)
+	assert.NotNil(t, err)
+
+	_, err = CheckFairness([]uint32{0, 1, 0}, MaxFairnessSigners+1)
+	assert.NotNil(t, err)
+
+	_, err = CheckFairness([]uint32{}, 2)
+	assert.NotNil(t, err)
+
+	_, err = CheckFairness([]uint32{0, 1, 2}, 2)
+	assert.NotNil(t, err)
+}
+
+func TestCheckFairnessSquared(t *testing.T) {
+	for n := 2; n <= 4; n++ {
+		var sequence []uint32
+		for i := 0; i < n; i++ {
+			for j := 0; j < n; j++ {
+				sequence = append(sequence, uint32(j))
+			}
+		}
+
+		report, err := CheckFairness(sequence, n)
+		assert.Nil(t, err)
+		assert.Equal(t, 1<<uint(n)-1, len(report.Scenarios))
+		assert.Equal(t, 0, len(report.Scenarios[0].Dishonest))
+		assert.True(t, report.Fair(), "%d signers", n)
+		assert.True(t, report.AbuseFree(), "%d signers", n)
+		for _, s := range report.Scenarios {
+			assert.Equal(t, 0, len(s.Counterexample))
+			assert.True(t, len(s.Outcomes) > 0)
+		}
+	}
+}
+
+func TestCheckFairnessUnfair(t *testing.T) {
+	// The second signer sends its signature without any proof that the first one did not abort
+	report, err := CheckFairness([]uint32{0, 1, 0}, 2)
+	assert.Nil(t, err)
+	assert.False(t, report.Fair())
+
+	scenario := report.Scenarios[1]
+	assert.Equal(t, []uint32{0}, scenario.Dishonest)
+	assert.False(t, scenario.Fair)
+	assert.Equal(t, []string{
+		"dishonest signer 0 resolves at step 0 and gets an abort token",
+		"signer 1 completes step 1 and sends its signature",
+		"signer 1 times out, resolves at step 1 and gets an abort token",
+		"unfair: 1 aborted, dishonest signers hold the contract",
+	}, scenario.Counterexample)
+
+	// Without any dishonest signer, every execution is fair
+	assert.True(t, report.Scenarios[0].Fair)
+
+	// Shorter sequences are only fair against smaller groups of dishonest signers
+	report, err = CheckFairness([]uint32{0, 1, 2, 3, 0, 1, 2, 3, 0, 1, 2, 3, 0}, 4)
+	assert.Nil(t, err)
+	assert.False(t, report.Fair())
+	for _, s := range report.Scenarios {
+		if len(s.Dishonest) <= 2 {
+			assert.True(t, s.Fair, "%v", s.Dishonest)
+		}
+	}
+}
+
+func TestCheckFairnessPermutations(t *testing.T) {
+	// Every fair sequence contains every permutation of the signers
+	sequences := [][]uint32{
+		{0, 1, 2, 0, 1, 2},
+		{0, 1, 2, 1, 0, 2, 1},
+		{0, 1, 2, 0, 1, 0, 2, 0, 1},
+		{0, 1, 2, 0, 2, 1, 0, 2, 0},
+	}
+	for _, sequence := range sequences {
+		report, err := CheckFairness(sequence, 3)
+		assert.Nil(t, err)
+		if report.Fair() {
+			assert.True(t, containsAllPermutations(sequence, 3), "%v", sequence)
+		}
+	}
+}
+
+// containsAllPermutations is a naive check, only usable with a few signers
+func containsAllPermutations(sequence []uint32, n int) bool {
+	var permute func(prefix []uint32, used int) bool
+	permute = func(prefix []uint32, used int) bool {
+		if len(prefix) == n {
+			i := 0
+			for _, s := range sequence {
+				if i < n && s == prefix[i] {
+					i++
+				}
+			}
+			return i == n
+		}
+		for x := 0; x < n; x++ {
+			if used&(1<<uint(x)) == 0 && !permute(append(prefix, uint32(x)), used|1<<uint(x)) {
+				return false
+			}
+		}
+		return true
+	}
+	return permute(nil, 0)
+}
@@ -3,7 +3,12 @@ package common
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
+	"io/ioutil"
 
+	"dfss/auth"
+	cAPI "dfss/dfssc/api"
+	pAPI "dfss/dfssp/api"
 	"dfss/dfssp/contract"
 )
 
@@ -22,11 +27,78 @@ func UnmarshalDFSSFile(data []byte) (*contract.JSON, error) {
 	return c, nil
 }
 
+// CheckDFSSFile checks the seal of the platform on a DFSS file, using the root certificate stored in caFile.
+// Files without any seal, generated by older platforms, are reported with contract.ErrUnsealed.
+func CheckDFSSFile(c *contract.JSON, caFile string) error {
+	data, err := ioutil.ReadFile(caFile)
+	if err != nil {
+		return fmt.Errorf("unable to check the seal of the platform: %v", err)
+	}
+	ca, err := auth.PEMToCertificate(data)
+	if err != nil {
+		return fmt.Errorf("unable to check the seal of the platform: %v", err)
+	}
+	return c.CheckSeal(ca)
+}
+
+// SignedContractJSON is an union of contract and related signatures.
+// It is the format of the proof files, either written after a signature or generated by the ttp.
+type SignedContractJSON struct {
+	Contract   contract.JSON
+	Signatures []cAPI.Signature
+}
+
+// UnmarshalProofFile decodes a json-encoded proof file
+func UnmarshalProofFile(data []byte) (*SignedContractJSON, error) {
+	p := &SignedContractJSON{}
+	err := json.Unmarshal(data, p)
+	if err != nil {
+		return nil, err
+	}
+
+	if p.Contract.File == nil {
+		return nil, errors.New("empty file description")
+	}
+
+	return p, nil
+}
+
 // RecoverDataJSON : contains all the necessary information to try and recover a previously signed contract from the ttp
+//
+// During a signature, it is also the journal of the client, updated after every round,
+// so that an interrupted signature can be resumed with all the evidence archived so far.
+// The journal fields are empty in files written before the first round.
 type RecoverDataJSON struct {
 	SignatureUUID string
 	TTPAddrport   string
 	TTPHash       []byte
+
+	ContractUUID      string                  `json:",omitempty"`
+	Sequence          []uint32                `json:",omitempty"`
+	SequenceGenerator string                  `json:",omitempty"`
+	KeyHash           [][]byte                `json:",omitempty"`
+	Seal              []byte                  `json:",omitempty"`
+	CurrentIndex      int                     // Index of the next round to play, -1 once the promise rounds are over
+	LastValidIndex    int                     // Index of the last completed round
+	ReceivedPromises  []*cAPI.Promise         `json:",omitempty"`
+	SentSignatures    []*cAPI.Signature       `json:",omitempty"`
+	RejectedEvidence  []*RejectedEvidenceJSON `json:",omitempty"`
+	Deadline          int64                   `json:",omitempty"` // Deadline of an asynchronous signature (unix time)
+	Certificates      [][]byte                `json:",omitempty"` // DER certificates of the signers of an asynchronous signature
+}
+
+// RejectedEvidenceJSON : an invalid promise or signature received during a signature, kept with the reason of its rejection
+type RejectedEvidenceJSON struct {
+	Promise   *cAPI.Promise   `json:",omitempty"`
+	Signature *cAPI.Signature `json:",omitempty"`
+	Sender    []byte          // Hash of the certificate of the authenticated peer
+	Code      pAPI.ErrorCode_Code
+	Reason    string
+}
+
+// IsJournal returns true if the recover data contains the journal of a signature, and not only the ttp information
+func (r *RecoverDataJSON) IsJournal() bool {
+	return r.ContractUUID != "" && len(r.Sequence) > 0
 }
 
 // UnmarshalRecoverDataFile decodes a json-encoded Recover dara file
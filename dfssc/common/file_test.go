@@ -6,6 +6,7 @@ import (
 	"io/ioutil"
 	"testing"
 
+	"dfss/dfssp/contract"
 	"github.com/stretchr/testify/assert"
 	"gopkg.in/mgo.v2/bson"
 )
@@ -20,6 +21,18 @@ func TestUnmarshalDFSSFile(t *testing.T) {
 	// a tiny test, full unmarshal is tested in dfss/dfssp/contract package
 }
 
+func TestCheckDFSSFile(t *testing.T) {
+	data, _ := ioutil.ReadFile("../testdata/file.dfss")
+	c, _ := UnmarshalDFSSFile(data)
+
+	// seals are tested in dfss/dfssp/contract package
+	assert.Equal(t, contract.ErrUnsealed, CheckDFSSFile(c, "../testdata/ca.pem"))
+
+	err := CheckDFSSFile(c, "../testdata/missing.pem")
+	assert.NotNil(t, err)
+	assert.NotEqual(t, contract.ErrUnsealed, err)
+}
+
 func TestUnmarshalRecoverDataFile(t *testing.T) {
 	bsonUUID := bson.NewObjectId()
 	uuid := bsonUUID.Hex()
@@ -43,4 +56,5 @@ func TestUnmarshalRecoverDataFile(t *testing.T) {
 	assert.Equal(t, uuid, unmarshal.SignatureUUID)
 	assert.Equal(t, ttpAddrport, unmarshal.TTPAddrport)
 	assert.Equal(t, ttpHash, unmarshal.TTPHash)
+	assert.False(t, unmarshal.IsJournal())
 }
@@ -0,0 +1,182 @@
+package common
+
+import (
+	"bytes"
+	"crypto/x509"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"dfss/auth"
+	cAPI "dfss/dfssc/api"
+	pAPI "dfss/dfssp/api"
+	"dfss/dfssp/contract"
+)
+
+// IsSealValid checks that the metadata stored in a context is correctly sealed by the platform.
+func IsSealValid(ca *x509.Certificate, context *cAPI.Context) bool {
+	var ttp *pAPI.LaunchSignature_TTP
+	if context.TtpAddrPort != "" {
+		ttp = &pAPI.LaunchSignature_TTP{
+			Addrport: context.TtpAddrPort,
+			Hash:     context.TtpHash,
+		}
+	}
+
+	theoric := pAPI.LaunchSignature{
+		SignatureUuid: context.SignatureUUID,
+		DocumentHash:  context.ContractDocumentHash,
+		KeyHash:       context.Signers,
+		Sequence:      context.Sequence,
+		Ttp:           ttp,
+
+		SequenceGenerator: context.SequenceGenerator,
+		Deadline:          context.Deadline,
+	}
+
+	ok, _ := auth.VerifyStructure(ca, theoric, context.Seal)
+	return ok
+}
+
+// VerifyProof checks a signed contract without any connection to the platform.
+//
+// The proof is valid if and only if:
+// - every context is sealed by the platform and matches the contract description;
+// - every signature is issued by a certificate signed by the provided root certificate, and valid at the provided time;
+// - every signer of the contract has signed it.
+//
+// A proof must stay checkable once the certificates of its signers have expired or been renewed: the certificates are
+// checked at the time of the signature, the creation date of the contract being used if the provided time is zero
+// (see ProofTime). The certificates that have expired since are returned, without invalidating the proof.
+func VerifyProof(proof *SignedContractJSON, ca *x509.Certificate, at time.Time) ([]*x509.Certificate, error) {
+	if proof.Contract.File == nil || len(proof.Contract.Signers) == 0 {
+		return nil, errors.New("empty contract description")
+	}
+	if len(proof.Signatures) == 0 {
+		return nil, errors.New("no signature found")
+	}
+	if err := proof.Contract.CheckSeal(ca); err != nil && err != contract.ErrUnsealed {
+		return nil, err
+	}
+	if at.IsZero() {
+		at = ProofTime(proof)
+	}
+
+	documentHash, err := hex.DecodeString(proof.Contract.File.Hash)
+	if err != nil {
+		return nil, fmt.Errorf("invalid document hash: %v", err)
+	}
+
+	signers := make([][]byte, len(proof.Contract.Signers))
+	for i, s := range proof.Contract.Signers {
+		signers[i], err = hex.DecodeString(s.Hash)
+		if err != nil {
+			return nil, fmt.Errorf("invalid hash for signer %s: %v", s.Email, err)
+		}
+	}
+
+	// All the signatures must share the same sealed context
+	reference := proof.Signatures[0].Context
+	if reference == nil {
+		return nil, errors.New("missing signature context")
+	}
+	if !IsSealValid(ca, reference) {
+		return nil, errors.New("invalid platform seal")
+	}
+	if !bytes.Equal(documentHash, reference.ContractDocumentHash) {
+		return nil, errors.New("document hash does not match the sealed one")
+	}
+	if len(signers) != len(reference.Signers) {
+		return nil, errors.New("signers do not match the sealed ones")
+	}
+	for i := range signers {
+		if !bytes.Equal(signers[i], reference.Signers[i]) {
+			return nil, errors.New("signers do not match the sealed ones")
+		}
+	}
+
+	signed := make([]*x509.Certificate, len(signers))
+	for i := range proof.Signatures {
+		id, cert, err := verifyProofSignature(&proof.Signatures[i], reference, proof, ca, at)
+		if err != nil {
+			return nil, fmt.Errorf("signature %d: %v", i, err)
+		}
+		signed[id] = cert
+	}
+
+	var expired []*x509.Certificate
+	for i, cert := range signed {
+		if cert == nil {
+			return nil, fmt.Errorf("missing signature of %s", proof.Contract.Signers[i].Email)
+		}
+		if time.Now().After(cert.NotAfter) {
+			expired = append(expired, cert)
+		}
+	}
+
+	return expired, nil
+}
+
+// ProofTime returns the default time at which the certificates of a proof are checked: the creation date of its
+// contract, as the signers are bound to their certificates from this date.
+// The zero time is returned if the contract has no date, as in the proofs generated by the TTP.
+func ProofTime(proof *SignedContractJSON) time.Time {
+	if proof.Contract.Date == nil {
+		return time.Time{}
+	}
+	return *proof.Contract.Date
+}
+
+// verifyProofSignature checks a single signature of a proof at the provided time, and returns the index and certificate of its signer.
+func verifyProofSignature(signature *cAPI.Signature, reference *cAPI.Context, proof *SignedContractJSON, ca *x509.Certificate, at time.Time) (uint32, *x509.Certificate, error) {
+	c := signature.Context
+	if c == nil {
+		return 0, nil, errors.New("missing context")
+	}
+	if c.SignatureUUID != reference.SignatureUUID || !bytes.Equal(c.Seal, reference.Seal) {
+		return 0, nil, errors.New("context does not belong to this signature")
+	}
+
+	id := -1
+	for i, s := range reference.Signers {
+		if bytes.Equal(s, c.SenderKeyHash) {
+			id = i
+			break
+		}
+	}
+	if id < 0 {
+		return 0, nil, errors.New("unknown signer")
+	}
+
+	certified, err := auth.ParseCertifiedSignature(signature.Payload)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	// The statement is computed from the sealed context, not the received one
+	statement := SignatureStatement(&cAPI.Context{
+		ContractDocumentHash: reference.ContractDocumentHash,
+		SignatureUUID:        reference.SignatureUUID,
+		Seal:                 reference.Seal,
+	})
+	cert, err := certified.Verify(statement)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	err = auth.CheckCertificateAt(cert, ca, c.SenderKeyHash, at)
+	if err, ok := err.(x509.CertificateInvalidError); ok && err.Reason == x509.Expired && at.IsZero() {
+		return 0, nil, errors.New("the certificate has expired, provide the time of the signature to check it")
+	}
+	if err != nil {
+		return 0, nil, err
+	}
+
+	email := proof.Contract.Signers[id].Email
+	if email != "" && email != cert.Subject.CommonName {
+		return 0, nil, fmt.Errorf("certificate does not belong to %s", email)
+	}
+
+	return uint32(id), cert, nil
+}
@@ -0,0 +1,158 @@
+package common
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha512"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"fmt"
+	"math/big"
+	"testing"
+	"time"
+
+	"dfss/auth"
+	cAPI "dfss/dfssc/api"
+	pAPI "dfss/dfssp/api"
+	"dfss/dfssp/contract"
+	"github.com/stretchr/testify/assert"
+)
+
+// newTestCertificate issues a certificate valid from an hour ago until notAfter, or a root certificate if ca is nil.
+func newTestCertificate(t *testing.T, cn string, notAfter time.Time, ca *x509.Certificate, caKey *rsa.PrivateKey) (*rsa.PrivateKey, *x509.Certificate) {
+	key, _ := auth.GeneratePrivateKey(1024)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: cn},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     notAfter,
+	}
+	if ca == nil {
+		template.IsCA = true
+		template.BasicConstraintsValid = true
+		template.KeyUsage = x509.KeyUsageCertSign
+		ca, caKey = template, key
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, ca, &key.PublicKey, caKey)
+	assert.Nil(t, err)
+	cert, _ := x509.ParseCertificate(der)
+	return key, cert
+}
+
+// newTestProof builds a proof signed by two signers, whose certificates are valid until notAfter.
+func newTestProof(t *testing.T, notAfter time.Time) (*SignedContractJSON, *x509.Certificate) {
+	caKey, ca := newTestCertificate(t, "platform", time.Now().Add(time.Hour), nil, nil)
+
+	documentHash := sha512.Sum512([]byte("document"))
+	proof := &SignedContractJSON{
+		Contract: contract.JSON{
+			File: &contract.FileJSON{Name: "document.pdf", Hash: fmt.Sprintf("%x", documentHash)},
+		},
+	}
+
+	var keys []*rsa.PrivateKey
+	var certs []*x509.Certificate
+	var hashes [][]byte
+	for _, mail := range []string{"a@example.com", "b@example.com"} {
+		key, cert := newTestCertificate(t, mail, notAfter, ca, caKey)
+		keys = append(keys, key)
+		certs = append(certs, cert)
+		hashes = append(hashes, auth.GetCertificateHash(cert))
+		proof.Contract.Signers = append(proof.Contract.Signers, contract.SignerJSON{
+			Email: mail,
+			Hash:  fmt.Sprintf("%x", hashes[len(hashes)-1]),
+		})
+	}
+
+	launch := pAPI.LaunchSignature{
+		SignatureUuid: "uuid",
+		DocumentHash:  documentHash[:],
+		KeyHash:       hashes,
+		Sequence:      []uint32{0, 1, 0, 1},
+	}
+	seal, err := auth.SignStructure(caKey, launch)
+	assert.Nil(t, err)
+
+	for i := range keys {
+		for j := range keys {
+			if i == j {
+				continue
+			}
+			context := &cAPI.Context{
+				RecipientKeyHash:     hashes[j],
+				SenderKeyHash:        hashes[i],
+				Sequence:             launch.Sequence,
+				Signers:              hashes,
+				ContractDocumentHash: documentHash[:],
+				SignatureUUID:        launch.SignatureUuid,
+				Seal:                 seal,
+			}
+			certified, err := auth.NewCertifiedSignature(keys[i], certs[i], SignatureStatement(context))
+			assert.Nil(t, err)
+			payload, _ := certified.Marshal()
+			proof.Signatures = append(proof.Signatures, cAPI.Signature{Context: context, Payload: payload})
+		}
+	}
+
+	return proof, ca
+}
+
+func TestVerifyProof(t *testing.T) {
+	proof, ca := newTestProof(t, time.Now().Add(time.Hour))
+	expired, err := VerifyProof(proof, ca, time.Time{})
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(expired))
+
+	// Bad root certificate
+	otherKey, _ := auth.GeneratePrivateKey(1024)
+	otherPem, _ := auth.GetSelfSignedCertificate(1, 0, "FR", "DFSS", "TEST", "platform", otherKey)
+	other, _ := auth.PEMToCertificate(otherPem)
+	assert.NotNil(t, verifyProofError(proof, other, time.Time{}))
+
+	// Bad document hash
+	hash := proof.Contract.File.Hash
+	proof.Contract.File.Hash = fmt.Sprintf("%x", sha512.Sum512([]byte("other")))
+	assert.NotNil(t, verifyProofError(proof, ca, time.Time{}))
+	proof.Contract.File.Hash = hash
+
+	// Bad signer email
+	proof.Contract.Signers[0].Email = "c@example.com"
+	assert.NotNil(t, verifyProofError(proof, ca, time.Time{}))
+	proof.Contract.Signers[0].Email = "a@example.com"
+
+	// Bad payload
+	proof.Signatures[1].Payload[len(proof.Signatures[1].Payload)-1]++
+	assert.NotNil(t, verifyProofError(proof, ca, time.Time{}))
+	proof.Signatures[1].Payload[len(proof.Signatures[1].Payload)-1]--
+
+	// Incomplete signer set
+	proof.Signatures = proof.Signatures[:1]
+	assert.NotNil(t, verifyProofError(proof, ca, time.Time{}))
+}
+
+func verifyProofError(proof *SignedContractJSON, ca *x509.Certificate, at time.Time) error {
+	_, err := VerifyProof(proof, ca, at)
+	return err
+}
+
+func TestVerifyProofExpired(t *testing.T) {
+	notAfter := time.Now().Add(-time.Minute)
+	proof, ca := newTestProof(t, notAfter)
+
+	// Without any time, the certificates are checked now
+	assert.NotNil(t, verifyProofError(proof, ca, time.Time{}))
+
+	// At the creation date of the contract, they are valid and reported as expired since
+	date := notAfter.Add(-time.Minute)
+	proof.Contract.Date = &date
+	expired, err := VerifyProof(proof, ca, time.Time{})
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(expired))
+
+	// The provided time takes precedence
+	assert.NotNil(t, verifyProofError(proof, ca, time.Now()))
+	expired, err = VerifyProof(proof, ca, notAfter.Add(-time.Second))
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(expired))
+}
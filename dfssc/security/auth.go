@@ -1,7 +1,7 @@
 package security
 
 import (
-	"crypto/rsa"
+	"crypto"
 	"crypto/x509"
 
 	"github.com/spf13/viper"
@@ -14,7 +14,7 @@ type AuthContainer struct {
 
 	CA   *x509.Certificate
 	Cert *x509.Certificate
-	Key  *rsa.PrivateKey
+	Key  crypto.Signer
 }
 
 // NewAuthContainer is a shortcut to build an AuthContainer
@@ -25,16 +25,21 @@ func NewAuthContainer(passphrase string) *AuthContainer {
 }
 
 // LoadFiles tries to load the required certificates and key for TLS authentication
-func (a *AuthContainer) LoadFiles() (ca *x509.Certificate, cert *x509.Certificate, key *rsa.PrivateKey, err error) {
-	ca, err = GetCertificate(viper.GetString("file_ca"))
+func (a *AuthContainer) LoadFiles() (ca *x509.Certificate, cert *x509.Certificate, key crypto.Signer, err error) {
+	return a.LoadFilesFrom(viper.GetString("file_ca"), viper.GetString("file_cert"), viper.GetString("file_key"))
+}
+
+// LoadFilesFrom tries to load the required certificates and key for TLS authentication from the specified files
+func (a *AuthContainer) LoadFilesFrom(caFile, certFile, keyFile string) (ca *x509.Certificate, cert *x509.Certificate, key crypto.Signer, err error) {
+	ca, err = GetCertificate(caFile)
 	if err != nil {
 		return
 	}
-	cert, err = GetCertificate(viper.GetString("file_cert"))
+	cert, err = GetCertificate(certFile)
 	if err != nil {
 		return
 	}
-	key, err = GetPrivateKey(viper.GetString("file_key"), a.Passphrase)
+	key, err = GetPrivateKey(keyFile, a.Passphrase)
 
 	a.CA = ca
 	a.Cert = cert
@@ -2,14 +2,13 @@ package security
 
 import (
 	"bytes"
+	"crypto"
 	"crypto/aes"
 	"crypto/cipher"
-	"crypto/rand"
-	"crypto/rsa"
 	"crypto/x509"
 	"encoding/base64"
 	"fmt"
-	"io"
+	"log"
 	"strings"
 
 	"dfss/auth"
@@ -31,8 +30,10 @@ func GetCertificate(filename string) (*x509.Certificate, error) {
 	return cert, nil
 }
 
-// GetPrivateKey return the private key stored on the disk
-func GetPrivateKey(filename, passphrase string) (*rsa.PrivateKey, error) {
+// GetPrivateKey return the private key stored on the disk.
+// If the file uses a legacy format, it is migrated to the current one when possible:
+// a failed migration is only logged, the key being usable anyway.
+func GetPrivateKey(filename, passphrase string) (crypto.Signer, error) {
 
 	data, err := common.ReadFile(filename)
 	if err != nil {
@@ -44,35 +45,34 @@ func GetPrivateKey(filename, passphrase string) (*rsa.PrivateKey, error) {
 		return nil, err
 	}
 
+	if auth.IsLegacyPEM(data) {
+		err = ReplacePrivateKey(key, filename, passphrase)
+		if err != nil {
+			log.Println("Unable to migrate the private key to the current format:", err)
+		}
+	}
+
 	return key, nil
 }
 
+// ReplacePrivateKey saves the private key in place of the current one, using the current format.
+// The file is replaced atomically, so that a key is never lost if the operation is interrupted, see common.ReplaceFile.
+func ReplacePrivateKey(key crypto.Signer, filename, passphrase string) error {
+	data, err := auth.PrivateKeyToEncryptedPEM(key, passphrase)
+	if err != nil {
+		return err
+	}
+	return common.ReplaceFile(data, filename)
+}
+
 // AES-256 requires a 32 bytes key, this function extend the key to this length
 func extendKey(key string) string {
 	key = strings.Repeat(key, 32/len(key)+1)
 	return key[:32]
 }
 
-// EncryptStringAES enciphers the data using AES-256 algorithm
-func EncryptStringAES(key string, data []byte) ([]byte, error) {
-	key = extendKey(key)
-	block, err := aes.NewCipher(bytes.NewBufferString(key).Bytes())
-	if err != nil {
-		return nil, err
-	}
-	b := base64.StdEncoding.EncodeToString(data)
-	ciphertext := make([]byte, aes.BlockSize+len(b))
-
-	iv := ciphertext[:aes.BlockSize]
-	if _, err := io.ReadFull(rand.Reader, iv); err != nil {
-		return nil, err
-	}
-	cfb := cipher.NewCFBEncrypter(block, iv)
-	cfb.XORKeyStream(ciphertext[aes.BlockSize:], []byte(b))
-	return ciphertext, nil
-}
-
-// DecryptAES deciphers the data using AES-256 algorithm
+// DecryptAES deciphers the data using AES-256 algorithm.
+// It is the legacy format of the exported configurations, see auth.DecryptWithPassphrase for the current one.
 func DecryptAES(key string, data []byte) ([]byte, error) {
 	key = extendKey(key)
 	block, err := aes.NewCipher(bytes.NewBufferString(key).Bytes())
@@ -2,7 +2,7 @@
 package security
 
 import (
-	"crypto/rsa"
+	"crypto"
 	"fmt"
 
 	"dfss/auth"
@@ -10,9 +10,10 @@ import (
 	"github.com/spf13/viper"
 )
 
-// GenerateKeys generate a pair of keys and save it to the disk
-func GenerateKeys(bits int, passphrase string) (*rsa.PrivateKey, error) {
-	key, err := auth.GeneratePrivateKey(bits)
+// GenerateKeys generate a pair of keys of the given type and save it to the disk.
+// The size in bits is only used for RSA keys.
+func GenerateKeys(keyType string, bits int, passphrase string) (crypto.Signer, error) {
+	key, err := auth.GenerateKey(keyType, bits)
 	if err != nil {
 		return nil, err
 	}
@@ -32,7 +33,7 @@ func GenerateKeys(bits int, passphrase string) (*rsa.PrivateKey, error) {
 
 // GenerateCertificateRequest generate a certificate request from data, and
 // return a PEM-encoded certificate as a string
-func GenerateCertificateRequest(country, organization, unit, mail string, key *rsa.PrivateKey) (string, error) {
+func GenerateCertificateRequest(country, organization, unit, mail string, key crypto.Signer) (string, error) {
 	data, err := auth.GetCertificateRequest(country, organization, unit, mail, key)
 	if err != nil {
 		return "", err
@@ -0,0 +1,114 @@
+package security
+
+import (
+	"crypto/x509"
+	"errors"
+	"io/ioutil"
+	"time"
+
+	"dfss/auth"
+	"dfss/dfssc/common"
+	pAPI "dfss/dfssp/api"
+	"dfss/net"
+	"golang.org/x/net/context"
+)
+
+// RevocationRetryDelay is the delay before trying again to refresh the revocation list after a failure
+var RevocationRetryDelay = time.Minute
+
+// UpdateRevocationList loads the certificate revocation list cached on the disk, and fetches a new one from the platform
+// if the cached one is missing or outdated. The resulting list is then used to reject revoked peers, see net.SetRevocationList.
+//
+// If filename is empty, the revocation list is always fetched from the platform and is not cached.
+// The request to the platform is bound to ctx.
+func UpdateRevocationList(ctx context.Context, platform pAPI.PlatformClient, ca *x509.Certificate, filename string) (*x509.RevocationList, error) {
+	var cached *x509.RevocationList
+	if filename != "" {
+		// A missing or corrupted cache is not an error, it is simply replaced
+		cached, _ = getRevocationList(filename, ca)
+	}
+
+	if cached != nil && time.Now().Before(cached.NextUpdate) {
+		net.SetRevocationList(cached)
+		return cached, nil
+	}
+
+	data, err := FetchRevocationList(ctx, platform)
+	if err != nil {
+		return nil, err
+	}
+
+	list, err := auth.PEMToRevocationList(data, ca)
+	if err != nil {
+		return nil, err
+	}
+
+	if cached != nil && list.Number.Cmp(cached.Number) < 0 {
+		return nil, errors.New("The platform sent an older revocation list than the cached one")
+	}
+
+	if filename != "" {
+		err = ioutil.WriteFile(filename, data, 0600)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	net.SetRevocationList(list)
+	return list, nil
+}
+
+// WatchRevocationList keeps the revocation list up to date for long-running clients, see UpdateRevocationList.
+// The list is refreshed when the next update of the current one is reached, each request being bound to the timeout.
+// Failures are reported to onError, if not nil, and tried again after RevocationRetryDelay.
+// It returns when ctx is done, and should be started in its own goroutine.
+func WatchRevocationList(ctx context.Context, platform pAPI.PlatformClient, ca *x509.Certificate, filename string, timeout time.Duration, onError func(error)) {
+	delay := RevocationRetryDelay
+	if current := net.GetRevocationList(ca); current != nil {
+		delay = current.NextUpdate.Sub(time.Now())
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(delay):
+		}
+
+		delay = RevocationRetryDelay
+		requestCtx, cancel := context.WithTimeout(ctx, timeout)
+		list, err := UpdateRevocationList(requestCtx, platform, ca, filename)
+		cancel()
+		if err != nil {
+			if ctx.Err() == nil && onError != nil {
+				onError(err)
+			}
+		} else if d := list.NextUpdate.Sub(time.Now()); d > 0 {
+			delay = d
+		}
+	}
+}
+
+// FetchRevocationList gets the current certificate revocation list from the platform, as a PEM-encoded array of bytes.
+// The list is not authenticated, see auth.PEMToRevocationList.
+func FetchRevocationList(ctx context.Context, platform pAPI.PlatformClient) ([]byte, error) {
+	response, err := platform.GetRevocationList(ctx, &pAPI.Empty{})
+	if err != nil {
+		return nil, err
+	}
+
+	err = common.EvaluateErrorCodeResponse(response.ErrorCode)
+	if err != nil {
+		return nil, err
+	}
+	return response.Crl, nil
+}
+
+// getRevocationList returns the authenticated revocation list stored on the disk
+func getRevocationList(filename string, ca *x509.Certificate) (*x509.RevocationList, error) {
+	data, err := common.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	return auth.PEMToRevocationList(data, ca)
+}
@@ -1,14 +1,25 @@
 package security
 
 import (
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
 	"fmt"
+	"io/ioutil"
+	"math/big"
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
+	"dfss/auth"
 	"dfss/dfssc/common"
+	pAPI "dfss/dfssp/api"
+	"dfss/net"
 	"github.com/spf13/viper"
 	"github.com/stretchr/testify/assert"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
 )
 
 var crtFixture = `-----BEGIN CERTIFICATE-----
@@ -35,7 +46,7 @@ func TestGenerateKeys(t *testing.T) {
 	fkey := filepath.Join(path, "genKey.pem")
 	viper.Set("file_key", fkey)
 
-	rsa, err := GenerateKeys(512, "pwd")
+	rsa, err := GenerateKeys(auth.KeyTypeRSA, 512, "pwd")
 	assert.True(t, err == nil, "An error has been raised during generation")
 	assert.True(t, rsa != nil, "RSA key should not be nil")
 	assert.True(t, common.FileExists(fkey), "File is missing")
@@ -47,7 +58,7 @@ func TestCertificateRequest(t *testing.T) {
 	fkey := filepath.Join(path, "genCsr.pem")
 	viper.Set("file_key", fkey)
 
-	rsa, err := GenerateKeys(512, "pwd")
+	rsa, err := GenerateKeys(auth.KeyTypeRSA, 512, "pwd")
 	defer common.DeleteQuietly(fkey)
 	assert.True(t, err == nil, "An error has been raised during generation")
 	assert.True(t, rsa != nil, "RSA key should not be nil")
@@ -63,7 +74,7 @@ func TestDumpingKey(t *testing.T) {
 	fkey := filepath.Join(path, "dumpKey.pem")
 	viper.Set("file_key", fkey)
 
-	rsa, err := GenerateKeys(512, "pwd")
+	rsa, err := GenerateKeys(auth.KeyTypeRSA, 512, "pwd")
 	defer common.DeleteQuietly(fkey)
 
 	assert.True(t, err == nil, "An error has been raised during generation")
@@ -78,10 +89,45 @@ func TestDumpingKey(t *testing.T) {
 
 	k, err = GetPrivateKey(fkey, "pwd")
 	assert.True(t, err == nil, "No error should have been raised")
-	assert.Equal(t, *rsa, *k, "Keys should be equal")
+	assert.Equal(t, rsa, k, "Keys should be equal")
 
 }
 
+// Test the migration of a legacy key on the disk
+func TestMigrateKey(t *testing.T) {
+	fkey := filepath.Join(path, "legacyKey.pem")
+	defer common.DeleteQuietly(fkey)
+
+	key, _ := auth.GeneratePrivateKey(512)
+	block, _ := x509.EncryptPEMBlock(rand.Reader, "RSA PRIVATE KEY", x509.MarshalPKCS1PrivateKey(key), []byte("pwd"), x509.PEMCipherAES256)
+	assert.Nil(t, common.SaveToDisk(pem.EncodeToMemory(block), fkey))
+
+	k, err := GetPrivateKey(fkey, "pwd")
+	assert.Nil(t, err)
+	assert.True(t, key.Equal(k))
+
+	data, _ := common.ReadFile(fkey)
+	assert.False(t, auth.IsLegacyPEM(data), "Key should have been migrated")
+	assert.True(t, auth.IsPEMEncrypted(data), "Key should still be encrypted")
+
+	k, err = GetPrivateKey(fkey, "pwd")
+	assert.Nil(t, err)
+	assert.True(t, key.Equal(k))
+
+	// A failed migration does not prevent the loading of the key
+	dir, _ := ioutil.TempDir("", "dfssc_security")
+	defer func() { _ = os.RemoveAll(dir) }()
+	fkey = filepath.Join(dir, "legacyKey.pem")
+	assert.Nil(t, common.SaveToDisk(pem.EncodeToMemory(block), fkey))
+	assert.Nil(t, os.Mkdir(fkey+".tmp", 0700))
+
+	k, err = GetPrivateKey(fkey, "pwd")
+	assert.Nil(t, err)
+	assert.True(t, key.Equal(k))
+	data, _ = common.ReadFile(fkey)
+	assert.True(t, auth.IsLegacyPEM(data))
+}
+
 // Test the saving of a certificate in a file
 func TestDumpCrt(t *testing.T) {
 	fcert := filepath.Join(path, "dumpCert.pem")
@@ -100,3 +146,115 @@ func TestDumpCrt(t *testing.T) {
 	assert.True(t, err == nil, "An error has been raised while parsing certificate")
 	assert.True(t, crt != nil, "Certificate is nil")
 }
+
+// crlPlatform is a platform client only able to serve a revocation list
+type crlPlatform struct {
+	pAPI.PlatformClient
+	crl   []byte
+	calls int
+}
+
+func (p *crlPlatform) GetRevocationList(ctx context.Context, in *pAPI.Empty, opts ...grpc.CallOption) (*pAPI.RevocationList, error) {
+	p.calls++
+	return &pAPI.RevocationList{ErrorCode: &pAPI.ErrorCode{Code: pAPI.ErrorCode_SUCCESS}, Crl: p.crl}, nil
+}
+
+// Test the caching and refreshing of the revocation list
+func TestUpdateRevocationList(t *testing.T) {
+	fcrl := filepath.Join(path, "crl.pem")
+	defer common.DeleteQuietly(fcrl)
+	defer net.SetRevocationList(nil)
+
+	caKey, _ := auth.GeneratePrivateKey(512)
+	caPem, _ := auth.GetSelfSignedCertificate(1, 1, "", "", "", "ca", caKey)
+	ca, _ := auth.PEMToCertificate(caPem)
+	revoked := []x509.RevocationListEntry{{SerialNumber: big.NewInt(42), RevocationTime: time.Now()}}
+
+	crl, err := auth.GetRevocationList(2, revoked, time.Hour, ca, caKey)
+	assert.Nil(t, err)
+	platform := &crlPlatform{crl: crl}
+
+	// Missing cache
+	list, err := UpdateRevocationList(context.Background(), platform, ca, fcrl)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, platform.calls)
+	assert.Equal(t, big.NewInt(2), list.Number)
+	assert.True(t, common.FileExists(fcrl))
+	assert.Equal(t, list, net.GetRevocationList(ca))
+
+	// Valid cache
+	_, err = UpdateRevocationList(context.Background(), platform, ca, fcrl)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, platform.calls)
+
+	// Outdated cache
+	platform.crl, _ = auth.GetRevocationList(3, revoked, time.Millisecond, ca, caKey)
+	assert.Nil(t, common.SaveToDisk(platform.crl, fcrl))
+	time.Sleep(10 * time.Millisecond)
+	_, err = UpdateRevocationList(context.Background(), platform, ca, fcrl)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, platform.calls)
+
+	// Older list than the cached one
+	platform.crl = crl
+	_, err = UpdateRevocationList(context.Background(), platform, ca, fcrl)
+	assert.NotNil(t, err)
+	assert.Equal(t, 3, platform.calls)
+
+	// List not signed by the ca
+	otherKey, _ := auth.GeneratePrivateKey(512)
+	platform.crl, _ = auth.GetRevocationList(4, nil, time.Hour, ca, otherKey)
+	_, err = UpdateRevocationList(context.Background(), platform, ca, "")
+	assert.NotNil(t, err)
+}
+
+// Test the refreshing of the revocation list once its next update is reached
+func TestWatchRevocationList(t *testing.T) {
+	defer net.SetRevocationList(nil)
+	defer func(d time.Duration) { RevocationRetryDelay = d }(RevocationRetryDelay)
+	RevocationRetryDelay = 10 * time.Millisecond
+
+	caKey, _ := auth.GeneratePrivateKey(512)
+	caPem, _ := auth.GetSelfSignedCertificate(1, 1, "", "", "", "ca", caKey)
+	ca, _ := auth.PEMToCertificate(caPem)
+
+	crl, _ := auth.GetRevocationList(1, nil, time.Millisecond, ca, caKey)
+	platform := &crlPlatform{crl: crl}
+	_, err := UpdateRevocationList(context.Background(), platform, ca, "")
+	assert.Nil(t, err)
+
+	// The next list is not signed by the ca, then valid
+	otherKey, _ := auth.GeneratePrivateKey(512)
+	platform.crl, _ = auth.GetRevocationList(2, nil, time.Hour, ca, otherKey)
+	errors := make(chan error, 10)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan bool)
+	go func() {
+		WatchRevocationList(ctx, platform, ca, "", time.Second, func(err error) { errors <- err })
+		done <- true
+	}()
+
+	select {
+	case err = <-errors:
+		assert.NotNil(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("The revocation list was not refreshed")
+	}
+	assert.Equal(t, big.NewInt(1), net.GetRevocationList(ca).Number)
+
+	crl, _ = auth.GetRevocationList(3, nil, time.Hour, ca, caKey)
+	cancel()
+	<-done
+	platform.crl = crl
+	ctx, cancel = context.WithCancel(context.Background())
+	go func() {
+		WatchRevocationList(ctx, platform, ca, "", time.Second, nil)
+		done <- true
+	}()
+	for i := 0; i < 100 && net.GetRevocationList(ca).Number.Cmp(big.NewInt(3)) != 0; i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+	cancel()
+	<-done
+	assert.Equal(t, big.NewInt(3), net.GetRevocationList(ca).Number)
+}
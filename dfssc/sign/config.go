@@ -0,0 +1,79 @@
+package sign
+
+import (
+	"time"
+
+	"dfss/dfssc/security"
+	dAPI "dfss/dfssd/api"
+	"dfss/net"
+	"github.com/spf13/viper"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+)
+
+// Config holds the settings of a signature.
+// ConfigFromViper builds it from the global configuration of the client.
+type Config struct {
+	PlatformAddrport string    // Address of the platform
+	LocalHost        string    // Address of the local server, every interface (IPv4 and IPv6) if empty
+	LocalPort        int       // Port of the local server, chosen by the system if zero
+	Advertise        []string  // Endpoints (host or host:port) announced to the peers, the addresses of the local server if empty
+	Listener         *Listener // Local server shared with other signatures, LocalHost and LocalPort are ignored if set
+
+	CAFile   string // Certificate of the platform
+	CertFile string // Certificate of the user
+	KeyFile  string // Private key of the user
+	CRLFile  string // Cache of the revocation list of the platform, optional
+
+	Timeout    time.Duration                // Timeout of the connections and requests, defaultTimeout if zero
+	Log        func(signer, message string) // Receives the messages for the demonstrator, optional
+	Slowdown   time.Duration                // Delay before each round, for tests and demonstrations
+	StopBefore int                          // Exit before the specified round (-1 for the signature round), for tests
+}
+
+// defaultTimeout is the timeout of the connections and requests when the configuration does not set any
+const defaultTimeout = 10 * time.Second
+
+// timeout returns the timeout of the connections and requests
+func (c *Config) timeout() time.Duration {
+	if c.Timeout <= 0 {
+		return defaultTimeout
+	}
+	return c.Timeout
+}
+
+// loadAuth loads the credentials of the user from the files of the configuration
+func (c *Config) loadAuth(passphrase string) (*security.AuthContainer, error) {
+	auth := security.NewAuthContainer(passphrase)
+	_, _, _, err := auth.LoadFilesFrom(c.CAFile, c.CertFile, c.KeyFile)
+	if err != nil {
+		return nil, err
+	}
+	return auth, nil
+}
+
+// connectPlatform connects to the platform with the provided credentials, within the timeout of the configuration
+func (c *Config) connectPlatform(ctx context.Context, auth *security.AuthContainer) (*grpc.ClientConn, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout())
+	defer cancel()
+	conn, _, err := net.ConnectContext(ctx, c.PlatformAddrport, auth.Cert, auth.Key, auth.CA, nil)
+	return conn, err
+}
+
+// ConfigFromViper builds the configuration of a signature from the global configuration of the client
+func ConfigFromViper() *Config {
+	return &Config{
+		PlatformAddrport: viper.GetString("platform_addrport"),
+		LocalHost:        viper.GetString("local_host"),
+		LocalPort:        viper.GetInt("local_port"),
+		Advertise:        viper.GetStringSlice("local_advertise"),
+		CAFile:           viper.GetString("file_ca"),
+		CertFile:         viper.GetString("file_cert"),
+		KeyFile:          viper.GetString("file_key"),
+		CRLFile:          viper.GetString("file_crl"),
+		Timeout:          viper.GetDuration("timeout"),
+		Log:              dAPI.DLogFrom,
+		Slowdown:         viper.GetDuration("slowdown"),
+		StopBefore:       viper.GetInt("stopbefore"),
+	}
+}
@@ -2,37 +2,62 @@ package sign
 
 import (
 	"crypto/sha512"
+	"io"
 	"io/ioutil"
+	"os"
 	"path/filepath"
+	"time"
 
 	"dfss/dfssc/common"
 	"dfss/dfssc/security"
 	"dfss/dfssp/api"
-	"dfss/net"
-	"github.com/spf13/viper"
 	"golang.org/x/net/context"
 )
 
+// documentTimeout is the timeout of the upload or download of a hosted document
+var documentTimeout = 10 * time.Minute
+
+// documentChunkSize is the size of the chunks of an uploaded document
+const documentChunkSize = 64 << 10
+
 // CreateManager handles the creation of a new contract.
 type CreateManager struct {
+	config   *Config
 	auth     *security.AuthContainer
 	filepath string
 	comment  string
 	signers  []string
+	sequence string
+	deadline time.Duration
+	upload   bool
 	hash     []byte
 	filename string
 }
 
-// SendNewContract tries to create a contract on the platform and returns an error or nil
-func SendNewContract(passphrase, filepath, comment string, signers []string) error {
+// SendNewContract tries to create a contract on the platform and returns an error or nil.
+//
+// The sequence parameter is the name of the signing sequence generator to use, or empty for the default one.
+// The deadline parameter is the delay signers have to sign asynchronously, through the mailboxes of the platform,
+// or zero for a synchronous signature.
+// The upload parameter uploads the document itself before the contract, for the platform to host it for the signers.
+func SendNewContract(config *Config, passphrase, filepath, comment string, signers []string, sequence string, deadline time.Duration, upload bool) error {
+	auth, err := config.loadAuth(passphrase)
+	if err != nil {
+		return err
+	}
+
 	m := &CreateManager{
-		auth:     security.NewAuthContainer(passphrase),
+		config:   config,
+		auth:     auth,
 		filepath: filepath,
 		comment:  comment,
 		signers:  signers,
+		sequence: sequence,
+		deadline: deadline,
+		upload:   upload,
 	}
 
-	err := m.computeFile()
+	err = m.computeFile()
 	if err != nil {
 		return err
 	}
@@ -61,26 +86,32 @@ func (m *CreateManager) computeFile() error {
 
 // sendRequest sends a new contract request for the platform and send it
 func (m *CreateManager) sendRequest() (*api.ErrorCode, error) {
-
-	ca, cert, key, err := m.auth.LoadFiles()
+	conn, err := m.config.connectPlatform(context.Background(), m.auth)
 	if err != nil {
 		return nil, err
 	}
+	defer func() { _ = conn.Close() }()
 
-	conn, err := net.Connect(viper.GetString("platform_addrport"), cert, key, ca, nil)
-	if err != nil {
-		return nil, err
+	client := api.NewPlatformClient(conn)
+	if m.upload {
+		if err = m.uploadDocument(client); err != nil {
+			return nil, err
+		}
 	}
 
 	request := &api.PostContractRequest{
-		Hash:     m.hash,
-		Filename: m.filename,
-		Signer:   m.signers,
-		Comment:  m.comment,
+		Hash:              m.hash,
+		Filename:          m.filename,
+		Signer:            m.signers,
+		Comment:           m.comment,
+		SequenceGenerator: m.sequence,
+		Hosted:            m.upload,
+	}
+	if m.deadline > 0 {
+		request.Deadline = time.Now().Add(m.deadline).Unix()
 	}
 
-	client := api.NewPlatformClient(conn)
-	ctx, cancel := context.WithTimeout(context.Background(), net.DefaultTimeout)
+	ctx, cancel := context.WithTimeout(context.Background(), m.config.timeout())
 	defer cancel()
 	response, err := client.PostContract(ctx, request)
 	if err != nil {
@@ -89,3 +120,46 @@ func (m *CreateManager) sendRequest() (*api.ErrorCode, error) {
 
 	return response, nil
 }
+
+// uploadDocument streams the document to the platform, which checks it against its hash before hosting it
+func (m *CreateManager) uploadDocument(client api.PlatformClient) error {
+	f, err := os.Open(m.filepath)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = f.Close() }()
+
+	ctx, cancel := context.WithTimeout(context.Background(), documentTimeout)
+	defer cancel()
+	stream, err := client.PostDocument(ctx)
+	if err != nil {
+		return err
+	}
+
+	chunk := &api.DocumentChunk{Hash: m.hash}
+	buffer := make([]byte, documentChunkSize)
+	for {
+		n, err := f.Read(buffer)
+		if n > 0 || chunk.Hash != nil { // The hash is always sent, even for an empty document
+			chunk.Data = buffer[:n]
+			if sendErr := stream.Send(chunk); sendErr == io.EOF {
+				break // Rejected by the platform, the reason is given by CloseAndRecv
+			} else if sendErr != nil {
+				return sendErr
+			}
+			chunk = &api.DocumentChunk{}
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	response, err := stream.CloseAndRecv()
+	if err != nil {
+		return err
+	}
+	return common.EvaluateErrorCodeResponse(response)
+}
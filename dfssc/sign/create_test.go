@@ -23,6 +23,20 @@ var addrPort = "localhost:9090"
 
 func TestMain(m *testing.M) {
 
+	// Use a copy of the key, as loading it migrates its format
+	dir, err := ioutil.TempDir("", "dfssc_sign")
+	if err != nil {
+		os.Exit(1)
+	}
+	keyFixture, err := ioutil.ReadFile(fkey)
+	if err != nil {
+		os.Exit(1)
+	}
+	fkey = filepath.Join(dir, "key.pem")
+	if ioutil.WriteFile(fkey, keyFixture, 0600) != nil {
+		os.Exit(1)
+	}
+
 	// Load ca and key for platform
 	caData, err := ioutil.ReadFile(fca)
 	if err != nil {
@@ -47,14 +61,16 @@ func TestMain(m *testing.M) {
 	go server.Run(ca, key, addrPort)
 	time.Sleep(2 * time.Second)
 
-	os.Exit(m.Run())
+	code := m.Run()
+	_ = os.RemoveAll(dir)
+	os.Exit(code)
 }
 
 func TestNewCreateManager(t *testing.T) {
-	err := SendNewContract("password", fcontract, "success", []string{"a@example.com", "b@example.com"})
+	err := SendNewContract(ConfigFromViper(), "password", fcontract, "success", []string{"a@example.com", "b@example.com"}, "", 0, false)
 	assert.Equal(t, nil, err)
 
-	err = SendNewContract("password", fcontract, "warning", []string{"a@example.com", "b@example.com"})
+	err = SendNewContract(ConfigFromViper(), "password", fcontract, "warning", []string{"a@example.com", "b@example.com"}, "", 0, false)
 	assert.Equal(t, "Operation succeeded with a warning message: Some users are not ready yet", err.Error())
 }
 
@@ -0,0 +1,95 @@
+package sign
+
+import (
+	"bytes"
+	"crypto/x509"
+	"errors"
+	"fmt"
+
+	"dfss/auth"
+	cAPI "dfss/dfssc/api"
+	"dfss/dfssc/common"
+	pAPI "dfss/dfssp/api"
+	"dfss/net"
+	"github.com/golang/protobuf/proto"
+)
+
+// sealEnvelope signs an envelope and encrypts it for its recipient, so that the platform carrying it can neither read nor forge it.
+func (m *SignatureManager) sealEnvelope(recipient *x509.Certificate, envelope *cAPI.Envelope) ([]byte, error) {
+	data, err := proto.Marshal(envelope)
+	if err != nil {
+		return nil, err
+	}
+
+	signature, err := auth.NewCertifiedSignature(m.auth.Key, m.auth.Cert, data)
+	if err != nil {
+		return nil, err
+	}
+	signed, err := signature.Marshal()
+	if err != nil {
+		return nil, err
+	}
+
+	data, err = proto.Marshal(&cAPI.SignedEnvelope{Envelope: data, Signature: signed})
+	if err != nil {
+		return nil, err
+	}
+	return auth.EncryptFor(recipient, data, []byte(m.uuid))
+}
+
+// openEnvelope decrypts an envelope sealed with sealEnvelope, and returns it with the certificate hash of its signer.
+func (m *SignatureManager) openEnvelope(payload []byte) (*cAPI.Envelope, []byte, error) {
+	data, err := auth.DecryptWith(m.auth.Key, payload, []byte(m.uuid))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	signed := &cAPI.SignedEnvelope{}
+	err = proto.Unmarshal(data, signed)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	certified, err := auth.ParseCertifiedSignature(signed.Signature)
+	if err != nil {
+		return nil, nil, err
+	}
+	cert, err := certified.Verify(signed.Envelope)
+	if err != nil {
+		return nil, nil, err
+	}
+	signer := auth.GetCertificateHash(cert)
+	if err = auth.CheckCertificate(cert, m.auth.CA, signer); err != nil {
+		return nil, nil, err
+	}
+	if net.IsRevoked(cert) {
+		return nil, nil, errors.New("revoked signer")
+	}
+
+	envelope := &cAPI.Envelope{}
+	err = proto.Unmarshal(signed.Envelope, envelope)
+	return envelope, signer, err
+}
+
+// treatEnvelope opens an evidence carried by the platform and routes it to the protocol.
+// The evidence is authenticated by the signature of its sender, the sender announced by the platform must be the same.
+func (m *SignatureManager) treatEnvelope(sender, payload []byte) {
+	envelope, signer, err := m.openEnvelope(payload)
+	if err != nil {
+		m.dlog(fmt.Sprintf("dropped an unreadable evidence from %x: %v", sender, err))
+		return
+	}
+
+	if !bytes.Equal(sender, signer) {
+		rejected := &common.RejectedEvidenceJSON{Promise: envelope.Promise, Signature: envelope.Signature}
+		m.rejectEvidence(sender, rejected, &pAPI.ErrorCode{Code: pAPI.ErrorCode_BADAUTH, Message: "sender does not match the signer of the envelope"})
+		return
+	}
+
+	if envelope.Promise != nil {
+		m.treatPromise(signer, envelope.Promise)
+	} else if envelope.Signature != nil {
+		m.treatSignature(signer, envelope.Signature)
+	}
+	// ready signals are acknowledged by the relay itself
+}
@@ -0,0 +1,44 @@
+package sign
+
+import (
+	"testing"
+
+	"dfss/auth"
+	cAPI "dfss/dfssc/api"
+	"github.com/golang/protobuf/proto"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOpenEnvelope(t *testing.T) {
+	m := newTestSignatureManager(t)
+	envelope := &cAPI.Envelope{Ready: &cAPI.ReadySignal{SignatureUUID: m.uuid}}
+
+	payload, err := m.sealEnvelope(m.auth.Cert, envelope)
+	assert.Nil(t, err)
+	opened, signer, err := m.openEnvelope(payload)
+	assert.Nil(t, err)
+	assert.Equal(t, m.keyHash[0], signer)
+	assert.Equal(t, m.uuid, opened.Ready.SignatureUUID)
+
+	// Envelopes are bound to the signature
+	m.uuid = "other"
+	_, _, err = m.openEnvelope(payload)
+	assert.NotNil(t, err)
+
+	// The platform cannot replace the content of an envelope
+	data, _ := auth.DecryptWith(m.auth.Key, payload, []byte("signature"))
+	signed := &cAPI.SignedEnvelope{}
+	assert.Nil(t, proto.Unmarshal(data, signed))
+	signed.Envelope, _ = proto.Marshal(&cAPI.Envelope{Ready: &cAPI.ReadySignal{SignatureUUID: "other"}})
+	data, _ = proto.Marshal(signed)
+	payload, _ = auth.EncryptFor(m.auth.Cert, data, []byte(m.uuid))
+	_, _, err = m.openEnvelope(payload)
+	assert.NotNil(t, err)
+
+	// Unsigned envelopes are rejected
+	signed.Signature = nil
+	data, _ = proto.Marshal(signed)
+	payload, _ = auth.EncryptFor(m.auth.Cert, data, []byte(m.uuid))
+	_, _, err = m.openEnvelope(payload)
+	assert.NotNil(t, err)
+}
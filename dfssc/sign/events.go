@@ -0,0 +1,129 @@
+package sign
+
+import (
+	"dfss/dfssp/contract"
+	"golang.org/x/net/context"
+)
+
+// EventType identifies the kind of an Event.
+type EventType int
+
+// These constants represent the different events of a signature.
+const (
+	EventSignerStatus      EventType = iota // a peer changed of status, see Mail, Status and Data
+	EventRoundStarted                       // a promise round started at Index
+	EventRoundEnded                         // a promise round ended at Index, every due promise was received and sent
+	EventPromiseReceived                    // a valid promise for Index was received from Mail
+	EventPromiseSent                        // a promise for Index was sent to Mail
+	EventSignatureReceived                  // a valid signature was received from Mail
+	EventSignatureSent                      // our signature was sent to Mail
+	EventTTPCall                            // the ttp is contacted, with the resolve Index
+	EventOutcome                            // the signature is terminated, see Outcome and Err
+)
+
+// Event is sent on the event channel of a signature started with Start.
+// Only the fields related to its type are set.
+type Event struct {
+	Type    EventType
+	Mail    string
+	Status  SignerStatus
+	Data    string
+	Index   int
+	Outcome SignatureOutcome
+	Err     error
+}
+
+// Start connects to the platform and runs a whole signature in the background, without any user interaction:
+// connection to the peers, ready signal, promises rounds and signature round.
+//
+// The events of the signature are sent on the returned channel, which must be drained by the caller.
+// The last event is always an EventOutcome, after which the channel is closed.
+// Cancelling the context cancels the signature; once the promises rounds are started, the ttp is contacted.
+func Start(ctx context.Context, config *Config, passphrase string, c *contract.JSON) (<-chan Event, error) {
+	m, err := newSignatureManager(ctx, passphrase, c, config)
+	if err != nil {
+		return nil, err
+	}
+
+	m.events = make(chan Event, chanBufferSize)
+	m.eventsDone = make(chan struct{})
+	go m.run(ctx)
+	return m.events, nil
+}
+
+// run performs the signature, forwarding the cancellation of the context to the Cancel channel
+func (m *SignatureManager) run(ctx context.Context) {
+	done := make(chan interface{})
+	go func() {
+		select {
+		case <-ctx.Done():
+			select {
+			case m.Cancel <- true:
+			case <-done:
+			}
+		case <-done:
+		}
+	}()
+
+	err := m.ConnectToPeers()
+	if err == nil {
+		_, err = m.SendReadySign()
+	}
+	if err == nil {
+		err = m.Sign()
+	}
+	close(done)
+
+	m.emit(Event{Type: EventOutcome, Outcome: m.outcome, Err: err})
+	m.closeEvents()
+}
+
+// closeEvents closes the event channel.
+// Goroutines of the signature may still be running, their late events are dropped.
+func (m *SignatureManager) closeEvents() {
+	m.eventsMutex.Lock()
+	m.eventsClosed = true
+	close(m.eventsDone)
+	m.eventsMutex.Unlock()
+
+	// Pending sends are released by eventsDone, the channel can then be closed safely
+	m.eventsSending.Wait()
+	close(m.events)
+}
+
+// emit sends the event to the event channel, if the signature was started with Start and is not terminated.
+// The mutex is not held during the send, which blocks until the caller reads the event or the channel is closed.
+func (m *SignatureManager) emit(e Event) {
+	if m.events == nil {
+		return
+	}
+
+	m.eventsMutex.Lock()
+	if m.eventsClosed {
+		m.eventsMutex.Unlock()
+		return
+	}
+	m.eventsSending.Add(1)
+	m.eventsMutex.Unlock()
+	defer m.eventsSending.Done()
+
+	select {
+	case m.events <- e:
+	case <-m.eventsDone:
+	}
+}
+
+// updateSignerStatus reports a new status for a peer, to the callback and to the event channel
+func (m *SignatureManager) updateSignerStatus(mail string, status SignerStatus, data string) {
+	if m.OnSignerStatusUpdate != nil {
+		m.OnSignerStatusUpdate(mail, status, data)
+	}
+	m.emit(Event{Type: EventSignerStatus, Mail: mail, Status: status, Data: data})
+}
+
+// updateProgress reports the progress of the signature to the callback
+func (m *SignatureManager) updateProgress(current, end int) {
+	if m.OnProgressUpdate != nil {
+		m.OnProgressUpdate(current, end)
+	}
+}
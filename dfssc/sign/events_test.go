@@ -0,0 +1,61 @@
+package sign
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEvents(t *testing.T) {
+	m := newTestSignatureManager(t)
+	m.archives = &Archives{}
+	m.ttp = &fakeTTP{}
+	m.lastValidIndex = 2
+
+	// Without event channel nor callback
+	m.updateSignerStatus("other@example.com", StatusConnected, "v")
+	m.updateProgress(1, 2)
+
+	var status SignerStatus
+	m.OnSignerStatusUpdate = func(mail string, s SignerStatus, data string) { status = s }
+	m.events = make(chan Event, chanBufferSize)
+	m.eventsDone = make(chan struct{})
+
+	m.updateSignerStatus("other@example.com", StatusError, "unreachable")
+	assert.Equal(t, StatusError, status)
+	assert.Equal(t, Event{Type: EventSignerStatus, Mail: "other@example.com", Status: StatusError, Data: "unreachable"}, <-m.events)
+
+	assert.Nil(t, m.resolve())
+	assert.Equal(t, Event{Type: EventTTPCall, Index: 2}, <-m.events)
+	assert.Equal(t, 0, len(m.events))
+
+	// Late events are dropped once the channel is closed
+	m.closeEvents()
+	m.updateSignerStatus("other@example.com", StatusConnected, "v")
+	_, open := <-m.events
+	assert.False(t, open)
+}
+
+func TestEventsCloseReleasesPendingSend(t *testing.T) {
+	m := newTestSignatureManager(t)
+	m.events = make(chan Event)
+	m.eventsDone = make(chan struct{})
+
+	// Nobody reads the channel, the send is pending
+	sent := make(chan bool)
+	go func() {
+		m.updateSignerStatus("other@example.com", StatusConnected, "v")
+		sent <- true
+	}()
+	time.Sleep(10 * time.Millisecond)
+
+	m.closeEvents()
+	select {
+	case <-sent:
+	case <-time.After(time.Second):
+		t.Fatal("the pending send was not released")
+	}
+	_, open := <-m.events
+	assert.False(t, open)
+}
@@ -1,34 +1,38 @@
 package sign
 
 import (
+	"crypto/sha512"
+	"errors"
+	"fmt"
+	"io"
 	"io/ioutil"
+	"os"
+	"path/filepath"
 
 	"dfss/dfssc/common"
-	"dfss/dfssc/security"
 	"dfss/dfssp/api"
-	"dfss/net"
-	"github.com/spf13/viper"
+	"dfss/dfssp/contract"
 	"golang.org/x/net/context"
 )
 
 // FetchContract tries to download contract metadata from specified uuid, and stores the resulting json at path
-func FetchContract(passphrase, uuid, path string) error {
-	auth := security.NewAuthContainer(passphrase)
-	ca, cert, key, err := auth.LoadFiles()
+func FetchContract(config *Config, passphrase, uuid, path string) error {
+	auth, err := config.loadAuth(passphrase)
 	if err != nil {
 		return err
 	}
 
-	conn, err := net.Connect(viper.GetString("platform_addrport"), cert, key, ca, nil)
+	conn, err := config.connectPlatform(context.Background(), auth)
 	if err != nil {
 		return err
 	}
+	defer func() { _ = conn.Close() }()
 
 	request := &api.GetContractRequest{
 		Uuid: uuid,
 	}
 	client := api.NewPlatformClient(conn)
-	ctx, cancel := context.WithTimeout(context.Background(), net.DefaultTimeout)
+	ctx, cancel := context.WithTimeout(context.Background(), config.timeout())
 	defer cancel()
 	response, err := client.GetContract(ctx, request)
 	if err != nil {
@@ -42,3 +46,66 @@ func FetchContract(passphrase, uuid, path string) error {
 
 	return ioutil.WriteFile(path, response.Json, 0600)
 }
+
+// FetchDocument downloads the document of a contract hosted on the platform, and stores it at path.
+// The document is checked against the hash of the contract before being stored.
+func FetchDocument(config *Config, passphrase string, c *contract.JSON, path string) error {
+	if c.File == nil || !c.File.Hosted {
+		return errors.New("The document of this contract is not hosted on the platform")
+	}
+
+	auth, err := config.loadAuth(passphrase)
+	if err != nil {
+		return err
+	}
+
+	conn, err := config.connectPlatform(context.Background(), auth)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = conn.Close() }()
+
+	client := api.NewPlatformClient(conn)
+	ctx, cancel := context.WithTimeout(context.Background(), documentTimeout)
+	defer cancel()
+	stream, err := client.GetDocument(ctx, &api.GetContractRequest{Uuid: c.UUID})
+	if err != nil {
+		return err
+	}
+
+	tmp, err := ioutil.TempFile(filepath.Dir(path), ".dfss-")
+	if err != nil {
+		return err
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }()
+
+	h := sha512.New()
+	err = receiveDocument(stream, io.MultiWriter(tmp, h))
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+
+	if fmt.Sprintf("%x", h.Sum(nil)) != c.File.Hash {
+		return errors.New("The downloaded document does not match the hash of the contract")
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// receiveDocument writes the chunks of a hosted document to w
+func receiveDocument(stream api.Platform_GetDocumentClient, w io.Writer) error {
+	for {
+		chunk, err := stream.Recv()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if _, err = w.Write(chunk.Data); err != nil {
+			return err
+		}
+	}
+}
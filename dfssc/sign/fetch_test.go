@@ -4,8 +4,10 @@ import (
 	"fmt"
 	"io/ioutil"
 	"os"
+	"path/filepath"
 	"testing"
 
+	"dfss/dfssp/contract"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -20,7 +22,7 @@ func TestFetchContractWrongUUID(t *testing.T) {
 func checkFetchResult(t *testing.T, uuid string, errExpected bool, content string) {
 	file, _ := ioutil.TempFile("", "")
 	defer func() { _ = os.Remove(file.Name()) }()
-	err := FetchContract("password", uuid, file.Name())
+	err := FetchContract(ConfigFromViper(), "password", uuid, file.Name())
 	if errExpected {
 		assert.NotEqual(t, nil, err)
 	} else {
@@ -30,3 +32,13 @@ func checkFetchResult(t *testing.T, uuid string, errExpected bool, content strin
 	data, _ := ioutil.ReadFile(file.Name())
 	assert.Equal(t, content, fmt.Sprintf("%s", data))
 }
+
+func TestFetchDocumentNotHosted(t *testing.T) {
+	c := &contract.JSON{UUID: "01", File: &contract.FileJSON{Name: "contract.txt", Hosted: false}}
+	path := filepath.Join(os.TempDir(), "dfss_fetch_document")
+	err := FetchDocument(ConfigFromViper(), "password", c, path)
+	assert.NotNil(t, err)
+
+	_, err = os.Stat(path)
+	assert.True(t, os.IsNotExist(err))
+}
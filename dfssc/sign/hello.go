@@ -0,0 +1,93 @@
+package sign
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"dfss"
+	cAPI "dfss/dfssc/api"
+	pAPI "dfss/dfssp/api"
+)
+
+// ProtocolVersion is the version of the signature protocol implemented by this client.
+// Peers use the highest version they both support, negotiated during the Discover handshake.
+// Version 2 changed the format of promises, that now contain a verifiable escrow.
+const ProtocolVersion uint32 = 2
+
+// MinProtocolVersion is the oldest version of the signature protocol this client can sign with.
+// Clients without a protocol range in their Discover handshake are too old to sign with.
+const MinProtocolVersion uint32 = 2
+
+// newHello returns the Discover handshake of a signer for a contract
+func newHello(contractUUID string, keyHash []byte) *cAPI.Hello {
+	return &cAPI.Hello{
+		Version:      dfss.Version,
+		ContractUuid: contractUUID,
+		KeyHash:      keyHash,
+		MinProtocol:  MinProtocolVersion,
+		MaxProtocol:  ProtocolVersion,
+	}
+}
+
+// negotiateProtocol returns the highest protocol version supported by both this client and the peer
+func negotiateProtocol(hello *cAPI.Hello) (uint32, error) {
+	min, max := MinProtocolVersion, ProtocolVersion
+	if hello.MinProtocol > min {
+		min = hello.MinProtocol
+	}
+	if hello.MaxProtocol < max {
+		max = hello.MaxProtocol
+	}
+	if min > max {
+		return 0, fmt.Errorf("Incompatible peer version %s: supports protocols %d to %d, expected %d to %d", hello.Version, hello.MinProtocol, hello.MaxProtocol, MinProtocolVersion, ProtocolVersion)
+	}
+	return max, nil
+}
+
+// checkHello checks the Discover handshake of a peer against the expected contract and signer,
+// and returns the negotiated protocol version.
+func checkHello(hello *cAPI.Hello, contractUUID string, keyHash []byte) (uint32, error) {
+	protocol, err := negotiateProtocol(hello)
+	if err != nil {
+		return 0, err
+	}
+	if hello.ContractUuid != contractUUID {
+		return 0, errors.New("Peer is not signing this contract")
+	}
+	if !bytes.Equal(hello.KeyHash, keyHash) {
+		return 0, errors.New("Peer key hash does not match its certificate")
+	}
+	return protocol, nil
+}
+
+// checkDiscover checks the answer of a peer to our Discover handshake.
+// The peer must accept the handshake, and agree on the contract, its identity and the protocol version.
+func checkDiscover(hello *cAPI.Hello, contractUUID string, keyHash []byte) error {
+	if hello.ErrorCode == nil {
+		return fmt.Errorf("Incompatible peer version %s: no protocol negotiation", hello.Version)
+	}
+	if hello.ErrorCode.Code != pAPI.ErrorCode_SUCCESS {
+		return errors.New("Peer rejected the handshake: " + hello.ErrorCode.Message)
+	}
+
+	protocol, err := checkHello(hello, contractUUID, keyHash)
+	if err != nil {
+		return err
+	}
+	if hello.Protocol != protocol {
+		return fmt.Errorf("Peer negotiated protocol %d instead of %d", hello.Protocol, protocol)
+	}
+	return nil
+}
+
+// isSigner returns true if the specified certificate hash is the one of a signer of the contract
+func (m *SignatureManager) isSigner(keyHash []byte) bool {
+	hash := fmt.Sprintf("%x", keyHash)
+	for _, s := range m.contract.Signers {
+		if s.Hash == hash {
+			return true
+		}
+	}
+	return false
+}
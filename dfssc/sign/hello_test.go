@@ -0,0 +1,48 @@
+package sign
+
+import (
+	"testing"
+
+	cAPI "dfss/dfssc/api"
+	pAPI "dfss/dfssp/api"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNegotiateProtocol(t *testing.T) {
+	protocol, err := negotiateProtocol(newHello("contract", nil))
+	assert.Nil(t, err)
+	assert.Equal(t, ProtocolVersion, protocol)
+
+	// A newer peer still supporting our protocol
+	protocol, err = negotiateProtocol(&cAPI.Hello{MinProtocol: MinProtocolVersion, MaxProtocol: ProtocolVersion + 1})
+	assert.Nil(t, err)
+	assert.Equal(t, ProtocolVersion, protocol)
+
+	_, err = negotiateProtocol(&cAPI.Hello{MinProtocol: ProtocolVersion + 1, MaxProtocol: ProtocolVersion + 2})
+	assert.NotNil(t, err)
+	_, err = negotiateProtocol(&cAPI.Hello{Version: "0.3.0"})
+	assert.NotNil(t, err)
+
+	// Promises of the first version cannot be verified
+	_, err = negotiateProtocol(&cAPI.Hello{MinProtocol: 1, MaxProtocol: 1})
+	assert.NotNil(t, err)
+}
+
+func TestCheckDiscover(t *testing.T) {
+	hello := newHello("contract", []byte{0x01})
+	hello.Protocol = ProtocolVersion
+	hello.ErrorCode = &pAPI.ErrorCode{Code: pAPI.ErrorCode_SUCCESS}
+	assert.Nil(t, checkDiscover(hello, "contract", []byte{0x01}))
+
+	assert.NotNil(t, checkDiscover(hello, "other", []byte{0x01}))
+	assert.NotNil(t, checkDiscover(hello, "contract", []byte{0x02}))
+
+	hello.Protocol = 0
+	assert.NotNil(t, checkDiscover(hello, "contract", []byte{0x01}))
+
+	hello.ErrorCode.Code = pAPI.ErrorCode_BADAUTH
+	assert.NotNil(t, checkDiscover(hello, "contract", []byte{0x01}))
+
+	// Older clients do not negotiate
+	assert.NotNil(t, checkDiscover(&cAPI.Hello{Version: "0.3.0"}, "contract", []byte{0x01}))
+}
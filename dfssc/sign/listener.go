@@ -0,0 +1,191 @@
+package sign
+
+import (
+	"errors"
+	gonet "net"
+	"strconv"
+	"strings"
+	"sync"
+
+	cAPI "dfss/dfssc/api"
+	"dfss/dfssc/security"
+	pAPI "dfss/dfssp/api"
+	"dfss/net"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+)
+
+// Listener is the local server of a client, that can be shared by several signatures of the same user.
+// Incoming promises and signatures are routed to the SignatureManager in charge of their signature UUID,
+// so that a long-running client can sign several contracts at once.
+type Listener struct {
+	auth      *security.AuthContainer
+	server    *grpc.Server
+	addr      *gonet.TCPAddr
+	managers  map[string]*SignatureManager // by signature UUID
+	contracts map[string]*SignatureManager // by contract UUID, for the Discover handshake
+	mutex     sync.Mutex
+	stopWatch context.CancelFunc // stops the refresh of the revocation list, nil if it is not refreshed
+}
+
+// NewListener starts a local server on the specified address (host:port), with the identity of the user.
+// An empty host listens on every interface, IPv4 and IPv6, and a zero port lets the system pick one.
+// The auth container must be loaded. The listener must be stopped with Stop.
+//
+// The revocation list of the platform of the configuration is refreshed first, and then as long as the listener runs,
+// so that peers revoked in the meantime are rejected.
+func NewListener(config *Config, auth *security.AuthContainer, addrPort string) (*Listener, error) {
+	conn, err := config.connectPlatform(context.Background(), auth)
+	if err != nil {
+		return nil, err
+	}
+	platform := pAPI.NewPlatformClient(conn)
+
+	ctx, cancel := context.WithTimeout(context.Background(), config.timeout())
+	_, err = security.UpdateRevocationList(ctx, platform, auth.CA, config.CRLFile)
+	cancel()
+	if err != nil {
+		_ = conn.Close()
+		return nil, err
+	}
+
+	l, err := newListener(auth, addrPort)
+	if err != nil {
+		_ = conn.Close()
+		return nil, err
+	}
+
+	ctx, l.stopWatch = context.WithCancel(context.Background())
+	go func() {
+		security.WatchRevocationList(ctx, platform, auth.CA, config.CRLFile, config.timeout(), func(err error) {
+			if config.Log != nil {
+				config.Log(auth.Cert.Subject.CommonName, "unable to refresh the revocation list: "+err.Error())
+			}
+		})
+		_ = conn.Close()
+	}()
+	return l, nil
+}
+
+// newListener starts a local server, without refreshing the revocation list
+func newListener(auth *security.AuthContainer, addrPort string) (*Listener, error) {
+	lis, err := gonet.Listen("tcp", addrPort)
+	if err != nil {
+		return nil, err
+	}
+
+	l := &Listener{
+		auth:      auth,
+		server:    net.NewServer(auth.Cert, auth.Key, auth.CA),
+		addr:      lis.Addr().(*gonet.TCPAddr),
+		managers:  make(map[string]*SignatureManager),
+		contracts: make(map[string]*SignatureManager),
+	}
+	cAPI.RegisterClientServer(l.server, &clientServer{listener: l})
+	go func() { _ = l.server.Serve(lis) }()
+	return l, nil
+}
+
+// Port returns the port of the local server
+func (l *Listener) Port() int {
+	return l.addr.Port
+}
+
+// Endpoints returns the endpoints (host:port) announced to the peers to reach the local server.
+// Advertised endpoints are used if provided, with the port of the local server when they have none.
+// Otherwise, the bound address or the addresses of every network interface are announced.
+func (l *Listener) Endpoints(advertised []string) ([]string, error) {
+	if len(advertised) == 0 {
+		if !l.addr.IP.IsUnspecified() {
+			advertised = []string{l.addr.IP.String()}
+		} else {
+			ips, err := net.ExternalInterfaceAddr()
+			if err != nil {
+				return nil, err
+			}
+			advertised = ips
+		}
+	}
+
+	port := strconv.Itoa(l.Port())
+	endpoints := make([]string, len(advertised))
+	for i, a := range advertised {
+		host, p, err := gonet.SplitHostPort(a)
+		if err != nil { // No port
+			host, p = strings.Trim(a, "[]"), port
+		}
+		if _, err = strconv.ParseUint(p, 10, 16); host == "" || err != nil {
+			return nil, errors.New("Invalid advertised endpoint: " + a)
+		}
+		endpoints[i] = gonet.JoinHostPort(host, p)
+	}
+	return endpoints, nil
+}
+
+// Stop closes the local server, and every connection to it, and stops refreshing the revocation list
+func (l *Listener) Stop() {
+	if l.stopWatch != nil {
+		l.stopWatch()
+	}
+	l.server.Stop()
+}
+
+// register routes the evidence of the signature of the manager to it.
+// The state of the manager written before registration is visible to the handlers, as route locks the same mutex.
+func (l *Listener) register(m *SignatureManager) error {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if _, ok := l.managers[m.uuid]; ok {
+		return errors.New("Signature " + m.uuid + " is already running")
+	}
+	l.managers[m.uuid] = m
+	return nil
+}
+
+// unregister stops routing the evidence of the signature of the manager, if it was registered
+func (l *Listener) unregister(m *SignatureManager) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if l.managers[m.uuid] == m {
+		delete(l.managers, m.uuid)
+	}
+}
+
+// join accepts the Discover handshakes of the peers of the contract of the manager
+func (l *Listener) join(m *SignatureManager) error {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if other, ok := l.contracts[m.contract.UUID]; ok && other != m {
+		return errors.New("Contract " + m.contract.UUID + " is already being signed")
+	}
+	l.contracts[m.contract.UUID] = m
+	return nil
+}
+
+// leave stops accepting the Discover handshakes for the contract of the manager, if it joined it
+func (l *Listener) leave(m *SignatureManager) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if m.contract != nil && l.contracts[m.contract.UUID] == m {
+		delete(l.contracts, m.contract.UUID)
+	}
+}
+
+// routeContract returns the manager connecting to the peers of the specified contract, if any
+func (l *Listener) routeContract(contractUUID string) *SignatureManager {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	return l.contracts[contractUUID]
+}
+
+// route returns the manager in charge of the specified signature, if any
+func (l *Listener) route(signatureUUID string) *SignatureManager {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	return l.managers[signatureUUID]
+}
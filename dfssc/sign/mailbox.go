@@ -0,0 +1,148 @@
+package sign
+
+import (
+	"crypto/x509"
+	"errors"
+	"time"
+
+	"dfss/auth"
+	cAPI "dfss/dfssc/api"
+	pAPI "dfss/dfssp/api"
+	"dfss/net"
+	"golang.org/x/net/context"
+)
+
+// mailboxPollDelay is the delay between two fetches of the mailbox of an asynchronous signature
+var mailboxPollDelay = 2 * time.Second
+
+// isAsync returns true if the evidence of the signature is exchanged through the mailboxes of the platform.
+// Before the launch of the signature, the contract tells whether the signature will be asynchronous.
+func (m *SignatureManager) isAsync() bool {
+	return m.deadline != 0 || m.contract.Deadline != nil
+}
+
+// evidenceTimeout returns the delay after which the missing evidence of a round is considered lost.
+// Asynchronous signers can send their evidence until the deadline, the ttp takes over after it.
+func (m *SignatureManager) evidenceTimeout() time.Duration {
+	if m.deadline == 0 {
+		return m.config.timeout()
+	}
+
+	timeout := time.Unix(m.deadline, 0).Sub(time.Now())
+	if timeout < 0 {
+		return 0
+	}
+	return timeout
+}
+
+// checkDeadline checks that the deadline sealed by the platform is the one of the contract
+func (m *SignatureManager) checkDeadline(deadline int64) error {
+	expected := int64(0)
+	if m.contract.Deadline != nil {
+		expected = m.contract.Deadline.Unix()
+	}
+	if deadline != expected {
+		return errors.New("Corrupted DFSS file: the deadline does not match the one of the platform, unable to sign safely")
+	}
+	return nil
+}
+
+// loadCertificates checks the certificates of the signers of an asynchronous signature, as sent by the platform.
+// They are needed to encrypt the evidence stored in the mailboxes, and must match the sealed hashes.
+func (m *SignatureManager) loadCertificates(certificates [][]byte) error {
+	if len(certificates) != len(m.keyHash) {
+		return errors.New("Missing certificates of the signers, unable to sign asynchronously")
+	}
+
+	for i, raw := range certificates {
+		mail := m.contract.Signers[i].Email
+		cert, err := x509.ParseCertificate(raw)
+		if err != nil {
+			return errors.New("Invalid certificate for signer " + mail + ": " + err.Error())
+		}
+		if err = auth.CheckCertificate(cert, m.auth.CA, m.keyHash[i]); err != nil {
+			return errors.New("Signer " + mail + " has an invalid certificate, unable to sign safely: " + err.Error())
+		}
+		if net.IsRevoked(cert) {
+			return errors.New("Signer " + mail + " has a revoked certificate, unable to sign safely")
+		}
+		m.peersCert[mail] = cert
+	}
+	return nil
+}
+
+// certificatesToJSON returns the DER certificates of the signers, in order, to be journaled
+func (m *SignatureManager) certificatesToJSON() [][]byte {
+	if m.deadline == 0 {
+		return nil
+	}
+
+	certificates := make([][]byte, len(m.contract.Signers))
+	for i, s := range m.contract.Signers {
+		if cert := m.peersCert[s.Email]; cert != nil {
+			certificates[i] = cert.Raw
+		}
+	}
+	return certificates
+}
+
+// postEvidence encrypts a promise or a signature for its recipient, and stores it in its mailbox on the platform
+func (m *SignatureManager) postEvidence(ctx context.Context, promise *cAPI.Promise, signature *cAPI.Signature, to uint32) (*pAPI.ErrorCode, error) {
+	cert := m.getCertificate(to)
+	if cert == nil {
+		return nil, errors.New("No certificate for " + m.contract.Signers[to].Email)
+	}
+
+	payload, err := m.sealEnvelope(cert, &cAPI.Envelope{Promise: promise, Signature: signature})
+	if err != nil {
+		return nil, err
+	}
+
+	return m.platform.PostEvidence(ctx, &pAPI.Evidence{
+		SignatureUuid:    m.uuid,
+		RecipientKeyHash: m.keyHash[to],
+		Payload:          payload,
+	})
+}
+
+// pollMailbox fetches the mailbox of the signature until stop is closed
+func (m *SignatureManager) pollMailbox(stop chan struct{}) {
+	for {
+		if err := m.fetchMailbox(); err != nil {
+			m.dlog("unable to fetch the mailbox: " + err.Error())
+		}
+
+		select {
+		case <-stop:
+			return
+		case <-time.After(mailboxPollDelay):
+		}
+	}
+}
+
+// fetchMailbox fetches the evidence waiting in our mailbox, and treats it as if it was sent directly by its sender.
+func (m *SignatureManager) fetchMailbox() error {
+	ctx, cancel := m.requestContext()
+	defer cancel()
+	list, err := m.platform.FetchEvidence(ctx, &pAPI.FetchEvidenceRequest{SignatureUuid: m.uuid})
+	if err != nil {
+		return err
+	}
+	if list.ErrorCode.Code != pAPI.ErrorCode_SUCCESS {
+		return errors.New(list.ErrorCode.Code.String() + " " + list.ErrorCode.Message)
+	}
+
+	for _, evidence := range list.Evidence {
+		m.treatEnvelope(evidence.SenderKeyHash, evidence.Payload)
+	}
+	return nil
+}
+
+// startMailbox starts fetching the mailbox of an asynchronous signature, until the connections are closed
+func (m *SignatureManager) startMailbox() {
+	if m.deadline == 0 || m.stopMailbox != nil {
+		return
+	}
+	m.stopMailbox = make(chan struct{})
+	go m.pollMailbox(m.stopMailbox)
+}
@@ -0,0 +1,126 @@
+package sign
+
+import (
+	"crypto/x509"
+	"testing"
+	"time"
+
+	cAPI "dfss/dfssc/api"
+	pAPI "dfss/dfssp/api"
+	"dfss/net"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+)
+
+// fakePlatform keeps the posted evidence in a single mailbox, and relays evidence to a single listener, as if it was sent by sender
+type fakePlatform struct {
+	pAPI.PlatformClient
+	sender  []byte
+	mailbox []*pAPI.Evidence
+	relay   chan *pAPI.Evidence
+}
+
+func (f *fakePlatform) PostEvidence(ctx context.Context, in *pAPI.Evidence, opts ...grpc.CallOption) (*pAPI.ErrorCode, error) {
+	in.SenderKeyHash = f.sender
+	f.mailbox = append(f.mailbox, in)
+	return &pAPI.ErrorCode{Code: pAPI.ErrorCode_SUCCESS}, nil
+}
+
+func (f *fakePlatform) FetchEvidence(ctx context.Context, in *pAPI.FetchEvidenceRequest, opts ...grpc.CallOption) (*pAPI.EvidenceList, error) {
+	list := &pAPI.EvidenceList{ErrorCode: &pAPI.ErrorCode{Code: pAPI.ErrorCode_SUCCESS}, Evidence: f.mailbox}
+	f.mailbox = nil
+	return list, nil
+}
+
+func newTestAsyncSignatureManager(t *testing.T) (*SignatureManager, *fakePlatform) {
+	m := newTestSignatureManager(t)
+	m.deadline = time.Now().Add(time.Hour).Unix()
+	sealTestSignatureManager(t, m)
+
+	platform := &fakePlatform{sender: m.keyHash[0]}
+	m.platform = platform
+	m.archives = &Archives{}
+	m.peersCert = make(map[string]*x509.Certificate)
+	m.incomingPromises = make(chan interface{}, chanBufferSize)
+	m.incomingSignatures = make(chan interface{}, chanBufferSize)
+	return m, platform
+}
+
+func TestMailbox(t *testing.T) {
+	m, platform := newTestAsyncSignatureManager(t)
+
+	// We are both sender and recipient here
+	promise, err := m.CreatePromise(0, 0, 0)
+	assert.Nil(t, err)
+	signature, err := m.CreateSignature(0, 0)
+	assert.Nil(t, err)
+
+	assert.Nil(t, m.SendEvidence(promise, nil, 0))
+	assert.Nil(t, m.SendEvidence(nil, signature, 0))
+	assert.Equal(t, 2, len(platform.mailbox))
+	assert.Equal(t, m.keyHash[0], platform.mailbox[0].RecipientKeyHash)
+	assert.Equal(t, 1, len(m.archives.sentSignatures))
+
+	// The platform cannot read the evidence
+	assert.NotContains(t, string(platform.mailbox[0].Payload), string(promise.Payload))
+
+	assert.Nil(t, m.fetchMailbox())
+	assert.Equal(t, 0, len(platform.mailbox))
+	assert.Equal(t, 1, len(m.incomingPromises))
+	assert.Equal(t, 1, len(m.incomingSignatures))
+	received := (<-m.incomingPromises).(*cAPI.Promise)
+	assert.Equal(t, promise.Payload, received.Payload)
+
+	// Unreadable evidence is dropped
+	platform.mailbox = []*pAPI.Evidence{{SenderKeyHash: m.keyHash[0], Payload: []byte{0x01}}}
+	assert.Nil(t, m.fetchMailbox())
+	assert.Equal(t, 0, len(m.incomingPromises))
+	assert.Equal(t, 0, len(m.archives.rejectedEvidence))
+
+	// Evidence from another sender than the authenticated one is rejected
+	assert.Nil(t, m.SendEvidence(promise, nil, 0))
+	platform.mailbox[0].SenderKeyHash = m.keyHash[1]
+	assert.Nil(t, m.fetchMailbox())
+	assert.Equal(t, 0, len(m.incomingPromises))
+	assert.Equal(t, 1, len(m.archives.rejectedEvidence))
+	assert.Equal(t, pAPI.ErrorCode_BADAUTH, m.archives.rejectedEvidence[0].Code)
+}
+
+func TestEvidenceTimeout(t *testing.T) {
+	m, _ := newTestAsyncSignatureManager(t)
+	assert.True(t, m.evidenceTimeout() > 59*time.Minute)
+
+	m.deadline = time.Now().Add(-time.Minute).Unix()
+	assert.Equal(t, time.Duration(0), m.evidenceTimeout())
+
+	m.deadline = 0
+	assert.Equal(t, net.DefaultTimeout, m.evidenceTimeout())
+}
+
+func TestCheckDeadline(t *testing.T) {
+	m := newTestSignatureManager(t)
+	assert.Nil(t, m.checkDeadline(0))
+	assert.NotNil(t, m.checkDeadline(42))
+
+	deadline := time.Unix(42, 0)
+	m.contract.Deadline = &deadline
+	assert.Nil(t, m.checkDeadline(42))
+	assert.NotNil(t, m.checkDeadline(0))
+	assert.True(t, m.isAsync())
+}
+
+func TestLoadCertificates(t *testing.T) {
+	m, _ := newTestAsyncSignatureManager(t)
+	raw := m.auth.Cert.Raw
+
+	assert.NotNil(t, m.loadCertificates([][]byte{raw}))
+	assert.NotNil(t, m.loadCertificates([][]byte{raw, nil}))
+	assert.NotNil(t, m.loadCertificates([][]byte{raw, raw}))
+
+	// The other signer has no valid certificate in the test manager
+	m.keyHash[1] = m.keyHash[0]
+	assert.Nil(t, m.loadCertificates([][]byte{raw, raw}))
+	assert.Equal(t, m.auth.Cert, m.peersCert["other@example.com"])
+	assert.Equal(t, 2, len(m.certificatesToJSON()))
+}
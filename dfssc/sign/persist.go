@@ -7,15 +7,8 @@ import (
 
 	cAPI "dfss/dfssc/api"
 	"dfss/dfssc/common"
-	"dfss/dfssp/contract"
 )
 
-// SignedContractJSON is an union of contract and related signatures
-type SignedContractJSON struct {
-	Contract   contract.JSON
-	Signatures []cAPI.Signature
-}
-
 // PersistSignaturesToFile save contract informations and signatures to disk
 func (m *SignatureManager) PersistSignaturesToFile() error {
 
@@ -25,7 +18,7 @@ func (m *SignatureManager) PersistSignaturesToFile() error {
 	}
 
 	// Fill JSON struct
-	signedContract := SignedContractJSON{
+	signedContract := common.SignedContractJSON{
 		Contract: *m.contract,
 		Signatures: make(
 			[]cAPI.Signature,
@@ -41,6 +34,37 @@ func (m *SignatureManager) PersistSignaturesToFile() error {
 		signedContract.Signatures[len(m.archives.sentSignatures)+i] = *s
 	}
 
+	return m.writeProof(&signedContract)
+}
+
+// PersistTTPContractToFile checks the signed contract generated by the ttp, and saves it to disk
+// with the information of our contract.
+func (m *SignatureManager) PersistTTPContractToFile(data []byte) error {
+	signedContract := &common.SignedContractJSON{}
+	err := json.Unmarshal(data, signedContract)
+	if err != nil {
+		return err
+	}
+
+	pendingSet := common.GetAllButOne(m.sequence, m.myID)
+	for i := range signedContract.Signatures {
+		senderID, err := m.checkSignature(&signedContract.Signatures[i])
+		if err != nil {
+			return err
+		}
+		pendingSet, _ = common.Remove(pendingSet, senderID)
+	}
+
+	if len(pendingSet) > 0 {
+		return fmt.Errorf("Incomplete contract received from the ttp")
+	}
+
+	signedContract.Contract = *m.contract
+	return m.writeProof(signedContract)
+}
+
+// writeProof saves the signed contract to disk, as a proof file
+func (m *SignatureManager) writeProof(signedContract *common.SignedContractJSON) error {
 	proof, err := json.MarshalIndent(signedContract, "", "  ")
 	if err != nil {
 		return err
@@ -50,6 +74,7 @@ func (m *SignatureManager) PersistSignaturesToFile() error {
 }
 
 // PersistRecoverDataToFile : save recover informations to disk.
+// The file is then used as the journal of the signature, see PersistJournal.
 // returns the file name and an error if any occured
 func (m *SignatureManager) PersistRecoverDataToFile() (string, error) {
 	// Check content, don't write an empty file
@@ -64,16 +89,62 @@ func (m *SignatureManager) PersistRecoverDataToFile() (string, error) {
 		TTPHash:       m.ttpData.Hash,
 	}
 
-	file, err := json.MarshalIndent(recData, "", "  ")
+	filename := m.mail + "-" + m.uuid + ".run"
+	err := writeJSONFile(filename, &recData)
 	if err != nil {
 		return "", err
 	}
 
-	filename := m.mail + "-" + m.uuid + ".run"
-	err = ioutil.WriteFile(filename, file, 0600)
+	m.journalFile = filename
+	return filename, nil
+}
+
+// PersistJournal saves the current state of the signature in the recover data file, if any:
+// sequence context, current index, received promises, sent signatures and rejected evidence.
+// It is called after every round, so that the signature can be resumed after a crash (see Resume).
+func (m *SignatureManager) PersistJournal() error {
+	if m.journalFile == "" {
+		return nil
+	}
+
+	m.archives.mutex.Lock()
+	recData := common.RecoverDataJSON{
+		SignatureUUID: m.uuid,
+		TTPAddrport:   m.ttpData.Addrport,
+		TTPHash:       m.ttpData.Hash,
+
+		ContractUUID:      m.contract.UUID,
+		Sequence:          m.sequence,
+		SequenceGenerator: m.generator,
+		KeyHash:           m.keyHash,
+		Seal:              m.seal,
+		CurrentIndex:      m.currentIndex,
+		LastValidIndex:    m.lastValidIndex,
+		ReceivedPromises:  m.archives.receivedPromises,
+		SentSignatures:    m.archives.sentSignatures,
+		RejectedEvidence:  m.archives.rejectedEvidence,
+		Deadline:          m.deadline,
+		Certificates:      m.certificatesToJSON(),
+	}
+	err := writeJSONFile(m.journalFile, &recData)
+	m.archives.mutex.Unlock()
+	return err
+}
+
+// updateJournal persists the journal during the protocol.
+// The protocol must go on even if the journal cannot be written, so the error is only logged.
+func (m *SignatureManager) updateJournal() {
+	err := m.PersistJournal()
 	if err != nil {
-		return "", err
+		m.dlog("unable to update the signature journal: " + err.Error())
 	}
+}
 
-	return filename, nil
+// writeJSONFile encodes the data and stores it at the specified file, see common.ReplaceFile.
+func writeJSONFile(filename string, data interface{}) error {
+	file, err := json.MarshalIndent(data, "", "  ")
+	if err != nil {
+		return err
+	}
+	return common.ReplaceFile(file, filename)
 }
@@ -1,14 +1,14 @@
 package sign
 
 import (
+	"crypto/x509"
 	"encoding/hex"
 	"errors"
 
+	"dfss/auth"
 	cAPI "dfss/dfssc/api"
-	dAPI "dfss/dfssd/api"
+	"dfss/dfssc/common"
 	pAPI "dfss/dfssp/api"
-	"dfss/net"
-	"golang.org/x/net/context"
 )
 
 func (m *SignatureManager) createContext(from, to uint32) (*cAPI.Context, error) {
@@ -28,26 +28,54 @@ func (m *SignatureManager) createContext(from, to uint32) (*cAPI.Context, error)
 		TtpAddrPort:          m.ttpData.Addrport,
 		TtpHash:              m.ttpData.Hash,
 		Seal:                 m.seal,
+		SequenceGenerator:    m.generator,
+		Deadline:             m.deadline,
 	}, nil
 }
 
 // CreatePromise creates a promise from 'from' to 'to', in the context of the SignatureManager
 // provided that the specified sequence indexes are valid
+//
+// The payload of the promise is a private contract signature of the contract (see auth.CreatePCS),
+// that only the recipient can check, and that only the TTP can convert into our real signature.
 func (m *SignatureManager) CreatePromise(from, to, at uint32) (*cAPI.Promise, error) {
 	context, err := m.createContext(from, to)
 	if err != nil {
 		return nil, err
 	}
 
-	if m.currentIndex < 0 {
-		return nil, errors.New("Invalid currentIndex for promise creation")
+	if int(at) >= len(m.sequence) {
+		return nil, errors.New("Invalid index for promise creation")
 	}
 
-	return &cAPI.Promise{
+	recipient := m.getCertificate(to)
+	if recipient == nil {
+		return nil, errors.New("Unknown certificate for promise recipient")
+	}
+
+	promise := &cAPI.Promise{
 		Index:   at,
 		Context: context,
-		Payload: []byte{0x41},
-	}, nil
+	}
+
+	promise.Payload, err = auth.CreatePCS(m.auth.Key, m.auth.Cert, common.SignatureStatement(context), common.PromiseLabel(promise), recipient, m.ttpCert)
+	if err != nil {
+		return nil, err
+	}
+
+	return promise, nil
+}
+
+// getCertificate returns the certificate of the specified sequence id, as authenticated during the connection
+// or sent by the platform for an asynchronous signature, if any
+func (m *SignatureManager) getCertificate(id uint32) *x509.Certificate {
+	if id == m.myID {
+		return m.auth.Cert
+	}
+	if int(id) >= len(m.contract.Signers) {
+		return nil
+	}
+	return m.peersCert[m.contract.Signers[id].Email]
 }
 
 // SendEvidence factorizes the send code between promises and signatures.
@@ -55,37 +83,42 @@ func (m *SignatureManager) CreatePromise(from, to, at uint32) (*cAPI.Promise, er
 // The successfully sent evidence is then added to the archives.
 func (m *SignatureManager) SendEvidence(promise *cAPI.Promise, signature *cAPI.Signature, to uint32) (err error) {
 	connection, mail := m.GetClient(to)
-	if connection == nil {
+	if connection == nil && m.deadline == 0 {
 		return
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), net.DefaultTimeout)
+	ctx, cancel := m.requestContext()
 	defer cancel()
 
 	var result *pAPI.ErrorCode
-	if promise != nil {
+	if promise == nil && signature == nil {
+		err = errors.New("both promise and signature are nil, cannot send anything")
+	} else if m.deadline != 0 {
+		result, err = m.postEvidence(ctx, promise, signature, to)
+	} else if promise != nil {
 		result, err = (*connection).TreatPromise(ctx, promise)
-	} else if signature != nil {
-		result, err = (*connection).TreatSignature(ctx, signature)
 	} else {
-		err = errors.New("both promise and signature are nil, cannot send anything")
+		result, err = (*connection).TreatSignature(ctx, signature)
 	}
 
 	if err == nil && result != nil && result.Code == pAPI.ErrorCode_SUCCESS {
 		m.archives.mutex.Lock()
 		if promise != nil {
-			dAPI.DLog("successfully sent promise to " + mail)
+			m.dlog("successfully sent promise to " + mail)
 		} else {
-			dAPI.DLog("successfully sent signature to " + mail)
+			m.dlog("successfully sent signature to " + mail)
 			m.archives.sentSignatures = append(m.archives.sentSignatures, signature)
 		}
 		m.archives.mutex.Unlock()
 	} else {
-		dAPI.DLog("was unable to send evidence to " + mail)
+		m.dlog("was unable to send evidence to " + mail)
 		if err != nil {
 			return
 		}
 		err = errors.New("received wrong error code")
+		if result != nil {
+			err = errors.New("received wrong error code: " + result.Code.String() + " " + result.Message)
+		}
 	}
 
 	return
@@ -5,16 +5,13 @@ import (
 	"bytes"
 	"errors"
 	"fmt"
-	"io/ioutil"
 	"os"
 	"time"
 
+	"dfss/auth"
 	cAPI "dfss/dfssc/api"
 	"dfss/dfssc/common"
-	dAPI "dfss/dfssd/api"
 	tAPI "dfss/dfsst/api"
-	"dfss/net"
-	"github.com/spf13/viper"
 	"golang.org/x/net/context"
 	"google.golang.org/grpc"
 )
@@ -22,10 +19,12 @@ import (
 // Sign performs all the message exchanges for the contract to be signed
 //
 // * Initialize the SignatureManager from starter.go
-// * Compute the reversed map [mail -> ID] of signers
-// * Make channels for handlers
+// * Wait for every peer to be ready
 // * Promises rounds
 // * Signature round
+//
+// Once started, the signature can be cancelled through the Cancel channel: the ttp is then contacted
+// with the current evidence, and Outcome tells whether the contract was signed or aborted.
 func (m *SignatureManager) Sign() error {
 
 	defer func() {
@@ -38,12 +37,18 @@ func (m *SignatureManager) Sign() error {
 		return err
 	}
 
-	m.makeSignersHashToIDMap()
-	m.cServerIface.incomingPromises = make(chan interface{}, chanBufferSize)
-	m.cServerIface.incomingSignatures = make(chan interface{}, chanBufferSize)
+	err = m.waitPeersReady()
+	if err != nil {
+		return err
+	}
+
+	return m.signFrom(nextIndex)
+}
 
-	// Cooldown delay, let other clients wake-up their channels
-	time.Sleep(time.Second)
+// signFrom performs the promises rounds from the current index, and then the signature round.
+// The journal of the signature is updated after every round.
+func (m *SignatureManager) signFrom(nextIndex int) (err error) {
+	m.updateJournal()
 
 	seqLen := len(m.sequence)
 
@@ -52,10 +57,11 @@ func (m *SignatureManager) Sign() error {
 	round := 0
 	for m.currentIndex >= 0 {
 		round = round + 1
-		stopIfNeeded(round)
-		m.OnProgressUpdate(m.currentIndex, seqLen+1)
-		time.Sleep(viper.GetDuration("slowdown"))
-		dAPI.DLog("starting round at index [" + fmt.Sprintf("%d", m.currentIndex) + "] with nextIndex=" + fmt.Sprintf("%d", nextIndex))
+		m.stopIfNeeded(round)
+		m.updateProgress(m.currentIndex, seqLen+1)
+		time.Sleep(m.config.Slowdown)
+		m.dlog("starting round at index [" + fmt.Sprintf("%d", m.currentIndex) + "] with nextIndex=" + fmt.Sprintf("%d", nextIndex))
+		m.emit(Event{Type: EventRoundStarted, Index: m.currentIndex})
 
 		// Set of promises we are waiting for
 		var pendingSet []common.SequenceCoordinate
@@ -75,29 +81,38 @@ func (m *SignatureManager) Sign() error {
 		var stop bool
 		stop, err = m.promiseRound(pendingSet, sendSet)
 		if err != nil || stop {
-			dAPI.DLog("stopping protocol execution")
+			m.dlog("stopping protocol execution")
 			return err
 		}
+		m.emit(Event{Type: EventRoundEnded, Index: m.currentIndex})
 
 		m.currentIndex = nextIndex
 		nextIndex, err = common.FindNextIndex(m.sequence, m.myID, m.currentIndex)
 		if err != nil {
 			return err
 		}
+
+		m.updateJournal()
 	}
 
 	// Signature round
-	stopIfNeeded(-1)
-	m.OnProgressUpdate(seqLen, seqLen+1)
-	dAPI.DLog("entering signature round")
+	m.stopIfNeeded(-1)
+	m.updateProgress(seqLen, seqLen+1)
+	m.dlog("entering signature round")
 	err = m.ExchangeAllSignatures()
 	if err != nil {
-		return err
+		// Every promise was exchanged, so the ttp is able to generate the signed contract
+		m.dlog("signature round failed: " + err.Error())
+		return m.resolve()
 	}
 
-	dAPI.DLog("exiting signature round")
-	m.OnProgressUpdate(seqLen+1, seqLen+1)
-	return m.PersistSignaturesToFile()
+	m.dlog("exiting signature round")
+	m.updateProgress(seqLen+1, seqLen+1)
+	err = m.PersistSignaturesToFile()
+	if err == nil {
+		m.outcome = OutcomeSigned
+	}
+	return err
 }
 
 // GetClient retrieves the Client to the specified sequence id provided it exists
@@ -124,7 +139,7 @@ func (m *SignatureManager) promiseRound(pendingSet, sendSet []common.SequenceCoo
 	var promises []*cAPI.Promise
 	for len(pendingSet) > 0 {
 		select {
-		case promiseIface := <-m.cServerIface.incomingPromises:
+		case promiseIface := <-m.incomingPromises:
 			promise := (promiseIface).(*cAPI.Promise)
 			valid, senderID := m.checkPromise(pendingSet, promise)
 			if valid {
@@ -134,12 +149,16 @@ func (m *SignatureManager) promiseRound(pendingSet, sendSet []common.SequenceCoo
 					continue
 				}
 				promises = append(promises, promise)
+				m.emit(Event{Type: EventPromiseReceived, Mail: m.contract.Signers[senderID].Email, Index: int(promise.Index)})
 			} else {
 				return true, m.resolve()
 			}
 
-		case <-time.After(net.DefaultTimeout):
+		case <-time.After(m.evidenceTimeout()):
 			return true, m.resolve()
+
+		case <-m.Cancel:
+			return true, m.cancelSignature()
 		}
 	}
 
@@ -147,6 +166,13 @@ func (m *SignatureManager) promiseRound(pendingSet, sendSet []common.SequenceCoo
 	m.updateReceivedPromises(promises)
 	m.lastValidIndex = m.currentIndex
 
+	// Stop before sending our promises if the signature was cancelled in the meantime
+	select {
+	case <-m.Cancel:
+		return true, m.cancelSignature()
+	default:
+	}
+
 	c := make(chan error, chanBufferSize)
 	// Sending of due promises
 	for _, coord := range sendSet {
@@ -155,6 +181,9 @@ func (m *SignatureManager) promiseRound(pendingSet, sendSet []common.SequenceCoo
 			if err == nil {
 				err = m.SendEvidence(promise, nil, coord.Signer)
 			}
+			if err == nil {
+				m.emit(Event{Type: EventPromiseSent, Mail: m.contract.Signers[coord.Signer].Email, Index: m.currentIndex})
+			}
 			c <- err
 		}(coord, m)
 	}
@@ -164,7 +193,7 @@ func (m *SignatureManager) promiseRound(pendingSet, sendSet []common.SequenceCoo
 		v := <-c
 		if v != nil {
 			// We couldn't send a due promise
-			dAPI.DLog("Couldn't send promise: " + v.Error())
+			m.dlog("Couldn't send promise: " + v.Error())
 			return true, m.resolve()
 		}
 	}
@@ -173,14 +202,26 @@ func (m *SignatureManager) promiseRound(pendingSet, sendSet []common.SequenceCoo
 }
 
 // closeConnections tries to close all established connection with other peers and platform.
-// It also stops the local server.
+// It also stops the local server, unless it is shared with other signatures.
 func (m *SignatureManager) closeConnections() {
 	_ = m.platformConn.Close()
 	for k, peer := range m.peersConn {
 		_ = peer.Close()
 		delete(m.peers, k)
 	}
-	m.cServer.Stop()
+	if m.stopMailbox != nil {
+		close(m.stopMailbox)
+		m.stopMailbox = nil
+	}
+	if m.stopRelay != nil {
+		m.stopRelay()
+		m.stopRelay = nil
+	}
+	m.listener.unregister(m)
+	m.listener.leave(m)
+	if m.ownListener {
+		m.listener.Stop()
+	}
 }
 
 // updateReceivedPromises : updates the RecievedPromises field of the SignatureManager with the provided promises:
@@ -233,7 +274,8 @@ func (m *SignatureManager) callForResolve() (*tAPI.TTPResponse, error) {
 
 	request := &tAPI.AlertRequest{Promises: toSend, Index: uint32(m.lastValidIndex)}
 
-	ctx, cancel := context.WithTimeout(context.Background(), net.DefaultTimeout)
+	// The ttp is contacted even once the signature is cancelled, so the request does not derive from its context
+	ctx, cancel := context.WithTimeout(context.Background(), m.config.timeout())
 	defer cancel()
 	response, err := m.ttp.Alert(ctx, request)
 	if err != nil {
@@ -246,26 +288,41 @@ func (m *SignatureManager) callForResolve() (*tAPI.TTPResponse, error) {
 // resolve : calls for the resolution, and persists the contract if obtained.
 func (m *SignatureManager) resolve() error {
 	if m.ttp == nil {
-		dAPI.DLog("unable to contact TTP")
+		m.dlog("unable to contact TTP")
 		return errors.New("No connection to TTP, aborting!")
 	}
 
-	dAPI.DLog("contacting TTP with resolve index " + fmt.Sprint(m.lastValidIndex))
+	m.dlog("contacting TTP with resolve index " + fmt.Sprint(m.lastValidIndex))
+	m.emit(Event{Type: EventTTPCall, Index: m.lastValidIndex})
 	response, err := m.callForResolve()
 	if err != nil {
-		dAPI.DLog("Resolve call generated an error: " + err.Error())
+		m.dlog("Resolve call generated an error: " + err.Error())
 		return err
 	}
 	if response.Abort {
-		dAPI.DLog("contacted TTP, received abort token")
+		m.dlog("contacted TTP, received abort token")
+		m.outcome = OutcomeAborted
 		return nil
 	}
-	dAPI.DLog("contacted TTP, received signed contract")
-	return ioutil.WriteFile(m.mail+"-"+m.uuid+".proof", response.Contract, 0600)
+	m.dlog("contacted TTP, received signed contract")
+	err = m.PersistTTPContractToFile(response.Contract)
+	if err == nil {
+		m.outcome = OutcomeResolved
+	}
+	return err
+}
+
+// cancelSignature stops the protocol on user request, and contacts the ttp with the current evidence.
+// The outcome of the signature tells whether the ttp sent an abort token or the signed contract.
+func (m *SignatureManager) cancelSignature() error {
+	m.dlog("signature cancelled")
+	m.cancelled = true
+	return m.resolve()
 }
 
 // checkPromise : verifies that the promise is valid wrt the expected promises.
 // We assume that the promise data is consistent wrt the platform seal.
+// The private contract signature of the promise is checked as its designated verifier.
 func (m *SignatureManager) checkPromise(expected []common.SequenceCoordinate, promise *cAPI.Promise) (bool, uint32) {
 	// the promise is consistent, but not for the expected signature
 	// this should not happen
@@ -284,6 +341,13 @@ func (m *SignatureManager) checkPromise(expected []common.SequenceCoordinate, pr
 	if !exist {
 		return false, 0
 	}
+
+	// the promise was not created by its sender, not for this context, or could not be converted by the ttp
+	sender, err := auth.VerifyPCS(m.auth.Key, promise.Payload, common.SignatureStatement(promise.Context), common.PromiseLabel(promise), m.ttpCert)
+	if err != nil || auth.CheckCertificate(sender, m.auth.CA, promise.Context.SenderKeyHash) != nil {
+		return false, 0
+	}
+
 	for _, c := range expected {
 		if c.Signer == senderID && c.Index == promise.Index {
 			return true, senderID
@@ -293,8 +357,8 @@ func (m *SignatureManager) checkPromise(expected []common.SequenceCoordinate, pr
 	return false, 0
 }
 
-func stopIfNeeded(index int) {
-	s := viper.GetInt("stopbefore")
+func (m *SignatureManager) stopIfNeeded(index int) {
+	s := m.config.StopBefore
 	if s == 0 {
 		return
 	}
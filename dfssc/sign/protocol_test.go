@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	cAPI "dfss/dfssc/api"
+	"dfss/dfssc/common"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -139,3 +140,35 @@ func TestUpdateRecievedPromises(t *testing.T) {
 	assert.Equal(t, present, true)
 	assert.Equal(t, index, 2)
 }
+
+func TestCancelPromiseRound(t *testing.T) {
+	m := newTestSignatureManager(t)
+	m.archives = &Archives{}
+	m.incomingPromises = make(chan interface{}, chanBufferSize)
+	m.Cancel = make(chan interface{})
+	m.currentIndex = 2
+	m.lastValidIndex = 0
+	ttp := &fakeTTP{}
+	m.ttp = ttp
+
+	// Cancelled while waiting for a promise
+	go func() { m.Cancel <- true }()
+	pendingSet := []common.SequenceCoordinate{{Signer: 1, Index: 1}}
+	stop, err := m.promiseRound(pendingSet, nil)
+	assert.True(t, stop)
+	assert.Nil(t, err)
+	assert.True(t, m.IsTerminated())
+	assert.Equal(t, OutcomeAborted, m.Outcome())
+	assert.Equal(t, 1, len(ttp.alerts))
+	assert.Equal(t, uint32(0), ttp.alerts[0].Index)
+
+	// Cancelled before sending our promises: the current round is the last valid one
+	m.Cancel = make(chan interface{}, 1)
+	m.Cancel <- true
+	sendSet := []common.SequenceCoordinate{{Signer: 1, Index: 2}}
+	stop, err = m.promiseRound(nil, sendSet)
+	assert.True(t, stop)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(ttp.alerts))
+	assert.Equal(t, uint32(2), ttp.alerts[1].Index)
+}
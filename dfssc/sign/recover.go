@@ -14,19 +14,18 @@ import (
 )
 
 // Recover : performs a recover attempt using the provided recover file, and the user's passphrase to use secured connection
-func Recover(filename, passphrase string) error {
+func Recover(config *Config, filename, passphrase string) error {
 	json, err := readRecoveryFile(filename)
 	if err != nil {
 		return err
 	}
 
-	auth := security.NewAuthContainer(passphrase)
-	_, _, _, err = auth.LoadFiles()
+	auth, err := config.loadAuth(passphrase)
 	if err != nil {
 		return err
 	}
 
-	ttp, err := connectToTTP(json, auth)
+	ttp, err := connectToTTP(config, json, auth)
 	if err != nil {
 		return err
 	}
@@ -58,9 +57,10 @@ func readRecoveryFile(filename string) (*common.RecoverDataJSON, error) {
 }
 
 // connectToTTP : connects to the ttp, using the provided recover data and authentication information
-func connectToTTP(json *common.RecoverDataJSON, auth *security.AuthContainer) (tAPI.TTPClient, error) {
-	// TODO check that the connection spots missing TTP and returns an error quickly enough
-	conn, err := net.Connect(json.TTPAddrport, auth.Cert, auth.Key, auth.CA, json.TTPHash)
+func connectToTTP(config *Config, json *common.RecoverDataJSON, auth *security.AuthContainer) (tAPI.TTPClient, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), config.timeout())
+	defer cancel()
+	conn, _, err := net.ConnectContext(ctx, json.TTPAddrport, auth.Cert, auth.Key, auth.CA, json.TTPHash)
 	if err != nil {
 		return nil, err
 	}
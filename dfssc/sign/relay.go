@@ -0,0 +1,82 @@
+package sign
+
+import (
+	"crypto/x509"
+	"errors"
+
+	cAPI "dfss/dfssc/api"
+	pAPI "dfss/dfssp/api"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+)
+
+// relayClient reaches a peer through the relay of the platform, when no direct connection is possible.
+// It implements the client API of the peer, so that the protocol does not depend on the transport.
+// The evidence is signed with our certificate and encrypted for the peer: the platform only carries it.
+type relayClient struct {
+	m    *SignatureManager
+	hash []byte
+	cert *x509.Certificate
+}
+
+func (r *relayClient) TreatPromise(ctx context.Context, in *cAPI.Promise, opts ...grpc.CallOption) (*pAPI.ErrorCode, error) {
+	return r.send(ctx, &cAPI.Envelope{Promise: in})
+}
+
+func (r *relayClient) TreatSignature(ctx context.Context, in *cAPI.Signature, opts ...grpc.CallOption) (*pAPI.ErrorCode, error) {
+	return r.send(ctx, &cAPI.Envelope{Signature: in})
+}
+
+// Ready succeeds once the peer listens to the relay, which it does as soon as it accepts the evidence of the signature.
+func (r *relayClient) Ready(ctx context.Context, in *cAPI.ReadySignal, opts ...grpc.CallOption) (*pAPI.ErrorCode, error) {
+	return r.send(ctx, &cAPI.Envelope{Ready: in})
+}
+
+func (r *relayClient) Discover(ctx context.Context, in *cAPI.Hello, opts ...grpc.CallOption) (*cAPI.Hello, error) {
+	return nil, errors.New("relayed peers cannot be discovered")
+}
+
+func (r *relayClient) send(ctx context.Context, envelope *cAPI.Envelope) (*pAPI.ErrorCode, error) {
+	payload, err := r.m.sealEnvelope(r.cert, envelope)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.m.platform.RelayEvidence(ctx, &pAPI.Evidence{
+		ContractUuid:     r.m.contract.UUID,
+		SignatureUuid:    r.m.uuid,
+		RecipientKeyHash: r.hash,
+		Payload:          payload,
+	})
+}
+
+// listenRelay treats the evidence relayed by the platform until ctx is cancelled
+func (m *SignatureManager) listenRelay(ctx context.Context) {
+	stream, err := m.platform.Relay(ctx, &pAPI.RelayRequest{ContractUuid: m.contract.UUID, SignatureUuid: m.uuid})
+	if err != nil {
+		m.dlog("unable to listen to the relay: " + err.Error())
+		return
+	}
+
+	for {
+		evidence, err := stream.Recv()
+		if err != nil {
+			if ctx.Err() == nil {
+				m.dlog("relay closed: " + err.Error())
+			}
+			return
+		}
+		m.treatEnvelope(evidence.SenderKeyHash, evidence.Payload)
+	}
+}
+
+// startRelay starts listening to the relay of a synchronous signature, until the connections are closed.
+// Peers unable to reach us directly send their evidence through it.
+func (m *SignatureManager) startRelay() {
+	if m.deadline != 0 || m.stopRelay != nil {
+		return
+	}
+	ctx, cancel := context.WithCancel(m.ctx)
+	m.stopRelay = cancel
+	go m.listenRelay(ctx)
+}
@@ -0,0 +1,112 @@
+package sign
+
+import (
+	"crypto/x509"
+	"testing"
+
+	cAPI "dfss/dfssc/api"
+	pAPI "dfss/dfssp/api"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+)
+
+func (f *fakePlatform) RelayEvidence(ctx context.Context, in *pAPI.Evidence, opts ...grpc.CallOption) (*pAPI.ErrorCode, error) {
+	if f.relay == nil {
+		return &pAPI.ErrorCode{Code: pAPI.ErrorCode_TIMEOUT}, nil
+	}
+	in.SenderKeyHash = f.sender
+	f.relay <- in
+	return &pAPI.ErrorCode{Code: pAPI.ErrorCode_SUCCESS}, nil
+}
+
+func (f *fakePlatform) Relay(ctx context.Context, in *pAPI.RelayRequest, opts ...grpc.CallOption) (pAPI.Platform_RelayClient, error) {
+	return &fakeRelayStream{ctx: ctx, relay: f.relay}, nil
+}
+
+type fakeRelayStream struct {
+	grpc.ClientStream
+	ctx   context.Context
+	relay chan *pAPI.Evidence
+}
+
+func (s *fakeRelayStream) Recv() (*pAPI.Evidence, error) {
+	select {
+	case evidence := <-s.relay:
+		return evidence, nil
+	case <-s.ctx.Done():
+		return nil, s.ctx.Err()
+	}
+}
+
+func TestRelay(t *testing.T) {
+	m, platform := newTestAsyncSignatureManager(t)
+	m.deadline = 0
+	sealTestSignatureManager(t, m)
+
+	// We are our own peer here
+	var client cAPI.ClientClient = &relayClient{m: m, hash: m.keyHash[0], cert: m.auth.Cert}
+	result, err := client.Ready(context.Background(), &cAPI.ReadySignal{SignatureUUID: m.uuid})
+	assert.Nil(t, err)
+	assert.Equal(t, pAPI.ErrorCode_TIMEOUT, result.Code)
+
+	platform.relay = make(chan *pAPI.Evidence, chanBufferSize)
+	m.startRelay()
+	defer m.stopRelay()
+
+	result, err = client.Ready(context.Background(), &cAPI.ReadySignal{SignatureUUID: m.uuid})
+	assert.Nil(t, err)
+	assert.Equal(t, pAPI.ErrorCode_SUCCESS, result.Code)
+
+	promise, err := m.CreatePromise(0, 0, 0)
+	assert.Nil(t, err)
+	result, err = client.TreatPromise(context.Background(), promise)
+	assert.Nil(t, err)
+	assert.Equal(t, pAPI.ErrorCode_SUCCESS, result.Code)
+
+	signature, err := m.CreateSignature(0, 0)
+	assert.Nil(t, err)
+	result, err = client.TreatSignature(context.Background(), signature)
+	assert.Nil(t, err)
+	assert.Equal(t, pAPI.ErrorCode_SUCCESS, result.Code)
+
+	receivedPromise := (<-m.incomingPromises).(*cAPI.Promise)
+	assert.Equal(t, promise.Payload, receivedPromise.Payload)
+	receivedSignature := (<-m.incomingSignatures).(*cAPI.Signature)
+	assert.Equal(t, signature.Payload, receivedSignature.Payload)
+
+	_, err = client.Discover(context.Background(), &cAPI.Hello{})
+	assert.NotNil(t, err)
+}
+
+func TestAddRelayedPeer(t *testing.T) {
+	m, _ := newTestAsyncSignatureManager(t)
+	m.deadline = 0
+	m.peers = map[string]*cAPI.ClientClient{"other@example.com": nil}
+	m.peersCert = make(map[string]*x509.Certificate)
+	cause := context.DeadlineExceeded
+
+	// No certificate from the platform
+	user := &pAPI.User{Email: "other@example.com", KeyHash: m.keyHash[1]}
+	_, err := m.addRelayedPeer(user, cause)
+	assert.Equal(t, cause, err)
+
+	// The certificate does not match the sealed hash
+	user.Certificate = m.auth.Cert.Raw
+	_, err = m.addRelayedPeer(user, cause)
+	assert.Equal(t, cause, err)
+	assert.Nil(t, m.peers["other@example.com"])
+
+	user.KeyHash = m.keyHash[0]
+	ready, err := m.addRelayedPeer(user, cause)
+	assert.Nil(t, err)
+	assert.True(t, ready)
+	assert.Equal(t, m.auth.Cert, m.peersCert["other@example.com"])
+	_, ok := (*m.peers["other@example.com"]).(*relayClient)
+	assert.True(t, ok)
+
+	// Already counted
+	ready, err = m.addRelayedPeer(user, cause)
+	assert.Nil(t, err)
+	assert.False(t, ready)
+}
@@ -0,0 +1,124 @@
+package sign
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+
+	"dfss/dfssc/common"
+	pAPI "dfss/dfssp/api"
+)
+
+// FindJournal looks for the journal of an interrupted signature of the contract in the current directory.
+// It returns the name of the recover data file, or an empty string if there is none.
+func (m *SignatureManager) FindJournal() string {
+	files, _ := filepath.Glob(m.mail + "-*.run")
+	for _, f := range files {
+		data, err := readRecoveryFile(f)
+		if err == nil && data.IsJournal() && data.ContractUUID == m.contract.UUID {
+			return f
+		}
+	}
+	return ""
+}
+
+// Resume restarts a signature interrupted by a crash, from the journal saved in the specified recover data file.
+//
+// If the promises rounds were not over, the client joins the signature room of the platform again to reach the peers,
+// and goes on with the protocol from the journaled index. The local port must be the same as before the crash.
+// Otherwise, or if the peers cannot be reached in time, the ttp is contacted with every promise of the journal.
+// Asynchronous signatures go on through the mailboxes of the platform, until the deadline.
+func (m *SignatureManager) Resume(filename string) error {
+	defer func() {
+		m.finished = true
+		m.closeConnections()
+	}()
+
+	data, err := readRecoveryFile(filename)
+	if err != nil {
+		return err
+	}
+
+	err = m.restoreJournal(data)
+	if err != nil {
+		return err
+	}
+	m.journalFile = filename
+
+	if m.currentIndex < 0 {
+		m.dlog("resuming after the promises rounds")
+		return m.resolve()
+	}
+
+	// The platform stream is only needed to reach the peers again
+	ctx, cancel := m.requestContext()
+	defer cancel()
+	err = m.connectToPeers(ctx)
+	if err != nil {
+		m.dlog("unable to reach the peers again: " + err.Error())
+		return m.resolve()
+	}
+
+	nextIndex, err := common.FindNextIndex(m.sequence, m.myID, m.currentIndex)
+	if err != nil {
+		return err
+	}
+
+	m.dlog("resuming signature at index " + fmt.Sprint(m.currentIndex))
+	return m.signFrom(nextIndex)
+}
+
+// restoreJournal checks the journal against the contract, restores the state of the signature and connects to the ttp
+func (m *SignatureManager) restoreJournal(data *common.RecoverDataJSON) (err error) {
+	if !data.IsJournal() || data.ContractUUID != m.contract.UUID {
+		return errors.New("No signature journal for this contract")
+	}
+
+	err = m.checkSigners(data.KeyHash)
+	if err != nil {
+		return
+	}
+
+	err = checkSequence(m.contract.SequenceGenerator, data.SequenceGenerator, data.Sequence, len(m.contract.Signers))
+	if err != nil {
+		return
+	}
+
+	if data.CurrentIndex >= len(data.Sequence) || data.LastValidIndex < 0 || data.LastValidIndex >= len(data.Sequence) {
+		return errors.New("Corrupted signature journal: invalid index")
+	}
+
+	err = m.checkDeadline(data.Deadline)
+	if err != nil {
+		return
+	}
+
+	m.uuid = data.SignatureUUID
+	m.sequence = data.Sequence
+	m.generator = data.SequenceGenerator
+	m.keyHash = data.KeyHash
+	m.seal = data.Seal
+	m.deadline = data.Deadline
+	m.currentIndex = data.CurrentIndex
+	m.lastValidIndex = data.LastValidIndex
+	m.archives.receivedPromises = append(m.archives.receivedPromises, data.ReceivedPromises...)
+	m.archives.sentSignatures = append(m.archives.sentSignatures, data.SentSignatures...)
+	m.archives.rejectedEvidence = append(m.archives.rejectedEvidence, data.RejectedEvidence...)
+
+	err = m.connectToTTP(&pAPI.LaunchSignature_TTP{
+		Addrport: data.TTPAddrport,
+		Hash:     data.TTPHash,
+	})
+	if err != nil {
+		return
+	}
+
+	if m.deadline != 0 {
+		err = m.loadCertificates(data.Certificates)
+		if err != nil {
+			return
+		}
+	}
+
+	return m.acceptEvidence()
+}
@@ -0,0 +1,109 @@
+package sign
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	cAPI "dfss/dfssc/api"
+	"dfss/dfssc/common"
+	pAPI "dfss/dfssp/api"
+	"dfss/dfssp/contract"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/net/context"
+)
+
+func newJournalManager(dir string) *SignatureManager {
+	c := &contract.JSON{
+		UUID: "contract",
+		Signers: []contract.SignerJSON{
+			{Email: "signer1@foo.foo", Hash: "0a"},
+			{Email: "signer2@foo.foo", Hash: "0b"},
+		},
+	}
+
+	return &SignatureManager{
+		ctx:            context.Background(),
+		config:         &Config{},
+		contract:       c,
+		ttpData:        &pAPI.LaunchSignature_TTP{Addrport: "127.0.0.1:9020", Hash: []byte{7}},
+		sequence:       []uint32{0, 1, 0, 1},
+		keyHash:        [][]byte{{0x0a}, {0x0b}},
+		seal:           []byte{1, 2, 3},
+		uuid:           "signature",
+		mail:           "signer2@foo.foo",
+		currentIndex:   3,
+		lastValidIndex: 1,
+		journalFile:    filepath.Join(dir, "journal.run"),
+		archives: &Archives{
+			receivedPromises: []*cAPI.Promise{{Index: 2, Context: &cAPI.Context{SenderKeyHash: []byte{0x0a}}}},
+		},
+	}
+}
+
+func TestPersistJournal(t *testing.T) {
+	dir, _ := ioutil.TempDir("", "journal")
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	m := newJournalManager(dir)
+	assert.Nil(t, m.PersistJournal())
+
+	_, err := os.Stat(m.journalFile + ".tmp")
+	assert.True(t, os.IsNotExist(err))
+
+	data, err := readRecoveryFile(m.journalFile)
+	assert.Nil(t, err)
+	assert.True(t, data.IsJournal())
+	assert.Equal(t, "signature", data.SignatureUUID)
+	assert.Equal(t, "contract", data.ContractUUID)
+	assert.Equal(t, m.sequence, data.Sequence)
+	assert.Equal(t, m.keyHash, data.KeyHash)
+	assert.Equal(t, 3, data.CurrentIndex)
+	assert.Equal(t, 1, data.LastValidIndex)
+	assert.Equal(t, 1, len(data.ReceivedPromises))
+	assert.Equal(t, uint32(2), data.ReceivedPromises[0].Index)
+	assert.Equal(t, 0, len(data.SentSignatures))
+
+	// Nothing is written without a recover data file
+	m.journalFile = ""
+	assert.Nil(t, m.PersistJournal())
+}
+
+func TestRestoreJournal(t *testing.T) {
+	journal := func() *common.RecoverDataJSON {
+		return &common.RecoverDataJSON{
+			SignatureUUID:  "signature",
+			TTPAddrport:    "127.0.0.1:9020",
+			TTPHash:        []byte{7},
+			ContractUUID:   "contract",
+			Sequence:       []uint32{0, 1, 0, 1},
+			KeyHash:        [][]byte{{0x0a}, {0x0b}},
+			CurrentIndex:   3,
+			LastValidIndex: 1,
+		}
+	}
+
+	m := newJournalManager("")
+	m.archives = &Archives{}
+
+	data := journal()
+	data.ContractUUID = "other"
+	assert.Equal(t, "No signature journal for this contract", m.restoreJournal(data).Error())
+
+	data = journal()
+	data.Sequence = nil
+	assert.Equal(t, "No signature journal for this contract", m.restoreJournal(data).Error())
+
+	data = journal()
+	data.KeyHash[1] = []byte{0x0c}
+	assert.NotNil(t, m.restoreJournal(data))
+
+	data = journal()
+	data.Sequence = []uint32{0, 1, 0, 1, 0, 1}
+	assert.NotNil(t, m.restoreJournal(data))
+
+	data = journal()
+	data.CurrentIndex = 4
+	assert.Equal(t, "Corrupted signature journal: invalid index", m.restoreJournal(data).Error())
+}
@@ -1,18 +1,20 @@
 package sign
 
 import (
-	"dfss"
+	"bytes"
+	"fmt"
+
+	"dfss/auth"
 	cAPI "dfss/dfssc/api"
+	"dfss/dfssc/common"
 	pAPI "dfss/dfssp/api"
-	"dfss/dfsst/entities"
 	"dfss/net"
 	"golang.org/x/net/context"
-	"google.golang.org/grpc"
 )
 
+// clientServer handles the incoming messages of a Listener
 type clientServer struct {
-	incomingPromises   chan interface{}
-	incomingSignatures chan interface{}
+	listener *Listener
 }
 
 func getServerErrorCode(c chan interface{}, in interface{}) *pAPI.ErrorCode {
@@ -23,37 +25,143 @@ func getServerErrorCode(c chan interface{}, in interface{}) *pAPI.ErrorCode {
 	return &pAPI.ErrorCode{Code: pAPI.ErrorCode_INTERR} // server not ready
 }
 
+// route returns the manager of the signature of the incoming evidence, or nil if it is unknown
+func (s *clientServer) route(c *cAPI.Context) *SignatureManager {
+	if c == nil {
+		return nil
+	}
+	return s.listener.route(c.SignatureUUID)
+}
+
 // TreatPromise handler
 //
 // Handle incoming TreatPromise messages
 func (s *clientServer) TreatPromise(ctx context.Context, in *cAPI.Promise) (*pAPI.ErrorCode, error) {
-	// we check that the incoming promise is valid (ie. no data inconsistency)
-	// we do not check that we expected that promise
-	valid, _, _, _ := entities.IsRequestValid(ctx, []*cAPI.Promise{in})
-	if !valid {
-		return &pAPI.ErrorCode{Code: pAPI.ErrorCode_SUCCESS}, nil
+	m := s.route(in.Context)
+	if m == nil {
+		return &pAPI.ErrorCode{Code: pAPI.ErrorCode_INTERR, Message: "unknown signature"}, nil // server not ready
 	}
-	return getServerErrorCode(s.incomingPromises, in), nil
+
+	return m.treatPromise(net.GetClientHash(&ctx), in), nil
 }
 
 // TreatSignature handler
 //
 // Handle incoming TreatSignature messages
 func (s *clientServer) TreatSignature(ctx context.Context, in *cAPI.Signature) (*pAPI.ErrorCode, error) {
-	return getServerErrorCode(s.incomingSignatures, in), nil
+	m := s.route(in.Context)
+	if m == nil {
+		return &pAPI.ErrorCode{Code: pAPI.ErrorCode_INTERR, Message: "unknown signature"}, nil // server not ready
+	}
+
+	return m.treatSignature(net.GetClientHash(&ctx), in), nil
+}
+
+// Ready handler
+//
+// Handle incoming Ready messages, accepted once the evidence of the signature is routed to us
+func (s *clientServer) Ready(ctx context.Context, in *cAPI.ReadySignal) (*pAPI.ErrorCode, error) {
+	m := s.listener.route(in.SignatureUUID)
+	if m == nil {
+		return &pAPI.ErrorCode{Code: pAPI.ErrorCode_INTERR, Message: "unknown signature"}, nil // not ready yet
+	}
+
+	if _, ok := m.hashToID[fmt.Sprintf("%x", net.GetClientHash(&ctx))]; !ok {
+		return &pAPI.ErrorCode{Code: pAPI.ErrorCode_BADAUTH, Message: "not a signer of this signature"}, nil
+	}
+	return &pAPI.ErrorCode{Code: pAPI.ErrorCode_SUCCESS}, nil
 }
 
 // Discover handler
 //
-// Handle incoming Discover messages
+// Handle incoming Discover messages, checking that the peer is a signer of a contract we are signing
 func (s *clientServer) Discover(ctx context.Context, in *cAPI.Hello) (*cAPI.Hello, error) {
-	return &cAPI.Hello{Version: dfss.Version}, nil
+	hello := newHello("", nil)
+	m := s.listener.routeContract(in.ContractUuid)
+	if m != nil {
+		hello.ContractUuid = m.contract.UUID
+		hello.KeyHash = auth.GetCertificateHash(m.auth.Cert)
+	}
+
+	sender := net.GetClientHash(&ctx)
+	protocol, err := negotiateProtocol(in)
+	switch {
+	case err != nil:
+		hello.ErrorCode = &pAPI.ErrorCode{Code: pAPI.ErrorCode_INVARG, Message: err.Error()}
+	case m == nil:
+		hello.ErrorCode = &pAPI.ErrorCode{Code: pAPI.ErrorCode_INVARG, Message: "not signing this contract"}
+	case !bytes.Equal(in.KeyHash, sender) || !m.isSigner(sender):
+		hello.ErrorCode = &pAPI.ErrorCode{Code: pAPI.ErrorCode_BADAUTH, Message: "not a signer of this contract"}
+	default:
+		hello.Protocol = protocol
+		hello.ErrorCode = &pAPI.ErrorCode{Code: pAPI.ErrorCode_SUCCESS}
+	}
+	return hello, nil
 }
 
-// GetServer create and registers a ClientServer, returning the associated GRPC server
-func (m *SignatureManager) GetServer() *grpc.Server {
-	server := net.NewServer(m.auth.Cert, m.auth.Key, m.auth.CA)
-	m.cServerIface = clientServer{}
-	cAPI.RegisterClientServer(server, &m.cServerIface)
-	return server
+// treatPromise checks an incoming promise sent by an authenticated signer, and keeps it for the promise rounds
+func (m *SignatureManager) treatPromise(sender []byte, in *cAPI.Promise) *pAPI.ErrorCode {
+	// we check that the incoming promise is consistent with the current signature
+	// we do not check that we expected that promise
+	if m.incomingPromises != nil {
+		errorCode := m.checkContext(sender, in.Context)
+		if errorCode.Code != pAPI.ErrorCode_SUCCESS {
+			m.rejectEvidence(sender, &common.RejectedEvidenceJSON{Promise: in}, errorCode)
+			return errorCode
+		}
+	}
+	return getServerErrorCode(m.incomingPromises, in)
+}
+
+// treatSignature checks an incoming signature sent by an authenticated signer, and keeps it for the signature round
+func (m *SignatureManager) treatSignature(sender []byte, in *cAPI.Signature) *pAPI.ErrorCode {
+	// the payload of the signature is checked by the signature round
+	if m.incomingSignatures != nil {
+		errorCode := m.checkContext(sender, in.Context)
+		if errorCode.Code != pAPI.ErrorCode_SUCCESS {
+			m.rejectEvidence(sender, &common.RejectedEvidenceJSON{Signature: in}, errorCode)
+			return errorCode
+		}
+	}
+	return getServerErrorCode(m.incomingSignatures, in)
+}
+
+// checkContext verifies the context of an incoming promise or signature:
+// it must be sealed by the platform for the current signature, sent by the authenticated peer, and addressed to us.
+// The sender is authenticated by its TLS certificate, or by the platform for evidence fetched from a mailbox.
+func (m *SignatureManager) checkContext(sender []byte, c *cAPI.Context) *pAPI.ErrorCode {
+	if c == nil {
+		return &pAPI.ErrorCode{Code: pAPI.ErrorCode_INVARG, Message: "missing context"}
+	}
+
+	if c.SignatureUUID != m.uuid {
+		return &pAPI.ErrorCode{Code: pAPI.ErrorCode_INVARG, Message: "unknown signature " + c.SignatureUUID}
+	}
+
+	if !common.IsSealValid(m.auth.CA, c) {
+		return &pAPI.ErrorCode{Code: pAPI.ErrorCode_INVARG, Message: "invalid platform seal"}
+	}
+
+	if sender == nil || !bytes.Equal(sender, c.SenderKeyHash) {
+		return &pAPI.ErrorCode{Code: pAPI.ErrorCode_BADAUTH, Message: "sender does not match the authenticated peer"}
+	}
+
+	if int(m.myID) >= len(m.keyHash) || !bytes.Equal(c.RecipientKeyHash, m.keyHash[m.myID]) {
+		return &pAPI.ErrorCode{Code: pAPI.ErrorCode_INVARG, Message: "bad recipient"}
+	}
+
+	return &pAPI.ErrorCode{Code: pAPI.ErrorCode_SUCCESS}
+}
+
+// rejectEvidence archives an invalid promise or signature, with the authenticated sender and the reason of the rejection
+func (m *SignatureManager) rejectEvidence(sender []byte, rejected *common.RejectedEvidenceJSON, errorCode *pAPI.ErrorCode) {
+	rejected.Sender = sender
+	rejected.Code = errorCode.Code
+	rejected.Reason = errorCode.Message
+	m.dlog("rejected incoming evidence: " + errorCode.Message)
+
+	m.archives.mutex.Lock()
+	m.archives.rejectedEvidence = append(m.archives.rejectedEvidence, rejected)
+	m.archives.mutex.Unlock()
+	m.updateJournal()
 }
@@ -0,0 +1,229 @@
+package sign
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"strconv"
+	"testing"
+	"time"
+
+	cAPI "dfss/dfssc/api"
+	pAPI "dfss/dfssp/api"
+	"dfss/net"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+)
+
+func peerContext(cert *x509.Certificate) context.Context {
+	return peer.NewContext(context.Background(), &peer.Peer{
+		AuthInfo: credentials.TLSInfo{State: tls.ConnectionState{
+			VerifiedChains: [][]*x509.Certificate{{cert}},
+		}},
+	})
+}
+
+func TestTreatEvidence(t *testing.T) {
+	m := newTestSignatureManager(t)
+	m.archives = &Archives{}
+	l := &Listener{managers: make(map[string]*SignatureManager)}
+	s := &clientServer{listener: l}
+	ctx := peerContext(m.auth.Cert)
+	c, _ := m.createContext(0, 0)
+
+	// Unknown signature
+	result, _ := s.TreatPromise(ctx, &cAPI.Promise{Context: c})
+	assert.Equal(t, pAPI.ErrorCode_INTERR, result.Code)
+
+	// Server not ready
+	m.listener = l
+	assert.Nil(t, l.register(m))
+	result, _ = s.TreatPromise(ctx, &cAPI.Promise{Context: c})
+	assert.Equal(t, pAPI.ErrorCode_INTERR, result.Code)
+	assert.Equal(t, 0, len(m.archives.rejectedEvidence))
+
+	m.incomingPromises = make(chan interface{}, chanBufferSize)
+	m.incomingSignatures = make(chan interface{}, chanBufferSize)
+
+	check := func(c *cAPI.Context, ctx context.Context, code pAPI.ErrorCode_Code) {
+		result, _ := s.TreatPromise(ctx, &cAPI.Promise{Context: c})
+		assert.Equal(t, code, result.Code, result.Message)
+		result, _ = s.TreatSignature(ctx, &cAPI.Signature{Context: c})
+		assert.Equal(t, code, result.Code, result.Message)
+	}
+
+	// Valid context: we are both sender and recipient here
+	check(c, ctx, pAPI.ErrorCode_SUCCESS)
+	assert.Equal(t, 1, len(m.incomingPromises))
+	assert.Equal(t, 1, len(m.incomingSignatures))
+
+	// Bad seal
+	c, _ = m.createContext(0, 0)
+	c.Sequence = []uint32{1, 0, 1, 0}
+	check(c, ctx, pAPI.ErrorCode_INVARG)
+
+	// Sender is not the authenticated peer
+	c, _ = m.createContext(1, 0)
+	check(c, ctx, pAPI.ErrorCode_BADAUTH)
+	c, _ = m.createContext(0, 0)
+	check(c, context.Background(), pAPI.ErrorCode_BADAUTH)
+
+	// We are not the recipient
+	c, _ = m.createContext(0, 1)
+	check(c, ctx, pAPI.ErrorCode_INVARG)
+
+	assert.Equal(t, 1, len(m.incomingPromises))
+	assert.Equal(t, 1, len(m.incomingSignatures))
+
+	// Rejected evidence is archived
+	rejected := m.archives.rejectedEvidence
+	assert.Equal(t, 8, len(rejected))
+	assert.NotNil(t, rejected[0].Promise)
+	assert.NotNil(t, rejected[1].Signature)
+	assert.Equal(t, m.keyHash[0], rejected[0].Sender)
+	assert.Equal(t, pAPI.ErrorCode_INVARG, rejected[0].Code)
+	assert.Equal(t, "invalid platform seal", rejected[0].Reason)
+	assert.Nil(t, rejected[5].Sender)
+	assert.Equal(t, pAPI.ErrorCode_BADAUTH, rejected[5].Code)
+}
+
+func TestCheckContext(t *testing.T) {
+	m := newTestSignatureManager(t)
+	sender := m.keyHash[0]
+
+	assert.Equal(t, "missing context", m.checkContext(sender, nil).Message)
+
+	c, _ := m.createContext(0, 0)
+	c.SignatureUUID = "other"
+	assert.Equal(t, pAPI.ErrorCode_INVARG, m.checkContext(sender, c).Code)
+}
+
+func TestListenerRouting(t *testing.T) {
+	m := newTestSignatureManager(t)
+	other := &SignatureManager{uuid: "other"}
+
+	l, err := newListener(m.auth, ":0")
+	assert.Nil(t, err)
+	defer l.Stop()
+	assert.NotEqual(t, 0, l.Port())
+
+	assert.Nil(t, l.register(m))
+	assert.Nil(t, l.register(other))
+	assert.NotNil(t, l.register(&SignatureManager{uuid: "other"}))
+
+	assert.Equal(t, m, l.route("signature"))
+	assert.Equal(t, other, l.route("other"))
+	assert.Nil(t, l.route("unknown"))
+
+	// Only the registered manager can unregister its signature
+	l.unregister(&SignatureManager{uuid: "other"})
+	assert.Equal(t, other, l.route("other"))
+	l.unregister(other)
+	assert.Nil(t, l.route("other"))
+	assert.Equal(t, m, l.route("signature"))
+}
+
+func TestListenerEndpoints(t *testing.T) {
+	m := newTestSignatureManager(t)
+	l, err := newListener(m.auth, "127.0.0.1:0")
+	assert.Nil(t, err)
+	defer l.Stop()
+	port := strconv.Itoa(l.Port())
+
+	// The bound address is announced by default
+	endpoints, err := l.Endpoints(nil)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"127.0.0.1:" + port}, endpoints)
+
+	endpoints, err = l.Endpoints([]string{"example.com", "192.0.2.1:9006", "2001:db8::1", "[2001:db8::2]"})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"example.com:" + port, "192.0.2.1:9006", "[2001:db8::1]:" + port, "[2001:db8::2]:" + port}, endpoints)
+
+	_, err = l.Endpoints([]string{":9006"})
+	assert.NotNil(t, err)
+	_, err = l.Endpoints([]string{"example.com:bad"})
+	assert.NotNil(t, err)
+}
+
+func TestDiscover(t *testing.T) {
+	m := newTestSignatureManager(t)
+	m.contract.UUID = "contract"
+	l, err := newListener(m.auth, ":0")
+	assert.Nil(t, err)
+	defer l.Stop()
+	m.listener = l
+
+	// We are our own peer here
+	conn, _, err := net.ConnectWithCertificate("127.0.0.1:"+strconv.Itoa(l.Port()), m.auth.Cert, m.auth.Key, m.auth.CA, m.keyHash[0])
+	assert.Nil(t, err)
+	defer func() { _ = conn.Close() }()
+	client := cAPI.NewClientClient(conn)
+
+	// Unknown contract until we connect to the peers
+	hello, err := client.Discover(context.Background(), newHello("contract", m.keyHash[0]))
+	assert.Nil(t, err)
+	assert.Equal(t, pAPI.ErrorCode_INVARG, hello.ErrorCode.Code)
+
+	assert.Nil(t, l.join(m))
+	assert.NotNil(t, l.join(&SignatureManager{contract: m.contract}))
+	hello, err = client.Discover(context.Background(), newHello("contract", m.keyHash[0]))
+	assert.Nil(t, err)
+	assert.Nil(t, checkDiscover(hello, "contract", m.keyHash[0]))
+	assert.Equal(t, ProtocolVersion, hello.Protocol)
+
+	// The key hash must be the one of the certificate
+	hello, err = client.Discover(context.Background(), newHello("contract", m.keyHash[1]))
+	assert.Nil(t, err)
+	assert.Equal(t, pAPI.ErrorCode_BADAUTH, hello.ErrorCode.Code)
+
+	// Older clients are rejected
+	hello, err = client.Discover(context.Background(), &cAPI.Hello{Version: "0.3.0", ContractUuid: "contract"})
+	assert.Nil(t, err)
+	assert.Equal(t, pAPI.ErrorCode_INVARG, hello.ErrorCode.Code)
+
+	l.leave(m)
+	assert.Nil(t, l.routeContract("contract"))
+}
+
+func TestReadyHandshake(t *testing.T) {
+	m := newTestSignatureManager(t)
+	l, err := newListener(m.auth, ":0")
+	assert.Nil(t, err)
+	defer l.Stop()
+	m.listener = l
+	m.platform = &fakePlatform{}
+
+	// We are our own peer here
+	conn, _, err := net.ConnectWithCertificate("127.0.0.1:"+strconv.Itoa(l.Port()), m.auth.Cert, m.auth.Key, m.auth.CA, m.keyHash[0])
+	assert.Nil(t, err)
+	defer func() { _ = conn.Close() }()
+	client := cAPI.NewClientClient(conn)
+	m.peers = map[string]*cAPI.ClientClient{"other@example.com": &client}
+
+	// Not ready until the evidence of the signature is accepted
+	result, err := client.Ready(context.Background(), &cAPI.ReadySignal{SignatureUUID: m.uuid})
+	assert.Nil(t, err)
+	assert.Equal(t, pAPI.ErrorCode_INTERR, result.Code)
+
+	go func() {
+		time.Sleep(3 * readyRetryDelay)
+		m.mail = "me@example.com"
+		_ = m.acceptEvidence()
+	}()
+	assert.Nil(t, m.waitPeersReady())
+	assert.NotNil(t, m.incomingPromises)
+
+	// Only signers are accepted
+	m.hashToID = map[string]uint32{}
+	result, _ = client.Ready(context.Background(), &cAPI.ReadySignal{SignatureUUID: m.uuid})
+	assert.Equal(t, pAPI.ErrorCode_BADAUTH, result.Code)
+
+	// Unreachable peer
+	timeout := net.DefaultTimeout
+	net.DefaultTimeout = 3 * readyRetryDelay
+	defer func() { net.DefaultTimeout = timeout }()
+	assert.NotNil(t, m.sendReady(1))
+	m.peers = map[string]*cAPI.ClientClient{}
+	assert.NotNil(t, m.sendReady(1))
+}
@@ -1,12 +1,15 @@
 package sign
 
 import (
+	"bytes"
+	"encoding/hex"
+	"errors"
 	"fmt"
 	"time"
 
+	"dfss/auth"
 	cAPI "dfss/dfssc/api"
 	"dfss/dfssc/common"
-	"dfss/net"
 )
 
 // ExchangeAllSignatures creates and sends signatures to all the signers of the contract
@@ -34,6 +37,7 @@ func (m *SignatureManager) ExchangeAllSignatures() error {
 				errorChan <- err2
 				return
 			}
+			m.emit(Event{Type: EventSignatureSent, Mail: m.contract.Signers[id].Email})
 			errorChan <- nil
 		}(id)
 	}
@@ -45,23 +49,78 @@ func (m *SignatureManager) ExchangeAllSignatures() error {
 		}
 	}
 
-	return <-allReceived
+	m.updateJournal()
+
+	select {
+	case err = <-allReceived:
+		return err
+	case <-m.Cancel:
+		m.cancelled = true
+		return errors.New("Signature cancelled")
+	}
 }
 
 // CreateSignature creates a signature from a sequence ID to another
 // provided the specified sequence indexes are valid
+//
+// The payload of the signature is our certified signature of the contract (see auth.CertifiedSignature).
 func (m *SignatureManager) CreateSignature(from, to uint32) (*cAPI.Signature, error) {
 	context, err := m.createContext(from, to)
 	if err != nil {
 		return nil, err
 	}
 
+	signature, err := auth.NewCertifiedSignature(m.auth.Key, m.auth.Cert, common.SignatureStatement(context))
+	if err != nil {
+		return nil, err
+	}
+
+	payload, err := signature.Marshal()
+	if err != nil {
+		return nil, err
+	}
+
 	return &cAPI.Signature{
 		Context: context,
-		Payload: []byte{0x42},
+		Payload: payload,
 	}, nil
 }
 
+// checkSignature verifies that the signature was made by its sender for the current signature,
+// and returns the sequence ID of the sender.
+func (m *SignatureManager) checkSignature(signature *cAPI.Signature) (uint32, error) {
+	if signature.Context == nil {
+		return 0, errors.New("Missing signature context")
+	}
+
+	senderID, exist := m.hashToID[fmt.Sprintf("%x", signature.Context.SenderKeyHash)]
+	if !exist {
+		return 0, errors.New("Unknown signature sender")
+	}
+
+	h, _ := hex.DecodeString(m.contract.File.Hash)
+	if signature.Context.SignatureUUID != m.uuid || !bytes.Equal(signature.Context.Seal, m.seal) || !bytes.Equal(signature.Context.ContractDocumentHash, h) {
+		return 0, errors.New("Signature is not related to the current signature")
+	}
+
+	certified, err := auth.ParseCertifiedSignature(signature.Payload)
+	if err != nil {
+		return 0, err
+	}
+
+	cert, err := certified.Verify(common.SignatureStatement(signature.Context))
+	if err != nil {
+		return 0, err
+	}
+
+	err = auth.CheckCertificate(cert, m.auth.CA, signature.Context.SenderKeyHash)
+	if err != nil {
+		return 0, err
+	}
+
+	return senderID, nil
+}
+
 // ReceiveAllSignatures receive all the signatures
 func (m *SignatureManager) ReceiveAllSignatures(out chan error) {
 	myID, err := m.FindID()
@@ -76,15 +135,22 @@ func (m *SignatureManager) ReceiveAllSignatures(out chan error) {
 	for len(pendingSet) > 0 {
 		select {
 		// Waiting for signatures from grpc handler
-		case signatureIface := <-m.cServerIface.incomingSignatures:
+		case signatureIface := <-m.incomingSignatures:
 			signature := (signatureIface).(*cAPI.Signature)
-			senderID, exist := m.hashToID[fmt.Sprintf("%x", signature.Context.SenderKeyHash)]
-			if exist {
-				pendingSet, _ = common.Remove(pendingSet, senderID)
+			senderID, err := m.checkSignature(signature)
+			if err != nil {
+				m.dlog("received an invalid signature: " + err.Error())
+				continue
+			}
+
+			// a signature is archived only once per sender
+			pendingSet, err = common.Remove(pendingSet, senderID)
+			if err == nil {
 				m.archives.receivedSignatures = append(m.archives.receivedSignatures, signature)
+				m.emit(Event{Type: EventSignatureReceived, Mail: m.contract.Signers[senderID].Email})
 			}
 
-		case <-time.After(net.DefaultTimeout):
+		case <-time.After(m.evidenceTimeout()):
 			out <- fmt.Errorf("Signature reception timeout!")
 			return
 		}
@@ -0,0 +1,144 @@
+package sign
+
+import (
+	"crypto"
+	"crypto/sha512"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"dfss/auth"
+	"dfss/dfssc/security"
+	pAPI "dfss/dfssp/api"
+	"dfss/dfssp/contract"
+	tAPI "dfss/dfsst/api"
+	"dfss/net"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+)
+
+// testCAKey is the root key of the last test manager, to seal its launch signal again
+var testCAKey crypto.Signer
+
+func newTestSignatureManager(t *testing.T) *SignatureManager {
+	// Testdata keys are too small for SHA-512 signatures
+	a := security.NewAuthContainer("")
+	caKey, _ := auth.GeneratePrivateKey(1024)
+	testCAKey = caKey
+	caPem, _ := auth.GetSelfSignedCertificate(1, 0, "FR", "DFSS", "TEST", "ca", caKey)
+	a.CA, _ = auth.PEMToCertificate(caPem)
+	a.Key, _ = auth.GeneratePrivateKey(1024)
+	csrPem, _ := auth.GetCertificateRequest("FR", "DFSS", "TEST", "me@example.com", a.Key)
+	csr, _ := auth.PEMToCertificateRequest(csrPem)
+	certPem, err := auth.GetCertificate(1, 1, csr, a.CA, caKey)
+	assert.Nil(t, err)
+	a.Cert, _ = auth.PEMToCertificate(certPem)
+
+	myHash := auth.GetCertificateHash(a.Cert)
+	otherHash := sha512.Sum512([]byte("other"))
+
+	m := &SignatureManager{
+		ctx:    context.Background(),
+		config: &Config{},
+		auth:   a,
+		contract: &contract.JSON{
+			File: &contract.FileJSON{Hash: "0102"},
+			Signers: []contract.SignerJSON{
+				{Email: "me@example.com", Hash: fmt.Sprintf("%x", myHash)},
+				{Email: "other@example.com", Hash: fmt.Sprintf("%x", otherHash)},
+			},
+		},
+		ttpData:  &pAPI.LaunchSignature_TTP{},
+		sequence: []uint32{0, 1, 0, 1},
+		keyHash:  [][]byte{myHash, otherHash[:]},
+		uuid:     "signature",
+	}
+	sealTestSignatureManager(t, m)
+	m.makeSignersHashToIDMap()
+	return m
+}
+
+// sealTestSignatureManager seals the current launch data of a test manager
+func sealTestSignatureManager(t *testing.T, m *SignatureManager) {
+	var err error
+	m.seal, err = auth.SignStructure(testCAKey, pAPI.LaunchSignature{
+		SignatureUuid: m.uuid,
+		DocumentHash:  []byte{0x01, 0x02},
+		KeyHash:       m.keyHash,
+		Sequence:      m.sequence,
+		Deadline:      m.deadline,
+	})
+	assert.Nil(t, err)
+}
+
+func TestCheckSignature(t *testing.T) {
+	m := newTestSignatureManager(t)
+
+	signature, err := m.CreateSignature(0, 1)
+	assert.Nil(t, err)
+
+	id, err := m.checkSignature(signature)
+	assert.Nil(t, err)
+	assert.Equal(t, uint32(0), id)
+
+	// Bad context
+	signature.Context.SignatureUUID = "other"
+	_, err = m.checkSignature(signature)
+	assert.NotNil(t, err)
+	signature.Context.SignatureUUID = m.uuid
+
+	// Bad sender
+	signature.Context.SenderKeyHash = m.keyHash[1]
+	_, err = m.checkSignature(signature)
+	assert.NotNil(t, err)
+	signature.Context.SenderKeyHash = m.keyHash[0]
+
+	// Bad payload
+	signature.Payload[len(signature.Payload)-1]++
+	_, err = m.checkSignature(signature)
+	assert.NotNil(t, err)
+}
+
+type fakeTTP struct {
+	alerts []*tAPI.AlertRequest
+}
+
+func (f *fakeTTP) Alert(ctx context.Context, in *tAPI.AlertRequest, opts ...grpc.CallOption) (*tAPI.TTPResponse, error) {
+	f.alerts = append(f.alerts, in)
+	return &tAPI.TTPResponse{Abort: true}, nil
+}
+
+func (f *fakeTTP) Recover(ctx context.Context, in *tAPI.RecoverRequest, opts ...grpc.CallOption) (*tAPI.TTPResponse, error) {
+	return nil, errors.New("unexpected recover")
+}
+
+func TestSignatureRoundResolve(t *testing.T) {
+	timeout := net.DefaultTimeout
+	net.DefaultTimeout = 100 * time.Millisecond
+	defer func() { net.DefaultTimeout = timeout }()
+
+	m := newTestSignatureManager(t)
+	m.mail = "me@example.com"
+	m.archives = &Archives{}
+	m.incomingSignatures = make(chan interface{}, chanBufferSize)
+	m.OnProgressUpdate = func(int, int) {}
+	m.currentIndex = -1
+	m.lastValidIndex = 2
+
+	// No connection to the ttp
+	err := m.signFrom(-1)
+	assert.Equal(t, "No connection to TTP, aborting!", err.Error())
+
+	// The signature of the other signer never comes
+	ttp := &fakeTTP{}
+	m.ttp = ttp
+	err = m.signFrom(-1)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(ttp.alerts))
+	assert.Equal(t, uint32(2), ttp.alerts[0].Index)
+	assert.Equal(t, 1, len(ttp.alerts[0].Promises))
+	assert.Equal(t, uint32(2), ttp.alerts[0].Promises[0].Index)
+	assert.Equal(t, OutcomeAborted, m.Outcome())
+}
@@ -1,23 +1,23 @@
 package sign
 
 import (
+	"bytes"
+	"crypto/x509"
 	"errors"
 	"fmt"
+	gonet "net"
 	"strconv"
 	"sync"
 	"time"
 
-	"dfss"
+	"dfss/auth"
 	cAPI "dfss/dfssc/api"
 	"dfss/dfssc/common"
 	"dfss/dfssc/security"
-	dAPI "dfss/dfssd/api"
 	pAPI "dfss/dfssp/api"
 	"dfss/dfssp/contract"
 	tAPI "dfss/dfsst/api"
-	"dfss/dfsst/entities"
 	"dfss/net"
-	"github.com/spf13/viper"
 	"golang.org/x/net/context"
 	"google.golang.org/grpc"
 )
@@ -25,31 +25,52 @@ import (
 // Limit the buffer size of the channels
 const chanBufferSize = 100
 
+// Delay between two ready signals to a peer that does not accept the evidence of the signature yet
+const readyRetryDelay = 100 * time.Millisecond
+
 // SignatureManager handles the signature of a contract.
 type SignatureManager struct {
-	auth           *security.AuthContainer
-	contract       *contract.JSON // contains the contractUUID, the list of the signers' hashes, the hash of the contract
-	platform       pAPI.PlatformClient
-	platformConn   *grpc.ClientConn
-	ttp            tAPI.TTPClient
-	ttpData        *pAPI.LaunchSignature_TTP
-	peersConn      map[string]*grpc.ClientConn
-	peers          map[string]*cAPI.ClientClient
-	hashToID       map[string]uint32
-	nbReady        int
-	cServer        *grpc.Server
-	cServerIface   clientServer
-	sequence       []uint32
-	lastValidIndex int // the last index at which we sent a promise
-	currentIndex   int
-	myID           uint32
-	uuid           string
-	keyHash        [][]byte
-	mail           string
-	archives       *Archives
-	seal           []byte
-	cancelled      bool
-	finished       bool
+	ctx                context.Context // every request of the signature derives from it, see requestContext
+	config             *Config
+	auth               *security.AuthContainer
+	contract           *contract.JSON // contains the contractUUID, the list of the signers' hashes, the hash of the contract
+	platform           pAPI.PlatformClient
+	platformConn       *grpc.ClientConn
+	ttp                tAPI.TTPClient
+	ttpData            *pAPI.LaunchSignature_TTP
+	ttpCert            *x509.Certificate // nil if there is no ttp for this signature
+	peersConn          map[string]*grpc.ClientConn
+	peers              map[string]*cAPI.ClientClient
+	peersCert          map[string]*x509.Certificate
+	hashToID           map[string]uint32
+	nbReady            int
+	listener           *Listener
+	ownListener        bool // true if the listener was started for this signature only
+	incomingPromises   chan interface{}
+	incomingSignatures chan interface{}
+	sequence           []uint32
+	generator          string
+	lastValidIndex     int // the last index at which we sent a promise
+	currentIndex       int
+	myID               uint32
+	uuid               string
+	keyHash            [][]byte
+	mail               string
+	archives           *Archives
+	seal               []byte
+	deadline           int64         // deadline of an asynchronous signature (unix time), zero for a synchronous one
+	stopMailbox        chan struct{} // closed to stop fetching the mailbox of an asynchronous signature
+	stopRelay          func()        // stops listening to the relay of a synchronous signature
+	journalFile        string        // the recover data file, updated after every round
+	cancelled          bool
+	finished           bool
+	outcome            SignatureOutcome
+
+	events        chan Event     // nil if the signature was not started with Start
+	eventsDone    chan struct{}  // closed before the event channel, to release the pending sends
+	eventsMutex   sync.Mutex     // guards eventsClosed and eventsSending against the closing of the channel
+	eventsSending sync.WaitGroup // pending sends on the event channel
+	eventsClosed  bool
 
 	// Callbacks
 	OnSignerStatusUpdate func(mail string, status SignerStatus, data string)
@@ -62,13 +83,31 @@ type Archives struct {
 	receivedPromises   []*cAPI.Promise // TODO: improve by using a map
 	sentSignatures     []*cAPI.Signature
 	receivedSignatures []*cAPI.Signature
+	rejectedEvidence   []*common.RejectedEvidenceJSON
 	mutex              sync.Mutex
 }
 
-// NewSignatureManager populates a SignatureManager and connects to the platform.
+// NewSignatureManager populates a SignatureManager and connects to the platform, using the global configuration.
 func NewSignatureManager(passphrase string, c *contract.JSON) (*SignatureManager, error) {
+	return NewSignatureManagerWithConfig(passphrase, c, ConfigFromViper())
+}
+
+// NewSignatureManagerWithConfig populates a SignatureManager and connects to the platform, using the specified configuration.
+func NewSignatureManagerWithConfig(passphrase string, c *contract.JSON, config *Config) (*SignatureManager, error) {
+	return newSignatureManager(context.Background(), passphrase, c, config)
+}
+
+// newSignatureManager populates a SignatureManager whose requests are cancelled with the provided context
+func newSignatureManager(ctx context.Context, passphrase string, c *contract.JSON, config *Config) (*SignatureManager, error) {
+	auth, err := config.loadAuth(passphrase)
+	if err != nil {
+		return nil, err
+	}
+
 	m := &SignatureManager{
-		auth:     security.NewAuthContainer(passphrase),
+		ctx:      ctx,
+		config:   config,
+		auth:     auth,
 		contract: c,
 		archives: &Archives{
 			receivedPromises:   make([]*cAPI.Promise, 0),
@@ -77,18 +116,9 @@ func NewSignatureManager(passphrase string, c *contract.JSON) (*SignatureManager
 		},
 		Cancel: make(chan interface{}),
 	}
-	var err error
-	_, _, _, err = m.auth.LoadFiles()
-	if err != nil {
-		return nil, err
-	}
-
 	m.mail = m.auth.Cert.Subject.CommonName
-	dAPI.SetIdentifier(m.mail)
-	m.cServer = m.GetServer()
-	go func() { _ = net.Listen("0.0.0.0:"+strconv.Itoa(viper.GetInt("local_port")), m.cServer) }()
 
-	connp, err := net.Connect(viper.GetString("platform_addrport"), m.auth.Cert, m.auth.Key, m.auth.CA, nil)
+	connp, err := config.connectPlatform(ctx, m.auth)
 	if err != nil {
 		return nil, err
 	}
@@ -96,32 +126,69 @@ func NewSignatureManager(passphrase string, c *contract.JSON) (*SignatureManager
 	m.platform = pAPI.NewPlatformClient(connp)
 	m.platformConn = connp
 
+	// Peers with revoked certificates must be rejected during the signature.
+	// The revocation list is refreshed by the listener, as long as it runs.
+	m.listener = config.Listener
+	if m.listener == nil {
+		m.listener, err = NewListener(config, m.auth, gonet.JoinHostPort(config.LocalHost, strconv.Itoa(config.LocalPort)))
+		if err != nil {
+			_ = m.platformConn.Close()
+			return nil, err
+		}
+		m.ownListener = true
+	} else if !bytes.Equal(m.listener.auth.Cert.Raw, m.auth.Cert.Raw) {
+		// A shared listener is only able to authenticate as its own user
+		_ = m.platformConn.Close()
+		return nil, errors.New("The listener is not running for " + m.mail)
+	} else {
+		crlCtx, cancel := m.requestContext()
+		_, err = security.UpdateRevocationList(crlCtx, m.platform, m.auth.CA, config.CRLFile)
+		cancel()
+		if err != nil {
+			_ = m.platformConn.Close()
+			return nil, err
+		}
+	}
+
 	m.peersConn = make(map[string]*grpc.ClientConn)
 	m.peers = make(map[string]*cAPI.ClientClient)
+	m.peersCert = make(map[string]*x509.Certificate)
 	for _, u := range c.Signers {
 		if u.Email != m.auth.Cert.Subject.CommonName {
 			m.peers[u.Email] = nil
 		}
 	}
 
-	// Initialize TTP AuthContainer
-	// This is needed to use platform seal verification client-side
-	entities.AuthContainer = m.auth
-
 	return m, nil
 }
 
 // ConnectToPeers tries to fetch the list of users for this contract, and tries to establish a connection to each peer.
 func (m *SignatureManager) ConnectToPeers() error {
-	localIps, err := net.ExternalInterfaceAddr()
+	return m.connectToPeers(m.ctx)
+}
+
+// connectToPeers joins the signature room of the platform, until every peer is connected or the context is done.
+// Signers of an asynchronous contract do not connect to each other, as they exchange their evidence through the platform.
+func (m *SignatureManager) connectToPeers(ctx context.Context) error {
+	if m.isAsync() {
+		return nil
+	}
+
+	endpoints, err := m.listener.Endpoints(m.config.Advertise)
 	if err != nil {
 		return err
 	}
+	// Peers discovering us must find the contract, before we join the signature room
+	if err = m.listener.join(m); err != nil {
+		return err
+	}
 
-	stream, err := m.platform.JoinSignature(context.Background(), &pAPI.JoinSignatureRequest{
+	ips, port := legacyEndpoints(endpoints)
+	stream, err := m.platform.JoinSignature(ctx, &pAPI.JoinSignatureRequest{
 		ContractUuid: m.contract.UUID,
-		Port:         uint32(viper.GetInt("local_port")),
-		Ip:           localIps,
+		Port:         port,
+		Ip:           ips,
+		Endpoint:     endpoints,
 	})
 	if err != nil {
 		m.finished = true
@@ -129,7 +196,8 @@ func (m *SignatureManager) ConnectToPeers() error {
 		return err
 	}
 
-	c := make(chan error)
+	// Buffered, as the result of the loop is not read once the signature is cancelled
+	c := make(chan error, 1)
 	go connectToPeersLoop(m, stream, c)
 
 	select {
@@ -180,12 +248,15 @@ func (m *SignatureManager) addPeer(user *pAPI.User) (ready bool, err error) {
 	}
 
 	var conn *grpc.ClientConn
-	for _, ip := range user.Ip {
-		addrPort := ip + ":" + strconv.Itoa(int(user.Port))
-		m.OnSignerStatusUpdate(user.Email, StatusConnecting, addrPort)
+	var cert *x509.Certificate
+	err = errors.New("No endpoint offered by the peer")
+	for _, addrPort := range peerEndpoints(user) {
+		m.updateSignerStatus(user.Email, StatusConnecting, addrPort)
 
 		// This is an certificate authentificated TLS connection
-		conn, err = net.Connect(addrPort, m.auth.Cert, m.auth.Key, m.auth.CA, user.KeyHash)
+		ctx, cancel := m.requestContext()
+		conn, cert, err = net.ConnectContext(ctx, addrPort, m.auth.Cert, m.auth.Key, m.auth.CA, user.KeyHash)
+		cancel()
 		if err == nil {
 			break
 		}
@@ -196,8 +267,12 @@ func (m *SignatureManager) addPeer(user *pAPI.User) (ready bool, err error) {
 		}
 	}
 
+	if err != nil && !m.cancelled && m.deadline == 0 {
+		// The peer may be behind a NAT, falling back on the relay of the platform
+		return m.addRelayedPeer(user, err)
+	}
 	if err != nil {
-		m.OnSignerStatusUpdate(user.Email, StatusError, err.Error())
+		m.updateSignerStatus(user.Email, StatusError, err.Error())
 		return false, err
 	}
 
@@ -208,40 +283,106 @@ func (m *SignatureManager) addPeer(user *pAPI.User) (ready bool, err error) {
 	// The connection is encapsulated into the interface, so we
 	// need to create another way to access it
 	m.peersConn[user.Email] = conn
+	// The certificate is needed to create promises for this peer
+	m.peersCert[user.Email] = cert
 
-	ctx, cancel := context.WithTimeout(context.Background(), net.DefaultTimeout)
+	ctx, cancel := m.requestContext()
 	defer cancel()
-	msg, err := client.Discover(ctx, &cAPI.Hello{Version: dfss.Version})
+	msg, err := client.Discover(ctx, newHello(m.contract.UUID, auth.GetCertificateHash(m.auth.Cert)))
+	if err == nil {
+		err = checkDiscover(msg, m.contract.UUID, user.KeyHash)
+	}
 	if err != nil {
-		m.OnSignerStatusUpdate(user.Email, StatusError, err.Error())
+		m.updateSignerStatus(user.Email, StatusError, err.Error())
 		return false, err
 	}
 
 	// Printing answer: application version
-	// TODO check certificate
-	m.OnSignerStatusUpdate(user.Email, StatusConnected, msg.Version)
-
-	// Check if we have any other peer to connect to
-	if lastConnection == nil {
-		m.nbReady++
-		if m.nbReady == len(m.contract.Signers)-1 {
-			return true, nil
+	m.updateSignerStatus(user.Email, StatusConnected, fmt.Sprintf("%s (protocol %d)", msg.Version, msg.Protocol))
+	return m.countReady(lastConnection), nil
+}
+
+// peerEndpoints returns the endpoints (host:port) offered by a peer, built from its ips and port for older platforms
+func peerEndpoints(user *pAPI.User) []string {
+	if len(user.Endpoint) > 0 {
+		return user.Endpoint
+	}
+
+	endpoints := make([]string, len(user.Ip))
+	for i, ip := range user.Ip {
+		endpoints[i] = gonet.JoinHostPort(ip, strconv.Itoa(int(user.Port)))
+	}
+	return endpoints
+}
+
+// legacyEndpoints returns the hosts of the endpoints sharing the port of the first one, and this port.
+// Older platforms only broadcast a single port for every ip of a signer.
+func legacyEndpoints(endpoints []string) (ips []string, port uint32) {
+	for _, e := range endpoints {
+		host, p, _ := gonet.SplitHostPort(e)
+		n, _ := strconv.ParseUint(p, 10, 16)
+		if len(ips) == 0 {
+			port = uint32(n)
+		}
+		if uint32(n) == port {
+			ips = append(ips, host)
 		}
 	}
+	return
+}
+
+// addRelayedPeer sends the evidence for a peer through the relay of the platform, when it cannot be reached directly.
+// The certificate broadcasted by the platform must match the sealed hash of the signer, as it authenticates the relayed evidence.
+func (m *SignatureManager) addRelayedPeer(user *pAPI.User, cause error) (ready bool, err error) {
+	cert, err := x509.ParseCertificate(user.Certificate)
+	if err == nil {
+		err = auth.CheckCertificate(cert, m.auth.CA, user.KeyHash)
+	}
+	if err == nil && net.IsRevoked(cert) {
+		err = errors.New("revoked certificate")
+	}
+	if err != nil {
+		m.updateSignerStatus(user.Email, StatusError, cause.Error())
+		return false, cause
+	}
 
-	return false, nil
+	if conn, ok := m.peersConn[user.Email]; ok {
+		_ = conn.Close()
+		delete(m.peersConn, user.Email)
+	}
+
+	var client cAPI.ClientClient = &relayClient{m: m, hash: user.KeyHash, cert: cert}
+	lastConnection := m.peers[user.Email]
+	m.peers[user.Email] = &client
+	m.peersCert[user.Email] = cert
+
+	m.updateSignerStatus(user.Email, StatusConnected, "relayed through the platform")
+	return m.countReady(lastConnection), nil
+}
+
+// countReady counts a newly connected peer, and returns true once every peer is connected
+func (m *SignatureManager) countReady(lastConnection *cAPI.ClientClient) bool {
+	if lastConnection != nil {
+		return false
+	}
+	m.nbReady++
+	return m.nbReady == len(m.contract.Signers)-1
 }
 
 // SendReadySign sends the READY signal to the platform, and wait (potentially a long time) for START signal.
 func (m *SignatureManager) SendReadySign() (signatureUUID string, err error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+	ctx, cancel := context.WithTimeout(m.ctx, 10*time.Minute)
 	defer cancel()
 
-	c := make(chan *pAPI.LaunchSignature)
+	// Buffered, as the launch signal is not read once the signature is cancelled
+	c := make(chan *pAPI.LaunchSignature, 1)
 	go func() {
-		launch, _ := m.platform.ReadySign(ctx, &pAPI.ReadySignRequest{
+		launch, err := m.platform.ReadySign(ctx, &pAPI.ReadySignRequest{
 			ContractUuid: m.contract.UUID,
 		})
+		if err != nil {
+			launch = &pAPI.LaunchSignature{ErrorCode: &pAPI.ErrorCode{Code: pAPI.ErrorCode_INTERR, Message: err.Error()}}
+		}
 		c <- launch
 	}()
 
@@ -264,33 +405,167 @@ func (m *SignatureManager) SendReadySign() (signatureUUID string, err error) {
 	}
 
 	// Check signers from platform data
-	if len(m.contract.Signers) != len(launch.KeyHash) {
-		err = errors.New("Corrupted DFSS file: bad number of signers, unable to sign safely")
+	err = m.checkSigners(launch.KeyHash)
+	if err != nil {
 		m.finished = true
 		m.closeConnections()
 		return
 	}
 
-	for i, s := range m.contract.Signers {
-		if s.Hash != fmt.Sprintf("%x", launch.KeyHash[i]) {
-			err = errors.New("Corrupted DFSS file: signer " + s.Email + " has an invalid hash, unable to sign safely")
-			m.finished = true
-			m.closeConnections()
-			return
-		}
+	// Check sequence from platform data
+	err = checkSequence(m.contract.SequenceGenerator, launch.SequenceGenerator, launch.Sequence, len(m.contract.Signers))
+	if err != nil {
+		m.finished = true
+		m.closeConnections()
+		return
+	}
+
+	// Check deadline from platform data
+	err = m.checkDeadline(launch.Deadline)
+	if err != nil {
+		m.finished = true
+		m.closeConnections()
+		return
 	}
 
 	// Connect to TTP, if any
 	err = m.connectToTTP(launch.Ttp)
 
 	m.sequence = launch.Sequence
+	m.generator = launch.SequenceGenerator
 	m.uuid = launch.SignatureUuid
 	m.keyHash = launch.KeyHash
 	m.seal = launch.Seal
+	m.deadline = launch.Deadline
 	signatureUUID = m.uuid
+
+	if err == nil && m.deadline != 0 {
+		err = m.loadCertificates(launch.Certificate)
+	}
+	if err == nil {
+		err = m.acceptEvidence()
+	}
 	return
 }
 
+// acceptEvidence creates the buffers of the incoming evidence, and routes the evidence of the signature to them.
+// From then on, the promises and signatures sent by the peers are kept until the protocol needs them.
+//
+// The handlers of the listener, the relay and the mailbox read the identity of the signature concurrently:
+// uuid, keyHash, myID, hashToID and the buffers must not be modified once the manager is registered.
+func (m *SignatureManager) acceptEvidence() error {
+	myID, err := m.FindID()
+	if err != nil {
+		return err
+	}
+	m.myID = myID
+
+	m.makeSignersHashToIDMap()
+	m.incomingPromises = make(chan interface{}, chanBufferSize)
+	m.incomingSignatures = make(chan interface{}, chanBufferSize)
+	if err = m.listener.register(m); err != nil {
+		return err
+	}
+
+	m.startMailbox()
+	m.startRelay()
+	return nil
+}
+
+// waitPeersReady sends the ready signal to every peer, until each of them accepts the evidence of the signature.
+// No promise can be sent before this barrier, as a peer may not be able to keep it yet.
+// Asynchronous signers do not wait for each other, as their mailboxes keep the evidence.
+func (m *SignatureManager) waitPeersReady() error {
+	if m.deadline != 0 {
+		return nil
+	}
+
+	peers := common.GetAllButOne(m.sequence, m.myID)
+	c := make(chan error, len(peers))
+	for _, id := range peers {
+		go func(id uint32) { c <- m.sendReady(id) }(id)
+	}
+
+	for range peers {
+		err := <-c
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// sendReady sends the ready signal to a peer, until it is accepted, the timeout is reached or the signature is cancelled
+func (m *SignatureManager) sendReady(to uint32) error {
+	client, mail := m.GetClient(to)
+	if client == nil {
+		return errors.New("No connection to " + mail)
+	}
+
+	deadline := time.Now().Add(m.config.timeout())
+	for {
+		ctx, cancel := m.requestContext()
+		result, err := (*client).Ready(ctx, &cAPI.ReadySignal{SignatureUUID: m.uuid})
+		cancel()
+		if err == nil && result.Code == pAPI.ErrorCode_SUCCESS {
+			return nil
+		}
+
+		if time.Now().After(deadline) {
+			return errors.New("Signer " + mail + " is not ready, unable to sign safely")
+		}
+		select {
+		case <-time.After(readyRetryDelay):
+		case <-m.ctx.Done():
+			return errors.New("Signature cancelled")
+		}
+	}
+}
+
+// checkSigners checks that the signers hashes sent by the platform are the ones of the contract
+func (m *SignatureManager) checkSigners(keyHash [][]byte) error {
+	if len(m.contract.Signers) != len(keyHash) {
+		return errors.New("Corrupted DFSS file: bad number of signers, unable to sign safely")
+	}
+
+	for i, s := range m.contract.Signers {
+		if s.Hash != fmt.Sprintf("%x", keyHash[i]) {
+			return errors.New("Corrupted DFSS file: signer " + s.Email + " has an invalid hash, unable to sign safely")
+		}
+	}
+	return nil
+}
+
+// checkSequence checks that the sequence sent by the platform is built by the generator chosen for the contract,
+// so that a platform cannot weaken the fairness of the protocol with a shorter sequence.
+// Platforms that do not send the generator name are using the default one.
+func checkSequence(expected, generator string, sequence []uint32, n int) error {
+	if expected == "" {
+		expected = contract.DefaultSequenceGenerator
+	}
+	if generator == "" {
+		generator = contract.DefaultSequenceGenerator
+	}
+	if generator != expected {
+		return errors.New("Corrupted signing sequence: expecting " + expected + " generator, got " + generator)
+	}
+
+	g, err := contract.GetSequenceGenerator(generator)
+	if err != nil {
+		return err
+	}
+	theoric := g.Generate(n)
+	if len(theoric) != len(sequence) {
+		return errors.New("Corrupted signing sequence: bad length, unable to sign safely")
+	}
+	for i := range theoric {
+		if theoric[i] != sequence[i] {
+			return errors.New("Corrupted signing sequence: bad signer at index " + strconv.Itoa(i) + ", unable to sign safely")
+		}
+	}
+	return nil
+}
+
 // connectToTTP : tries to open a connection with the ttp specified in the contract.
 func (m *SignatureManager) connectToTTP(ttp *pAPI.LaunchSignature_TTP) error {
 	if ttp == nil {
@@ -301,31 +576,28 @@ func (m *SignatureManager) connectToTTP(ttp *pAPI.LaunchSignature_TTP) error {
 		return nil
 	}
 
-	// TODO check that the connection spots missing TTP and returns an error quickly enough
-	conn, err := net.Connect(ttp.Addrport, m.auth.Cert, m.auth.Key, m.auth.CA, ttp.Hash)
+	ctx, cancel := m.requestContext()
+	defer cancel()
+	conn, cert, err := net.ConnectContext(ctx, ttp.Addrport, m.auth.Cert, m.auth.Key, m.auth.CA, ttp.Hash)
 	if err != nil {
 		return err
 	}
 
 	m.ttpData = ttp
+	m.ttpCert = cert
 	m.ttp = tAPI.NewTTPClient(conn)
 	return nil
 }
 
-// Initialize computes the values needed for the start of the signing
+// Initialize computes the values needed for the start of the signing, once the evidence of the signature is accepted
 func (m *SignatureManager) Initialize() (int, error) {
-	myID, err := m.FindID()
-	if err != nil {
-		return 0, err
-	}
-	m.myID = myID
-
-	m.currentIndex, err = common.FindNextIndex(m.sequence, myID, -1)
+	var err error
+	m.currentIndex, err = common.FindNextIndex(m.sequence, m.myID, -1)
 	if err != nil {
 		return 0, err
 	}
 
-	nextIndex, err := common.FindNextIndex(m.sequence, myID, m.currentIndex)
+	nextIndex, err := common.FindNextIndex(m.sequence, m.myID, m.currentIndex)
 	if err != nil {
 		return 0, err
 	}
@@ -350,3 +622,20 @@ func (m *SignatureManager) FindID() (uint32, error) {
 func (m *SignatureManager) IsTerminated() bool {
 	return m.cancelled || m.finished
 }
+
+// Outcome returns the result of the signature, once it is terminated
+func (m *SignatureManager) Outcome() SignatureOutcome {
+	return m.outcome
+}
+
+// requestContext returns the context of a request of the signature, cancelled with the signature or after the configured timeout
+func (m *SignatureManager) requestContext() (context.Context, context.CancelFunc) {
+	return context.WithTimeout(m.ctx, m.config.timeout())
+}
+
+// dlog sends a message to the demonstrator on behalf of the signer, as several managers may run in the same process
+func (m *SignatureManager) dlog(log string) {
+	if m.config.Log != nil {
+		m.config.Log(m.mail, log)
+	}
+}
@@ -3,6 +3,7 @@ package sign
 import (
 	"testing"
 
+	pAPI "dfss/dfssp/api"
 	"dfss/dfssp/contract"
 	"github.com/stretchr/testify/assert"
 )
@@ -35,3 +36,41 @@ func TestFindId(t *testing.T) {
 	assert.Equal(t, err.Error(), "Mail couldn't be found amongst signers")
 	assert.Equal(t, id, uint32(0))
 }
+
+func TestCheckSequence(t *testing.T) {
+	squared := []uint32{0, 1, 2, 0, 1, 2, 0, 1, 2}
+	compact := []uint32{0, 1, 2, 0, 1, 2, 0}
+
+	assert.Nil(t, checkSequence("", "", squared, 3))
+	assert.Nil(t, checkSequence("", contract.SequenceSquared, squared, 3))
+	assert.Nil(t, checkSequence(contract.SequenceCompact, contract.SequenceCompact, compact, 3))
+
+	// Platform not following the contract
+	assert.NotNil(t, checkSequence(contract.SequenceSquared, contract.SequenceCompact, compact, 3))
+	assert.NotNil(t, checkSequence(contract.SequenceCompact, "", squared, 3))
+
+	// Sequence not matching the generator
+	assert.NotNil(t, checkSequence(contract.SequenceCompact, contract.SequenceCompact, squared, 3))
+	assert.NotNil(t, checkSequence("", "", []uint32{0, 1, 2, 0, 2, 1, 0, 1, 2}, 3))
+
+	// Unknown generator
+	assert.NotNil(t, checkSequence("unknown", "unknown", squared, 3))
+}
+
+func TestPeerEndpoints(t *testing.T) {
+	user := &pAPI.User{Ip: []string{"192.0.2.1", "2001:db8::1"}, Port: 9005}
+	assert.Equal(t, []string{"192.0.2.1:9005", "[2001:db8::1]:9005"}, peerEndpoints(user))
+
+	user.Endpoint = []string{"192.0.2.1:9006"}
+	assert.Equal(t, user.Endpoint, peerEndpoints(user))
+}
+
+func TestLegacyEndpoints(t *testing.T) {
+	ips, port := legacyEndpoints([]string{"192.0.2.1:9005", "192.0.2.2:9006", "[2001:db8::1]:9005"})
+	assert.Equal(t, []string{"192.0.2.1", "2001:db8::1"}, ips)
+	assert.Equal(t, uint32(9005), port)
+
+	ips, port = legacyEndpoints(nil)
+	assert.Equal(t, 0, len(ips))
+	assert.Equal(t, uint32(0), port)
+}
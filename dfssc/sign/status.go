@@ -10,3 +10,14 @@ const (
 	StatusConnected
 	StatusError
 )
+
+// SignatureOutcome represents the result of a terminated signature.
+type SignatureOutcome int
+
+// These constants represent the different results of a signature.
+const (
+	OutcomeUnknown  SignatureOutcome = iota // the signature is not terminated, or failed
+	OutcomeSigned                           // every signature was received from the peers
+	OutcomeResolved                         // the signed contract was generated by the ttp
+	OutcomeAborted                          // the ttp sent an abort token
+)
@@ -10,8 +10,8 @@ import (
 )
 
 // Register a user using the provided parameters
-func Register(passphrase, country, organization, unit, mail string, bits int) error {
-	manager, err := NewRegisterManager(passphrase, country, organization, unit, mail, bits, common.SubViper("file_key", "file_cert", "file_ca"))
+func Register(passphrase, country, organization, unit, mail, keyType string, bits int) error {
+	manager, err := NewRegisterManager(passphrase, country, organization, unit, mail, keyType, bits, common.SubViper("file_key", "file_cert", "file_ca"))
 	if err != nil {
 		return err
 	}
@@ -53,7 +53,7 @@ func NewConfig(viper *viper.Viper) (*Config, error) {
 }
 
 // SaveConfigToFile marshals checks the  validity of the certificate and private key,
-// marshals the struct in JSON, encrypt the string using AES-256 with the provided passphrase,
+// marshals the struct in JSON, encrypt the string with the provided passphrase (see auth.EncryptWithPassphrase),
 // and finally save it to a file
 func (c *Config) SaveConfigToFile(fileName, passphrase, keyPassphrase string) error {
 	if common.FileExists(fileName) {
@@ -74,7 +74,7 @@ func (c *Config) SaveConfigToFile(fileName, passphrase, keyPassphrase string) er
 		return err
 	}
 
-	encodedData, err := security.EncryptStringAES(passphrase, data)
+	encodedData, err := auth.EncryptWithPassphrase(data, passphrase)
 	if err != nil {
 		return err
 	}
@@ -84,7 +84,8 @@ func (c *Config) SaveConfigToFile(fileName, passphrase, keyPassphrase string) er
 }
 
 // DecodeConfiguration : decrypt and unmarshal the given configuration file
-// to create a Config object. It also checks the validity of the certificate and private key
+// to create a Config object. It also checks the validity of the certificate and private key.
+// If the configuration file uses the legacy format, it is migrated to the current one.
 func DecodeConfiguration(fileName, keyPassphrase, confPassphrase string) (*Config, error) {
 	if !common.FileExists(fileName) {
 		return nil, fmt.Errorf("No such file: %s", fileName)
@@ -99,7 +100,13 @@ func DecodeConfiguration(fileName, keyPassphrase, confPassphrase string) (*Confi
 		return nil, err
 	}
 
-	decodedData, err := security.DecryptAES(confPassphrase, encodedData)
+	legacy := !auth.IsPassphraseEncrypted(encodedData)
+	var decodedData []byte
+	if legacy {
+		decodedData, err = security.DecryptAES(confPassphrase, encodedData)
+	} else {
+		decodedData, err = auth.DecryptWithPassphrase(encodedData, confPassphrase)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -115,6 +122,17 @@ func DecodeConfiguration(fileName, keyPassphrase, confPassphrase string) (*Confi
 		return nil, err
 	}
 
+	if legacy {
+		encodedData, err = auth.EncryptWithPassphrase(decodedData, confPassphrase)
+		if err != nil {
+			return nil, err
+		}
+		err = common.SaveToDisk(encodedData, fileName)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	return &config, nil
 }
 
@@ -3,8 +3,10 @@ package user
 import (
 	"errors"
 	"regexp"
+	"strings"
 	"time"
 
+	"dfss/auth"
 	"dfss/dfssc/common"
 	"dfss/dfssc/security"
 	pb "dfss/dfssp/api"
@@ -22,14 +24,15 @@ type RegisterManager struct {
 	organization string
 	unit         string
 	mail         string
+	keyType      string
 	bits         int
 }
 
 var mailRegex = regexp.MustCompile(`.+@.+\..+`)
 
 // NewRegisterManager return a new Register Manager to register a user
-func NewRegisterManager(passphrase, country, organization, unit, mail string, bits int, v *viper.Viper) (*RegisterManager, error) {
-	m := &RegisterManager{v, passphrase, country, organization, unit, mail, bits}
+func NewRegisterManager(passphrase, country, organization, unit, mail, keyType string, bits int, v *viper.Viper) (*RegisterManager, error) {
+	m := &RegisterManager{v, passphrase, country, organization, unit, mail, keyType, bits}
 
 	if err := m.checkValidParams(); err != nil {
 		return nil, err
@@ -42,13 +45,21 @@ func NewRegisterManager(passphrase, country, organization, unit, mail string, bi
 	return m, nil
 }
 
-// Check the validity of the provided email, passphrase and bits
+// Check the validity of the provided email, passphrase, key type and bits
 func (m *RegisterManager) checkValidParams() error {
 	if b := mailRegex.MatchString(m.mail); !b {
 		return errors.New("Provided mail is not valid")
 	}
 
-	if m.bits != 2048 && m.bits != 4096 {
+	validType := false
+	for _, t := range auth.KeyTypes {
+		validType = validType || t == m.keyType
+	}
+	if !validType {
+		return errors.New("Type of the key should be one of " + strings.Join(auth.KeyTypes, ", "))
+	}
+
+	if m.keyType == auth.KeyTypeRSA && m.bits != 2048 && m.bits != 4096 {
 		return errors.New("Length of the key should be 2048 or 4096 bits")
 	}
 
@@ -116,7 +127,7 @@ func (m *RegisterManager) GetCertificate() error {
 
 // Builds a certificate request
 func (m *RegisterManager) buildCertificateRequest() (string, error) {
-	key, err := security.GenerateKeys(m.bits, m.passphrase)
+	key, err := security.GenerateKeys(m.keyType, m.bits, m.passphrase)
 	if err != nil {
 		return "", err
 	}
@@ -78,23 +78,23 @@ func TestMain(m *testing.M) {
 
 // Test the validation of the fields
 func TestRegisterValidation(t *testing.T) {
-	_, err := NewRegisterManager("password", "FR", "organization", "unit", "dummy", 2048, mock(fca, fcert, fkey))
+	_, err := NewRegisterManager("password", "FR", "organization", "unit", "dummy", auth.KeyTypeRSA, 2048, mock(fca, fcert, fkey))
 	assert.True(t, err != nil, "Email is invalid")
 
-	_, err = NewRegisterManager("password", "FR", "organization", "unit", "mpcs@dfss.io", 2048, mock(fca, fkey, fkey))
+	_, err = NewRegisterManager("password", "FR", "organization", "unit", "mpcs@dfss.io", auth.KeyTypeRSA, 2048, mock(fca, fkey, fkey))
 	assert.True(t, err != nil, "Cert file is the same as key file")
 
-	_, err = NewRegisterManager("password", "FR", "organization", "unit", "mpcs@dfss.io", 2048, mock("inexistant.pem", fcert, fkey))
+	_, err = NewRegisterManager("password", "FR", "organization", "unit", "mpcs@dfss.io", auth.KeyTypeRSA, 2048, mock("inexistant.pem", fcert, fkey))
 	assert.True(t, err != nil, "CA file is invalid")
 
 	f, _ := os.Create(fcert)
 	_ = f.Close()
-	_, err = NewRegisterManager("password", "FR", "organization", "unit", "mpcs@dfss.io", 2048, mock(fca, fcert, fkey))
+	_, err = NewRegisterManager("password", "FR", "organization", "unit", "mpcs@dfss.io", auth.KeyTypeRSA, 2048, mock(fca, fcert, fkey))
 	assert.True(t, err != nil, "Cert file already exist")
 
 	k, _ := os.Create(fkey)
 	_ = k.Close()
-	_, err = NewRegisterManager("password", "FR", "organization", "unit", "mpcs@dfss.io", 2048, mock(fca, fcert, fkey))
+	_, err = NewRegisterManager("password", "FR", "organization", "unit", "mpcs@dfss.io", auth.KeyTypeRSA, 2048, mock(fca, fcert, fkey))
 	assert.True(t, err != nil, "Key file already exist")
 
 	_ = os.Remove(fcert)
@@ -104,7 +104,7 @@ func TestRegisterValidation(t *testing.T) {
 // Test the error codes received from the mock
 // Only the SUCCESS code should not raise an error
 func TestGetCertificate(t *testing.T) {
-	manager, err := NewRegisterManager("password", "FR", "organization", "unit", "dfss@success.io", 2048, mock(fca, fcert, fkey))
+	manager, err := NewRegisterManager("password", "FR", "organization", "unit", "dfss@success.io", auth.KeyTypeRSA, 2048, mock(fca, fcert, fkey))
 	assert.True(t, err == nil, "An error occurred while processing")
 	err = manager.GetCertificate()
 	assert.True(t, err == nil, "An error occurred while getting the certificate")
@@ -118,7 +118,7 @@ func TestGetCertificate(t *testing.T) {
 
 // Test an invalid error code and check we get an error
 func testRegisterInvalidResponse(t *testing.T, mail string) {
-	manager, err := NewRegisterManager("password", "FR", "organization", "unit", mail, 2048, mock(fca, fcert+mail, fkey+mail))
+	manager, err := NewRegisterManager("password", "FR", "organization", "unit", mail, auth.KeyTypeRSA, 2048, mock(fca, fcert+mail, fkey+mail))
 
 	assert.True(t, err == nil, "An error occurred while processing")
 	err = manager.GetCertificate()
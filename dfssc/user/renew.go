@@ -0,0 +1,92 @@
+package user
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/x509"
+	"errors"
+
+	"dfss/auth"
+	"dfss/dfssc/security"
+	"dfss/dfssp/api"
+	"dfss/net"
+	"github.com/spf13/viper"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+)
+
+// Renew asks the platform for a new certificate, before the current one expires, and replaces the current one on the disk.
+//
+// If keyType is not empty, a new private key of this type is generated and replaces the current one,
+// protected by the same passphrase. The size in bits is only used for RSA keys.
+func Renew(passphrase, keyType string, bits int) error {
+	if keyType == auth.KeyTypeRSA && bits != 2048 && bits != 4096 {
+		return errors.New("Length of the key should be 2048 or 4096 bits")
+	}
+
+	a := security.NewAuthContainer(passphrase)
+	ca, cert, key, err := a.LoadFiles()
+	if err != nil {
+		return err
+	}
+
+	newKey := key
+	if keyType != "" {
+		newKey, err = auth.GenerateKey(keyType, bits)
+		if err != nil {
+			return err
+		}
+	}
+
+	request, err := security.GenerateCertificateRequest(first(cert.Subject.Country), first(cert.Subject.Organization), first(cert.Subject.OrganizationalUnit), cert.Subject.CommonName, newKey)
+	if err != nil {
+		return err
+	}
+
+	conn, err := net.Connect(viper.GetString("platform_addrport"), cert, key, ca, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = conn.Close() }()
+
+	client := api.NewPlatformClient(conn)
+	ctx, cancel := context.WithTimeout(context.Background(), net.DefaultTimeout)
+	defer cancel()
+	response, err := client.Renew(ctx, &api.RenewRequest{Request: request})
+	if err != nil {
+		return errors.New(grpc.ErrorDesc(err))
+	}
+
+	newCert, err := auth.PEMToCertificate([]byte(response.ClientCert))
+	if err != nil {
+		return err
+	}
+	if err = auth.CheckCertificate(newCert, ca, auth.GetCertificateHash(newCert)); err != nil {
+		return err
+	}
+	if !samePublicKey(newCert.PublicKey, newKey.Public()) {
+		return errors.New("The renewed certificate does not match the private key")
+	}
+
+	if keyType != "" {
+		err = security.ReplacePrivateKey(newKey, viper.GetString("file_key"), passphrase)
+		if err != nil {
+			return err
+		}
+	}
+
+	return security.SaveCertificate(response.ClientCert, viper.GetString("file_cert"))
+}
+
+func samePublicKey(a, b crypto.PublicKey) bool {
+	derA, errA := x509.MarshalPKIXPublicKey(a)
+	derB, errB := x509.MarshalPKIXPublicKey(b)
+	return errA == nil && errB == nil && bytes.Equal(derA, derB)
+}
+
+func first(values []string) string {
+	if len(values) == 0 {
+		return ""
+	}
+	return values[0]
+}
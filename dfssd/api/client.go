@@ -2,6 +2,7 @@ package api
 
 import (
 	"fmt"
+	"sync"
 	"time"
 
 	"golang.org/x/net/context"
@@ -9,7 +10,10 @@ import (
 	"google.golang.org/grpc/grpclog"
 )
 
+// The parameters and the connection are shared by the whole process, and guarded by mutex.
+// Concurrent clients should log with DLogFrom rather than change the identifier.
 var (
+	mutex               sync.Mutex
 	address, identifier string
 	demo, verbose       bool
 	// lazy initializer
@@ -20,14 +24,18 @@ var (
 // Configure is used to update current parameters.
 // Call it at least one time before the first DLog call.
 func Configure(verbswitch, activated bool, addrport, id string) {
+	mutex.Lock()
+	defer mutex.Unlock()
 	address = addrport
 	identifier = id
 	demo = activated
 	verbose = verbswitch
 }
 
-// SetIdentifier updates the current client identifier.
+// SetIdentifier updates the default client identifier used by DLog.
 func SetIdentifier(id string) {
+	mutex.Lock()
+	defer mutex.Unlock()
 	identifier = id
 }
 
@@ -48,6 +56,8 @@ func dInit() error {
 //
 // This should be called at the end of any program that import this library
 func DClose() {
+	mutex.Lock()
+	defer mutex.Unlock()
 	if dial != nil {
 		err := dial.Close()
 		if err != nil {
@@ -56,10 +66,19 @@ func DClose() {
 	}
 }
 
-// DLog send a message to the demonstrator
+// DLog send a message to the demonstrator, on behalf of the current client identifier
 //
 // The client is dialed in a lazy way
 func DLog(log string) {
+	mutex.Lock()
+	id := identifier
+	mutex.Unlock()
+	DLogFrom(id, log)
+}
+
+// DLogFrom send a message to the demonstrator, on behalf of the given identifier
+func DLogFrom(id, log string) {
+	mutex.Lock()
 	// check verbose switch
 	if verbose {
 		fmt.Println(log)
@@ -67,6 +86,7 @@ func DLog(log string) {
 
 	// check demo switch
 	if !demo {
+		mutex.Unlock()
 		return
 	}
 
@@ -74,12 +94,16 @@ func DLog(log string) {
 	if dial == nil {
 		err := dInit()
 		if err != nil {
+			mutex.Unlock()
 			return // fail silently
 		}
 	}
-	_, err := demoClient.SendLog(
+	client := demoClient
+	mutex.Unlock()
+
+	_, err := client.SendLog(
 		context.Background(),
-		&Log{Timestamp: time.Now().UnixNano(), Identifier: identifier, Log: log})
+		&Log{Timestamp: time.Now().UnixNano(), Identifier: id, Log: log})
 
 	if err != nil {
 		grpclog.Printf("Fail to send message: %v", err)
@@ -13,15 +13,22 @@ It has these top-level messages:
 	ErrorCode
 	AuthRequest
 	RegisteredUser
+	RenewRequest
 	Empty
 	PostContractRequest
 	GetContractRequest
 	Contract
+	DocumentChunk
 	JoinSignatureRequest
 	UserConnected
 	User
 	ReadySignRequest
 	LaunchSignature
+	RevocationList
+	Evidence
+	RelayRequest
+	FetchEvidenceRequest
+	EvidenceList
 */
 package api
 
@@ -132,6 +139,16 @@ func (m *RegisteredUser) String() string            { return proto.CompactTextSt
 func (*RegisteredUser) ProtoMessage()               {}
 func (*RegisteredUser) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{3} }
 
+type RenewRequest struct {
+	// / Certificate request (CSR) as PEM, signed by the current or a new private key
+	Request string `protobuf:"bytes,1,opt,name=request" json:"request,omitempty"`
+}
+
+func (m *RenewRequest) Reset()                    { *m = RenewRequest{} }
+func (m *RenewRequest) String() string            { return proto.CompactTextString(m) }
+func (*RenewRequest) ProtoMessage()               {}
+func (*RenewRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{4} }
+
 // / An empty message, used when no parameters are required for a query or an answer.
 type Empty struct {
 }
@@ -139,7 +156,7 @@ type Empty struct {
 func (m *Empty) Reset()                    { *m = Empty{} }
 func (m *Empty) String() string            { return proto.CompactTextString(m) }
 func (*Empty) ProtoMessage()               {}
-func (*Empty) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{4} }
+func (*Empty) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{5} }
 
 type PostContractRequest struct {
 	// / Contract SHA-512 hash
@@ -150,12 +167,19 @@ type PostContractRequest struct {
 	Signer []string `protobuf:"bytes,3,rep,name=signer" json:"signer,omitempty"`
 	// / Additional comment
 	Comment string `protobuf:"bytes,4,opt,name=comment" json:"comment,omitempty"`
+	// / Name of the signing sequence generator, empty for the default one
+	SequenceGenerator string `protobuf:"bytes,5,opt,name=sequenceGenerator" json:"sequenceGenerator,omitempty"`
+	// / Deadline of an asynchronous signature (unix time in seconds), zero for a synchronous one.
+	// Signers of an asynchronous signature exchange their evidence through the mailboxes of the platform until this deadline.
+	Deadline int64 `protobuf:"varint,6,opt,name=deadline" json:"deadline,omitempty"`
+	// / The document has been uploaded with PostDocument, and is hosted by the platform for the signers
+	Hosted bool `protobuf:"varint,7,opt,name=hosted" json:"hosted,omitempty"`
 }
 
 func (m *PostContractRequest) Reset()                    { *m = PostContractRequest{} }
 func (m *PostContractRequest) String() string            { return proto.CompactTextString(m) }
 func (*PostContractRequest) ProtoMessage()               {}
-func (*PostContractRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{5} }
+func (*PostContractRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{6} }
 
 type GetContractRequest struct {
 	// / UUID of the requested contract
@@ -165,7 +189,7 @@ type GetContractRequest struct {
 func (m *GetContractRequest) Reset()                    { *m = GetContractRequest{} }
 func (m *GetContractRequest) String() string            { return proto.CompactTextString(m) }
 func (*GetContractRequest) ProtoMessage()               {}
-func (*GetContractRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{6} }
+func (*GetContractRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{7} }
 
 // / The fetched contract when using GetContract
 type Contract struct {
@@ -178,7 +202,7 @@ type Contract struct {
 func (m *Contract) Reset()                    { *m = Contract{} }
 func (m *Contract) String() string            { return proto.CompactTextString(m) }
 func (*Contract) ProtoMessage()               {}
-func (*Contract) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{7} }
+func (*Contract) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{8} }
 
 func (m *Contract) GetErrorCode() *ErrorCode {
 	if m != nil {
@@ -187,6 +211,18 @@ func (m *Contract) GetErrorCode() *ErrorCode {
 	return nil
 }
 
+// / A part of a document hosted by the platform
+type DocumentChunk struct {
+	// / The SHA-512 hash of the document, only in the first chunk of an upload
+	Hash []byte `protobuf:"bytes,1,opt,name=hash,proto3" json:"hash,omitempty"`
+	Data []byte `protobuf:"bytes,2,opt,name=data,proto3" json:"data,omitempty"`
+}
+
+func (m *DocumentChunk) Reset()                    { *m = DocumentChunk{} }
+func (m *DocumentChunk) String() string            { return proto.CompactTextString(m) }
+func (*DocumentChunk) ProtoMessage()               {}
+func (*DocumentChunk) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{9} }
+
 type JoinSignatureRequest struct {
 	// / The contract UUID to join
 	ContractUuid string `protobuf:"bytes,1,opt,name=contractUuid" json:"contractUuid,omitempty"`
@@ -194,12 +230,15 @@ type JoinSignatureRequest struct {
 	Port uint32 `protobuf:"varint,2,opt,name=port" json:"port,omitempty"`
 	// / The offered ips for P2P communication of the client
 	Ip []string `protobuf:"bytes,3,rep,name=ip" json:"ip,omitempty"`
+	// / The offered endpoints (host:port) for P2P communication of the client, with IPv6 support
+	// / Each endpoint can have its own port, for clients behind port forwarding
+	Endpoint []string `protobuf:"bytes,4,rep,name=endpoint" json:"endpoint,omitempty"`
 }
 
 func (m *JoinSignatureRequest) Reset()                    { *m = JoinSignatureRequest{} }
 func (m *JoinSignatureRequest) String() string            { return proto.CompactTextString(m) }
 func (*JoinSignatureRequest) ProtoMessage()               {}
-func (*JoinSignatureRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{8} }
+func (*JoinSignatureRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{10} }
 
 // / UserConnected is emitted by the platform to the client to announce a new client connection, through a stream.
 // Previously connected clients are also emitted one by one just after the beginning of the stream.
@@ -216,7 +255,7 @@ type UserConnected struct {
 func (m *UserConnected) Reset()                    { *m = UserConnected{} }
 func (m *UserConnected) String() string            { return proto.CompactTextString(m) }
 func (*UserConnected) ProtoMessage()               {}
-func (*UserConnected) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{9} }
+func (*UserConnected) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{11} }
 
 func (m *UserConnected) GetErrorCode() *ErrorCode {
 	if m != nil {
@@ -240,12 +279,16 @@ type User struct {
 	Ip []string `protobuf:"bytes,3,rep,name=ip" json:"ip,omitempty"`
 	// / The port offered by the user for P2P
 	Port uint32 `protobuf:"varint,4,opt,name=port" json:"port,omitempty"`
+	// / The DER certificate of the user, to reach it through the relay of the platform if P2P fails
+	Certificate []byte `protobuf:"bytes,5,opt,name=certificate,proto3" json:"certificate,omitempty"`
+	// / The endpoints (host:port) offered by the user for P2P, ip and port are kept for older clients
+	Endpoint []string `protobuf:"bytes,6,rep,name=endpoint" json:"endpoint,omitempty"`
 }
 
 func (m *User) Reset()                    { *m = User{} }
 func (m *User) String() string            { return proto.CompactTextString(m) }
 func (*User) ProtoMessage()               {}
-func (*User) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{10} }
+func (*User) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{12} }
 
 type ReadySignRequest struct {
 	// / The contract UUID to be ready for
@@ -255,7 +298,7 @@ type ReadySignRequest struct {
 func (m *ReadySignRequest) Reset()                    { *m = ReadySignRequest{} }
 func (m *ReadySignRequest) String() string            { return proto.CompactTextString(m) }
 func (*ReadySignRequest) ProtoMessage()               {}
-func (*ReadySignRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{11} }
+func (*ReadySignRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{13} }
 
 // / LaunchSignature is emitted by the platform when every signers of a specific contract are ready.
 type LaunchSignature struct {
@@ -271,16 +314,24 @@ type LaunchSignature struct {
 	Sequence []uint32 `protobuf:"varint,5,rep,name=sequence" json:"sequence,omitempty"`
 	// / The ttp can be nil if no ttp is available for this signature
 	Ttp *LaunchSignature_TTP `protobuf:"bytes,6,opt,name=ttp" json:"ttp,omitempty"`
+	// / The name of the generator used to build the signing sequence
+	SequenceGenerator string `protobuf:"bytes,7,opt,name=sequenceGenerator" json:"sequenceGenerator,omitempty"`
+	// / The deadline of an asynchronous signature (unix time in seconds), zero for a synchronous one
+	Deadline int64 `protobuf:"varint,8,opt,name=deadline" json:"deadline,omitempty"`
+	// / The DER certificates of the signers of an asynchronous signature, in the same order as keyHash.
+	// They are not sealed, as they can be checked against keyHash.
+	Certificate [][]byte `protobuf:"bytes,9,rep,name=certificate,proto3" json:"certificate,omitempty"`
 	// / The cryptographic object of the signature of this structure (seal and errorCode excepted) by the platform, for data certification.
+	// / The certificates are not part of the seal either.
 	// / The signature is computed using auth.SignStructure function:
-	// / PKCS1v15 + SHA512 hash of the string representation of the structure
+	// / version byte + PKCS1v15 + SHA512 hash of the canonical encoding of the structure (see auth.CanonicalEncode)
 	Seal []byte `protobuf:"bytes,10,opt,name=seal,proto3" json:"seal,omitempty"`
 }
 
 func (m *LaunchSignature) Reset()                    { *m = LaunchSignature{} }
 func (m *LaunchSignature) String() string            { return proto.CompactTextString(m) }
 func (*LaunchSignature) ProtoMessage()               {}
-func (*LaunchSignature) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{12} }
+func (*LaunchSignature) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{14} }
 
 func (m *LaunchSignature) GetErrorCode() *ErrorCode {
 	if m != nil {
@@ -305,23 +356,117 @@ type LaunchSignature_TTP struct {
 func (m *LaunchSignature_TTP) Reset()                    { *m = LaunchSignature_TTP{} }
 func (m *LaunchSignature_TTP) String() string            { return proto.CompactTextString(m) }
 func (*LaunchSignature_TTP) ProtoMessage()               {}
-func (*LaunchSignature_TTP) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{12, 0} }
+func (*LaunchSignature_TTP) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{14, 0} }
+
+// / RevocationList contains the certificates revoked by the platform, for instance when a user unregisters.
+type RevocationList struct {
+	// / The result code
+	ErrorCode *ErrorCode `protobuf:"bytes,1,opt,name=errorCode" json:"errorCode,omitempty"`
+	// / The X.509 certificate revocation list signed by the platform root certificate (PEM)
+	Crl []byte `protobuf:"bytes,2,opt,name=crl,proto3" json:"crl,omitempty"`
+}
+
+func (m *RevocationList) Reset()                    { *m = RevocationList{} }
+func (m *RevocationList) String() string            { return proto.CompactTextString(m) }
+func (*RevocationList) ProtoMessage()               {}
+func (*RevocationList) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{15} }
+
+func (m *RevocationList) GetErrorCode() *ErrorCode {
+	if m != nil {
+		return m.ErrorCode
+	}
+	return nil
+}
+
+// / Evidence is a promise or a signature of an asynchronous signature, encrypted for its recipient.
+type Evidence struct {
+	// / The signature UUID this evidence belongs to
+	SignatureUuid string `protobuf:"bytes,1,opt,name=signatureUuid" json:"signatureUuid,omitempty"`
+	// / The certificate hash of the sender, filled by the platform
+	SenderKeyHash []byte `protobuf:"bytes,2,opt,name=senderKeyHash,proto3" json:"senderKeyHash,omitempty"`
+	// / The certificate hash of the recipient
+	RecipientKeyHash []byte `protobuf:"bytes,3,opt,name=recipientKeyHash,proto3" json:"recipientKeyHash,omitempty"`
+	// / The evidence, signed by the sender and encrypted for the recipient (see auth.EncryptFor)
+	Payload []byte `protobuf:"bytes,4,opt,name=payload,proto3" json:"payload,omitempty"`
+	// / The contract UUID, only needed to relay the evidence of a synchronous signature
+	ContractUuid string `protobuf:"bytes,5,opt,name=contractUuid" json:"contractUuid,omitempty"`
+}
+
+func (m *Evidence) Reset()                    { *m = Evidence{} }
+func (m *Evidence) String() string            { return proto.CompactTextString(m) }
+func (*Evidence) ProtoMessage()               {}
+func (*Evidence) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{16} }
+
+type RelayRequest struct {
+	// / The contract UUID of the signature
+	ContractUuid string `protobuf:"bytes,1,opt,name=contractUuid" json:"contractUuid,omitempty"`
+	// / The signature UUID to relay the evidence of
+	SignatureUuid string `protobuf:"bytes,2,opt,name=signatureUuid" json:"signatureUuid,omitempty"`
+}
+
+func (m *RelayRequest) Reset()                    { *m = RelayRequest{} }
+func (m *RelayRequest) String() string            { return proto.CompactTextString(m) }
+func (*RelayRequest) ProtoMessage()               {}
+func (*RelayRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{17} }
+
+type FetchEvidenceRequest struct {
+	// / The signature UUID of the mailbox to fetch
+	SignatureUuid string `protobuf:"bytes,1,opt,name=signatureUuid" json:"signatureUuid,omitempty"`
+}
+
+func (m *FetchEvidenceRequest) Reset()                    { *m = FetchEvidenceRequest{} }
+func (m *FetchEvidenceRequest) String() string            { return proto.CompactTextString(m) }
+func (*FetchEvidenceRequest) ProtoMessage()               {}
+func (*FetchEvidenceRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{18} }
+
+// / EvidenceList contains the evidence waiting in the mailbox of a signer, in arrival order.
+type EvidenceList struct {
+	// / The result code
+	ErrorCode *ErrorCode  `protobuf:"bytes,1,opt,name=errorCode" json:"errorCode,omitempty"`
+	Evidence  []*Evidence `protobuf:"bytes,2,rep,name=evidence" json:"evidence,omitempty"`
+}
+
+func (m *EvidenceList) Reset()                    { *m = EvidenceList{} }
+func (m *EvidenceList) String() string            { return proto.CompactTextString(m) }
+func (*EvidenceList) ProtoMessage()               {}
+func (*EvidenceList) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{19} }
+
+func (m *EvidenceList) GetErrorCode() *ErrorCode {
+	if m != nil {
+		return m.ErrorCode
+	}
+	return nil
+}
+
+func (m *EvidenceList) GetEvidence() []*Evidence {
+	if m != nil {
+		return m.Evidence
+	}
+	return nil
+}
 
 func init() {
 	proto.RegisterType((*RegisterRequest)(nil), "api.RegisterRequest")
 	proto.RegisterType((*ErrorCode)(nil), "api.ErrorCode")
 	proto.RegisterType((*AuthRequest)(nil), "api.AuthRequest")
 	proto.RegisterType((*RegisteredUser)(nil), "api.RegisteredUser")
+	proto.RegisterType((*RenewRequest)(nil), "api.RenewRequest")
 	proto.RegisterType((*Empty)(nil), "api.Empty")
 	proto.RegisterType((*PostContractRequest)(nil), "api.PostContractRequest")
 	proto.RegisterType((*GetContractRequest)(nil), "api.GetContractRequest")
 	proto.RegisterType((*Contract)(nil), "api.Contract")
+	proto.RegisterType((*DocumentChunk)(nil), "api.DocumentChunk")
 	proto.RegisterType((*JoinSignatureRequest)(nil), "api.JoinSignatureRequest")
 	proto.RegisterType((*UserConnected)(nil), "api.UserConnected")
 	proto.RegisterType((*User)(nil), "api.User")
 	proto.RegisterType((*ReadySignRequest)(nil), "api.ReadySignRequest")
 	proto.RegisterType((*LaunchSignature)(nil), "api.LaunchSignature")
 	proto.RegisterType((*LaunchSignature_TTP)(nil), "api.LaunchSignature.TTP")
+	proto.RegisterType((*RevocationList)(nil), "api.RevocationList")
+	proto.RegisterType((*Evidence)(nil), "api.Evidence")
+	proto.RegisterType((*RelayRequest)(nil), "api.RelayRequest")
+	proto.RegisterType((*FetchEvidenceRequest)(nil), "api.FetchEvidenceRequest")
+	proto.RegisterType((*EvidenceList)(nil), "api.EvidenceList")
 	proto.RegisterEnum("api.ErrorCode_Code", ErrorCode_Code_name, ErrorCode_Code_value)
 }
 
@@ -342,6 +487,8 @@ type PlatformClient interface {
 	Auth(ctx context.Context, in *AuthRequest, opts ...grpc.CallOption) (*RegisteredUser, error)
 	// / Unregister a new user, authentication required.
 	Unregister(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*ErrorCode, error)
+	// / Renew the certificate of a registered user, authentication required.
+	Renew(ctx context.Context, in *RenewRequest, opts ...grpc.CallOption) (*RegisteredUser, error)
 	// / Create a new contract, authentication required.
 	PostContract(ctx context.Context, in *PostContractRequest, opts ...grpc.CallOption) (*ErrorCode, error)
 	// / Fetch a previously create contract, authentication required.
@@ -353,6 +500,24 @@ type PlatformClient interface {
 	// The response is returned when every signer is ready for a specific contract.
 	// Warning, can me answered with a very high delay.
 	ReadySign(ctx context.Context, in *ReadySignRequest, opts ...grpc.CallOption) (*LaunchSignature, error)
+	// / Fetch the current certificate revocation list, no authentication required.
+	GetRevocationList(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*RevocationList, error)
+	// / Store an encrypted evidence in the mailbox of a signer of an asynchronous signature, authentication required.
+	PostEvidence(ctx context.Context, in *Evidence, opts ...grpc.CallOption) (*ErrorCode, error)
+	// / Fetch and empty the mailbox of the authenticated signer for an asynchronous signature, authentication required.
+	FetchEvidence(ctx context.Context, in *FetchEvidenceRequest, opts ...grpc.CallOption) (*EvidenceList, error)
+	// / Open the relay of a signature, authentication required.
+	// The stream is triggered for each evidence relayed to the authenticated signer, until the stream is closed.
+	Relay(ctx context.Context, in *RelayRequest, opts ...grpc.CallOption) (Platform_RelayClient, error)
+	// / Forward an encrypted evidence to the relay of a signer, authentication required.
+	// Used when the signers cannot reach each other directly, the recipient must have opened its relay.
+	RelayEvidence(ctx context.Context, in *Evidence, opts ...grpc.CallOption) (*ErrorCode, error)
+	// / Upload a document to be hosted by the platform, before posting its contract, authentication required.
+	// The first chunk holds the SHA-512 hash of the document, which is checked once the stream is closed.
+	PostDocument(ctx context.Context, opts ...grpc.CallOption) (Platform_PostDocumentClient, error)
+	// / Download the hosted document of a contract, authentication required.
+	// Only signers of the contract can download it.
+	GetDocument(ctx context.Context, in *GetContractRequest, opts ...grpc.CallOption) (Platform_GetDocumentClient, error)
 }
 
 type platformClient struct {
@@ -390,6 +555,15 @@ func (c *platformClient) Unregister(ctx context.Context, in *Empty, opts ...grpc
 	return out, nil
 }
 
+func (c *platformClient) Renew(ctx context.Context, in *RenewRequest, opts ...grpc.CallOption) (*RegisteredUser, error) {
+	out := new(RegisteredUser)
+	err := grpc.Invoke(ctx, "/api.Platform/Renew", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *platformClient) PostContract(ctx context.Context, in *PostContractRequest, opts ...grpc.CallOption) (*ErrorCode, error) {
 	out := new(ErrorCode)
 	err := grpc.Invoke(ctx, "/api.Platform/PostContract", in, out, c.cc, opts...)
@@ -449,6 +623,140 @@ func (c *platformClient) ReadySign(ctx context.Context, in *ReadySignRequest, op
 	return out, nil
 }
 
+func (c *platformClient) GetRevocationList(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*RevocationList, error) {
+	out := new(RevocationList)
+	err := grpc.Invoke(ctx, "/api.Platform/GetRevocationList", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *platformClient) PostEvidence(ctx context.Context, in *Evidence, opts ...grpc.CallOption) (*ErrorCode, error) {
+	out := new(ErrorCode)
+	err := grpc.Invoke(ctx, "/api.Platform/PostEvidence", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *platformClient) FetchEvidence(ctx context.Context, in *FetchEvidenceRequest, opts ...grpc.CallOption) (*EvidenceList, error) {
+	out := new(EvidenceList)
+	err := grpc.Invoke(ctx, "/api.Platform/FetchEvidence", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *platformClient) Relay(ctx context.Context, in *RelayRequest, opts ...grpc.CallOption) (Platform_RelayClient, error) {
+	stream, err := grpc.NewClientStream(ctx, &_Platform_serviceDesc.Streams[1], c.cc, "/api.Platform/Relay", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &platformRelayClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Platform_RelayClient interface {
+	Recv() (*Evidence, error)
+	grpc.ClientStream
+}
+
+type platformRelayClient struct {
+	grpc.ClientStream
+}
+
+func (x *platformRelayClient) Recv() (*Evidence, error) {
+	m := new(Evidence)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *platformClient) RelayEvidence(ctx context.Context, in *Evidence, opts ...grpc.CallOption) (*ErrorCode, error) {
+	out := new(ErrorCode)
+	err := grpc.Invoke(ctx, "/api.Platform/RelayEvidence", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *platformClient) PostDocument(ctx context.Context, opts ...grpc.CallOption) (Platform_PostDocumentClient, error) {
+	stream, err := grpc.NewClientStream(ctx, &_Platform_serviceDesc.Streams[2], c.cc, "/api.Platform/PostDocument", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &platformPostDocumentClient{stream}
+	return x, nil
+}
+
+type Platform_PostDocumentClient interface {
+	Send(*DocumentChunk) error
+	CloseAndRecv() (*ErrorCode, error)
+	grpc.ClientStream
+}
+
+type platformPostDocumentClient struct {
+	grpc.ClientStream
+}
+
+func (x *platformPostDocumentClient) Send(m *DocumentChunk) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *platformPostDocumentClient) CloseAndRecv() (*ErrorCode, error) {
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	m := new(ErrorCode)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *platformClient) GetDocument(ctx context.Context, in *GetContractRequest, opts ...grpc.CallOption) (Platform_GetDocumentClient, error) {
+	stream, err := grpc.NewClientStream(ctx, &_Platform_serviceDesc.Streams[3], c.cc, "/api.Platform/GetDocument", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &platformGetDocumentClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Platform_GetDocumentClient interface {
+	Recv() (*DocumentChunk, error)
+	grpc.ClientStream
+}
+
+type platformGetDocumentClient struct {
+	grpc.ClientStream
+}
+
+func (x *platformGetDocumentClient) Recv() (*DocumentChunk, error) {
+	m := new(DocumentChunk)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 // Server API for Platform service
 
 type PlatformServer interface {
@@ -458,6 +766,8 @@ type PlatformServer interface {
 	Auth(context.Context, *AuthRequest) (*RegisteredUser, error)
 	// / Unregister a new user, authentication required.
 	Unregister(context.Context, *Empty) (*ErrorCode, error)
+	// / Renew the certificate of a registered user, authentication required.
+	Renew(context.Context, *RenewRequest) (*RegisteredUser, error)
 	// / Create a new contract, authentication required.
 	PostContract(context.Context, *PostContractRequest) (*ErrorCode, error)
 	// / Fetch a previously create contract, authentication required.
@@ -469,6 +779,24 @@ type PlatformServer interface {
 	// The response is returned when every signer is ready for a specific contract.
 	// Warning, can me answered with a very high delay.
 	ReadySign(context.Context, *ReadySignRequest) (*LaunchSignature, error)
+	// / Fetch the current certificate revocation list, no authentication required.
+	GetRevocationList(context.Context, *Empty) (*RevocationList, error)
+	// / Store an encrypted evidence in the mailbox of a signer of an asynchronous signature, authentication required.
+	PostEvidence(context.Context, *Evidence) (*ErrorCode, error)
+	// / Fetch and empty the mailbox of the authenticated signer for an asynchronous signature, authentication required.
+	FetchEvidence(context.Context, *FetchEvidenceRequest) (*EvidenceList, error)
+	// / Open the relay of a signature, authentication required.
+	// The stream is triggered for each evidence relayed to the authenticated signer, until the stream is closed.
+	Relay(*RelayRequest, Platform_RelayServer) error
+	// / Forward an encrypted evidence to the relay of a signer, authentication required.
+	// Used when the signers cannot reach each other directly, the recipient must have opened its relay.
+	RelayEvidence(context.Context, *Evidence) (*ErrorCode, error)
+	// / Upload a document to be hosted by the platform, before posting its contract, authentication required.
+	// The first chunk holds the SHA-512 hash of the document, which is checked once the stream is closed.
+	PostDocument(Platform_PostDocumentServer) error
+	// / Download the hosted document of a contract, authentication required.
+	// Only signers of the contract can download it.
+	GetDocument(*GetContractRequest, Platform_GetDocumentServer) error
 }
 
 func RegisterPlatformServer(s *grpc.Server, srv PlatformServer) {
@@ -529,6 +857,24 @@ func _Platform_Unregister_Handler(srv interface{}, ctx context.Context, dec func
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Platform_Renew_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RenewRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PlatformServer).Renew(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.Platform/Renew",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PlatformServer).Renew(ctx, req.(*RenewRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _Platform_PostContract_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(PostContractRequest)
 	if err := dec(in); err != nil {
@@ -604,6 +950,146 @@ func _Platform_ReadySign_Handler(srv interface{}, ctx context.Context, dec func(
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Platform_GetRevocationList_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PlatformServer).GetRevocationList(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.Platform/GetRevocationList",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PlatformServer).GetRevocationList(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Platform_PostEvidence_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Evidence)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PlatformServer).PostEvidence(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.Platform/PostEvidence",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PlatformServer).PostEvidence(ctx, req.(*Evidence))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Platform_FetchEvidence_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FetchEvidenceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PlatformServer).FetchEvidence(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.Platform/FetchEvidence",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PlatformServer).FetchEvidence(ctx, req.(*FetchEvidenceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Platform_Relay_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(RelayRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(PlatformServer).Relay(m, &platformRelayServer{stream})
+}
+
+type Platform_RelayServer interface {
+	Send(*Evidence) error
+	grpc.ServerStream
+}
+
+type platformRelayServer struct {
+	grpc.ServerStream
+}
+
+func (x *platformRelayServer) Send(m *Evidence) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Platform_RelayEvidence_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Evidence)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PlatformServer).RelayEvidence(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.Platform/RelayEvidence",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PlatformServer).RelayEvidence(ctx, req.(*Evidence))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Platform_PostDocument_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(PlatformServer).PostDocument(&platformPostDocumentServer{stream})
+}
+
+type Platform_PostDocumentServer interface {
+	SendAndClose(*ErrorCode) error
+	Recv() (*DocumentChunk, error)
+	grpc.ServerStream
+}
+
+type platformPostDocumentServer struct {
+	grpc.ServerStream
+}
+
+func (x *platformPostDocumentServer) SendAndClose(m *ErrorCode) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *platformPostDocumentServer) Recv() (*DocumentChunk, error) {
+	m := new(DocumentChunk)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _Platform_GetDocument_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(GetContractRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(PlatformServer).GetDocument(m, &platformGetDocumentServer{stream})
+}
+
+type Platform_GetDocumentServer interface {
+	Send(*DocumentChunk) error
+	grpc.ServerStream
+}
+
+type platformGetDocumentServer struct {
+	grpc.ServerStream
+}
+
+func (x *platformGetDocumentServer) Send(m *DocumentChunk) error {
+	return x.ServerStream.SendMsg(m)
+}
+
 var _Platform_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "api.Platform",
 	HandlerType: (*PlatformServer)(nil),
@@ -620,6 +1106,10 @@ var _Platform_serviceDesc = grpc.ServiceDesc{
 			MethodName: "Unregister",
 			Handler:    _Platform_Unregister_Handler,
 		},
+		{
+			MethodName: "Renew",
+			Handler:    _Platform_Renew_Handler,
+		},
 		{
 			MethodName: "PostContract",
 			Handler:    _Platform_PostContract_Handler,
@@ -632,6 +1122,22 @@ var _Platform_serviceDesc = grpc.ServiceDesc{
 			MethodName: "ReadySign",
 			Handler:    _Platform_ReadySign_Handler,
 		},
+		{
+			MethodName: "GetRevocationList",
+			Handler:    _Platform_GetRevocationList_Handler,
+		},
+		{
+			MethodName: "PostEvidence",
+			Handler:    _Platform_PostEvidence_Handler,
+		},
+		{
+			MethodName: "FetchEvidence",
+			Handler:    _Platform_FetchEvidence_Handler,
+		},
+		{
+			MethodName: "RelayEvidence",
+			Handler:    _Platform_RelayEvidence_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -639,57 +1145,96 @@ var _Platform_serviceDesc = grpc.ServiceDesc{
 			Handler:       _Platform_JoinSignature_Handler,
 			ServerStreams: true,
 		},
+		{
+			StreamName:    "Relay",
+			Handler:       _Platform_Relay_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "PostDocument",
+			Handler:       _Platform_PostDocument_Handler,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "GetDocument",
+			Handler:       _Platform_GetDocument_Handler,
+			ServerStreams: true,
+		},
 	},
 }
 
 var fileDescriptor0 = []byte{
-	// 768 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0x94, 0x55, 0xdd, 0x6e, 0xd3, 0x4a,
-	0x10, 0x4e, 0x62, 0x37, 0x3f, 0x93, 0x26, 0xb5, 0x36, 0x39, 0xe7, 0xf8, 0x44, 0xea, 0x51, 0xb5,
-	0x3a, 0x12, 0x55, 0x85, 0x92, 0x2a, 0x08, 0x10, 0xdc, 0xa5, 0x21, 0x6a, 0x8b, 0x4a, 0xa9, 0x36,
-	0x09, 0x48, 0x5c, 0x20, 0x19, 0x7b, 0xdb, 0x98, 0x26, 0xb6, 0xd9, 0xdd, 0x08, 0xf5, 0x8e, 0x47,
-	0xe0, 0x61, 0x10, 0x8f, 0x07, 0xec, 0xae, 0x7f, 0xe2, 0x84, 0x08, 0xa9, 0xbd, 0x70, 0x77, 0x7e,
-	0x76, 0xe6, 0x9b, 0x6f, 0x67, 0x26, 0xb0, 0xef, 0x5d, 0x73, 0xde, 0x53, 0x9f, 0xa8, 0xe7, 0x44,
-	0x7e, 0x2f, 0x9a, 0x3b, 0xe2, 0x3a, 0x64, 0x8b, 0x6e, 0xc4, 0x42, 0x11, 0x22, 0x43, 0xea, 0xf0,
-	0x00, 0xf6, 0x08, 0xbd, 0xf1, 0xb9, 0xa0, 0x8c, 0xd0, 0x4f, 0x4b, 0xca, 0x05, 0x6a, 0xc3, 0x0e,
-	0x5d, 0x38, 0xfe, 0xdc, 0x2e, 0x1e, 0x14, 0x0f, 0x6b, 0x24, 0x16, 0x90, 0x0d, 0x15, 0x16, 0x3b,
-	0xd8, 0x25, 0xad, 0x4f, 0x45, 0xfc, 0xad, 0x08, 0xb5, 0x11, 0x63, 0x21, 0x1b, 0x86, 0x1e, 0x45,
-	0x0f, 0xc0, 0x74, 0xe5, 0x7f, 0x7d, 0xb9, 0xd9, 0x6f, 0x75, 0x65, 0x92, 0x6e, 0x66, 0xed, 0xaa,
-	0x0f, 0xd1, 0x0e, 0x2a, 0xe0, 0x82, 0x72, 0xee, 0xdc, 0xd0, 0x34, 0x60, 0x22, 0x62, 0x0f, 0x4c,
-	0x1d, 0xaa, 0x0e, 0x95, 0xf1, 0x74, 0x38, 0x1c, 0x8d, 0xc7, 0x56, 0x01, 0x01, 0x94, 0xcf, 0x2f,
-	0xdf, 0x0c, 0xc8, 0xa9, 0x55, 0x54, 0x86, 0x93, 0xc1, 0x8b, 0xc1, 0x74, 0x72, 0x66, 0x95, 0x94,
-	0xf0, 0x76, 0x40, 0x2e, 0xcf, 0x2f, 0x4f, 0x2d, 0x03, 0xb5, 0x94, 0xd7, 0x64, 0x44, 0x88, 0xf5,
-	0x33, 0xfd, 0x2b, 0xca, 0x82, 0x2a, 0x93, 0xf3, 0x57, 0xa3, 0xd7, 0xd3, 0x89, 0xf5, 0x23, 0xd3,
-	0xe2, 0x67, 0x50, 0x1f, 0x2c, 0xc5, 0xec, 0xcf, 0x55, 0x4b, 0xad, 0x08, 0x6f, 0x69, 0x90, 0x40,
-	0x8c, 0x05, 0x7c, 0x0c, 0xcd, 0x94, 0x34, 0xea, 0x4d, 0x39, 0x65, 0xe8, 0x3f, 0x00, 0x77, 0xee,
-	0xd3, 0x40, 0x0c, 0x29, 0x13, 0x49, 0x88, 0x9c, 0x06, 0x57, 0x60, 0x67, 0xb4, 0x88, 0xc4, 0x1d,
-	0xfe, 0x0c, 0xad, 0xab, 0x90, 0x8b, 0x61, 0x18, 0x08, 0xe6, 0xb8, 0x22, 0xcd, 0x8e, 0xc0, 0x9c,
-	0x39, 0x7c, 0xa6, 0x6f, 0xee, 0x12, 0x7d, 0x46, 0x1d, 0xa8, 0x5e, 0xfb, 0x73, 0x1a, 0x38, 0x8b,
-	0x94, 0xa1, 0x4c, 0x46, 0x7f, 0x43, 0x99, 0xfb, 0x37, 0x01, 0x65, 0xb6, 0x71, 0x60, 0x48, 0x4b,
-	0x22, 0x29, 0x52, 0xdd, 0x70, 0xb1, 0x90, 0x69, 0x6d, 0x33, 0x26, 0x35, 0x11, 0xf1, 0x21, 0xa0,
-	0x53, 0xba, 0x2d, 0xef, 0x72, 0xe9, 0x7b, 0x09, 0x62, 0x7d, 0xc6, 0x17, 0x50, 0x4d, 0xdd, 0xd0,
-	0x43, 0xa8, 0xd1, 0xf4, 0xf1, 0xb4, 0x53, 0xbd, 0xdf, 0x5c, 0x7f, 0x52, 0xb2, 0x72, 0x50, 0xd1,
-	0x3e, 0xf2, 0x30, 0x26, 0x4b, 0x56, 0xa1, 0xce, 0xf8, 0x3d, 0xb4, 0x5f, 0x86, 0x7e, 0x30, 0x96,
-	0xf8, 0x1c, 0xb1, 0x64, 0x34, 0xcd, 0x8c, 0x61, 0xd7, 0x4d, 0xb2, 0x4c, 0x57, 0x08, 0xd6, 0x74,
-	0x2a, 0x5e, 0x14, 0xb2, 0xb8, 0xe1, 0x1a, 0x44, 0x9f, 0x51, 0x13, 0x4a, 0x7e, 0x94, 0x54, 0x2d,
-	0x4f, 0xf8, 0x4b, 0x11, 0x1a, 0xea, 0x09, 0x24, 0xe4, 0x80, 0xba, 0x82, 0x7a, 0xf7, 0xc4, 0xbc,
-	0x89, 0xa3, 0xb4, 0x05, 0xc7, 0xbe, 0x64, 0x89, 0x6b, 0xae, 0x55, 0xb0, 0x9a, 0x0e, 0xa6, 0x72,
-	0x12, 0xad, 0xc6, 0xef, 0xc0, 0xd4, 0x4d, 0x20, 0xc9, 0xbf, 0xa5, 0x77, 0x67, 0xab, 0x77, 0x4c,
-	0xc5, 0x55, 0x73, 0x95, 0xf2, 0xcd, 0xb5, 0x51, 0x4a, 0x56, 0xae, 0xb9, 0x2a, 0x17, 0x3f, 0x01,
-	0x8b, 0x50, 0xc7, 0xbb, 0x53, 0xfc, 0xdd, 0x83, 0x3a, 0xfc, 0xbd, 0x04, 0x7b, 0x17, 0xce, 0x32,
-	0x70, 0x67, 0x19, 0xf3, 0xf7, 0x24, 0xe6, 0x7f, 0x68, 0xf0, 0xf4, 0x6a, 0x8e, 0x99, 0x75, 0xa5,
-	0xc2, 0xe2, 0x85, 0xee, 0x52, 0xb5, 0x98, 0x2e, 0xdc, 0xd0, 0x85, 0xaf, 0xe9, 0xf2, 0xbc, 0x98,
-	0xb2, 0xd8, 0x1c, 0x2f, 0xb2, 0xc5, 0xb9, 0x2a, 0x2a, 0x70, 0xa9, 0xbd, 0x23, 0x4d, 0x0d, 0x92,
-	0xc9, 0xe8, 0x08, 0x0c, 0x21, 0x22, 0xbb, 0xac, 0x71, 0xda, 0x1a, 0xe7, 0x46, 0x41, 0xdd, 0xc9,
-	0xe4, 0x8a, 0x28, 0x27, 0xc5, 0x1c, 0xa7, 0xce, 0xdc, 0x86, 0xb8, 0xf1, 0xd4, 0xb9, 0xf3, 0x18,
-	0x0c, 0x69, 0x57, 0x29, 0x1c, 0xcf, 0x63, 0x9a, 0xd8, 0x98, 0xa8, 0x4c, 0xce, 0xa6, 0xae, 0xb4,
-	0x9a, 0xba, 0xfe, 0x57, 0x03, 0xaa, 0x57, 0xc9, 0xa2, 0x44, 0x7d, 0xa8, 0xa6, 0x83, 0x8e, 0xda,
-	0x1a, 0xc2, 0xc6, 0xb2, 0xec, 0x6c, 0x10, 0x88, 0x0b, 0xa8, 0x07, 0xa6, 0xda, 0x2b, 0xc8, 0xd2,
-	0x96, 0xdc, 0x8a, 0xe9, 0xb4, 0xd6, 0x22, 0xc4, 0x9b, 0x43, 0x5e, 0x38, 0x02, 0x98, 0x06, 0x2c,
-	0x4d, 0x03, 0x71, 0x40, 0xb5, 0x2c, 0xb6, 0x04, 0x7f, 0x0e, 0xbb, 0xf9, 0xf5, 0x81, 0x62, 0x5e,
-	0xb6, 0x6c, 0x94, 0x2d, 0x77, 0x9f, 0x42, 0x3d, 0xb7, 0x01, 0xd0, 0x3f, 0xda, 0xe1, 0xf7, 0x9d,
-	0xd0, 0x69, 0x68, 0x43, 0xaa, 0x95, 0x17, 0x4f, 0xa0, 0xb1, 0x36, 0xc2, 0xe8, 0x5f, 0xed, 0xb1,
-	0x6d, 0xac, 0x3b, 0x28, 0x1b, 0x8e, 0x6c, 0x20, 0x71, 0xe1, 0xb8, 0x28, 0x81, 0xd7, 0xb2, 0x3e,
-	0x46, 0x7f, 0x25, 0x44, 0xac, 0xf7, 0x75, 0xa7, 0xbd, 0xed, 0x91, 0x71, 0xe1, 0x43, 0x59, 0xff,
-	0x5e, 0x3d, 0xfa, 0x15, 0x00, 0x00, 0xff, 0xff, 0x31, 0x18, 0xb6, 0x50, 0xd0, 0x06, 0x00, 0x00,
+	// 1150 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0x9c, 0x57, 0x6f, 0x6f, 0xe3, 0x44,
+	0x13, 0x8f, 0xe3, 0xfc, 0x9d, 0x24, 0xbd, 0x74, 0x9b, 0xe7, 0xc1, 0x44, 0x3a, 0x14, 0xad, 0x90,
+	0x08, 0xd5, 0xd1, 0x96, 0x20, 0x7a, 0xe2, 0x84, 0x40, 0xb9, 0x5c, 0xe8, 0x15, 0x4a, 0xa9, 0xb6,
+	0x09, 0xf0, 0x76, 0xb1, 0xb7, 0x8d, 0xa9, 0x63, 0x9b, 0xf5, 0xe6, 0x50, 0x78, 0xc5, 0xc7, 0xe0,
+	0x35, 0x9f, 0x81, 0x0f, 0xc0, 0x2b, 0xbe, 0x03, 0x5f, 0x06, 0xd0, 0xae, 0xbd, 0x8e, 0x9d, 0xf8,
+	0x50, 0x4b, 0x5f, 0x44, 0x3b, 0xe3, 0xd9, 0x99, 0xd9, 0xdf, 0x6f, 0x66, 0x67, 0x0b, 0x8f, 0x9d,
+	0x9b, 0x28, 0x3a, 0x96, 0x3f, 0xe1, 0x31, 0x0d, 0xdd, 0xe3, 0xd0, 0xa3, 0xe2, 0x26, 0xe0, 0xcb,
+	0xa3, 0x90, 0x07, 0x22, 0x40, 0x26, 0x0d, 0x5d, 0x3c, 0x86, 0x47, 0x84, 0xdd, 0xba, 0x91, 0x60,
+	0x9c, 0xb0, 0x1f, 0x56, 0x2c, 0x12, 0xa8, 0x07, 0x55, 0xb6, 0xa4, 0xae, 0x67, 0x19, 0x03, 0x63,
+	0xd8, 0x24, 0xb1, 0x80, 0x2c, 0xa8, 0xf3, 0xd8, 0xc0, 0x2a, 0x2b, 0xbd, 0x16, 0xf1, 0x6f, 0x06,
+	0x34, 0xa7, 0x9c, 0x07, 0x7c, 0x12, 0x38, 0x0c, 0xbd, 0x03, 0x15, 0x3b, 0x70, 0x98, 0xda, 0xbc,
+	0x37, 0x3a, 0x38, 0xa2, 0xa1, 0x7b, 0x94, 0x7e, 0x3d, 0x92, 0x3f, 0x44, 0x19, 0x48, 0x87, 0x4b,
+	0x16, 0x45, 0xf4, 0x96, 0x69, 0x87, 0x89, 0x88, 0x1d, 0xa8, 0x28, 0x57, 0x2d, 0xa8, 0x5f, 0xcf,
+	0x27, 0x93, 0xe9, 0xf5, 0x75, 0xb7, 0x84, 0x00, 0x6a, 0xe7, 0x97, 0x5f, 0x8f, 0xc9, 0x59, 0xd7,
+	0x90, 0x1f, 0x9e, 0x8f, 0x5f, 0x8c, 0xe7, 0xb3, 0x97, 0xdd, 0xb2, 0x14, 0xbe, 0x19, 0x93, 0xcb,
+	0xf3, 0xcb, 0xb3, 0xae, 0x89, 0x0e, 0xa4, 0xd5, 0x6c, 0x4a, 0x48, 0xf7, 0x6f, 0xfd, 0x67, 0xa0,
+	0x1e, 0xd4, 0x67, 0xe7, 0x5f, 0x4e, 0xbf, 0x9a, 0xcf, 0xba, 0x7f, 0xa5, 0x5a, 0xfc, 0x11, 0xb4,
+	0xc6, 0x2b, 0xb1, 0xf8, 0xf7, 0x53, 0xf7, 0xa0, 0x2a, 0x82, 0x3b, 0xe6, 0x27, 0x29, 0xc6, 0x02,
+	0x3e, 0x81, 0x3d, 0x0d, 0x1a, 0x73, 0xe6, 0x11, 0xe3, 0xe8, 0x2d, 0x00, 0xdb, 0x73, 0x99, 0x2f,
+	0x26, 0x8c, 0x8b, 0xc4, 0x45, 0x46, 0x83, 0x87, 0xd0, 0x26, 0xcc, 0x67, 0x3f, 0xea, 0x68, 0x19,
+	0x34, 0x8d, 0x3c, 0x9a, 0x75, 0xa8, 0x4e, 0x97, 0xa1, 0x58, 0xe3, 0x3f, 0x0d, 0x38, 0xb8, 0x0a,
+	0x22, 0x31, 0x09, 0x7c, 0xc1, 0xa9, 0x2d, 0xf4, 0x56, 0x04, 0x95, 0x05, 0x8d, 0x16, 0x6a, 0x5f,
+	0x9b, 0xa8, 0x35, 0xea, 0x43, 0xe3, 0xc6, 0xf5, 0x98, 0x4f, 0x97, 0x1a, 0xcc, 0x54, 0x46, 0xff,
+	0x87, 0x5a, 0xe4, 0xde, 0xfa, 0x8c, 0x5b, 0xe6, 0xc0, 0x1c, 0x36, 0x49, 0x22, 0xc9, 0x14, 0xec,
+	0x60, 0xb9, 0x64, 0xbe, 0xb0, 0x2a, 0x71, 0x0a, 0x89, 0x88, 0x9e, 0xc0, 0x7e, 0x24, 0x83, 0xf9,
+	0x36, 0x3b, 0x63, 0x3e, 0xe3, 0x54, 0x04, 0xdc, 0xaa, 0x2a, 0x9b, 0xdd, 0x0f, 0x32, 0xb6, 0xc3,
+	0xa8, 0xe3, 0xb9, 0x3e, 0xb3, 0x6a, 0x03, 0x63, 0x68, 0x92, 0x54, 0x96, 0xb1, 0x17, 0x41, 0x24,
+	0x98, 0x63, 0xd5, 0x07, 0xc6, 0xb0, 0x41, 0x12, 0x09, 0x0f, 0x01, 0x9d, 0xb1, 0xa2, 0x93, 0xad,
+	0x56, 0xae, 0x93, 0x20, 0xa2, 0xd6, 0xf8, 0x02, 0x1a, 0xda, 0x0c, 0x3d, 0x81, 0x26, 0xd3, 0x95,
+	0xa4, 0x8c, 0x5a, 0xa3, 0xbd, 0x7c, 0x7d, 0x91, 0x8d, 0x81, 0xf4, 0xf6, 0x7d, 0x14, 0xc4, 0xcc,
+	0xb5, 0x89, 0x5a, 0xe3, 0xa7, 0xd0, 0x79, 0x11, 0xd8, 0x2b, 0x79, 0xca, 0xc9, 0x62, 0xe5, 0xdf,
+	0x15, 0x82, 0x89, 0xa0, 0xe2, 0x50, 0x41, 0xf5, 0x46, 0xb9, 0xc6, 0x3f, 0x41, 0xef, 0xf3, 0xc0,
+	0xf5, 0xaf, 0xdd, 0x5b, 0x9f, 0x8a, 0x15, 0x67, 0x3a, 0x65, 0x0c, 0x6d, 0x3b, 0x49, 0x6f, 0xbe,
+	0x49, 0x3d, 0xa7, 0x93, 0xfe, 0xc2, 0x80, 0xc7, 0x6d, 0xd3, 0x21, 0x6a, 0x8d, 0xf6, 0xa0, 0xec,
+	0x86, 0x09, 0x21, 0x65, 0x37, 0x94, 0x20, 0x32, 0xdf, 0x09, 0x03, 0x57, 0xb1, 0x21, 0xb5, 0xa9,
+	0x8c, 0x7f, 0x36, 0xa0, 0x23, 0x8b, 0x6c, 0x12, 0xf8, 0x3e, 0xb3, 0x05, 0x73, 0x1e, 0x08, 0xc4,
+	0x76, 0x8e, 0xe5, 0x82, 0x1c, 0x1f, 0x43, 0x65, 0x15, 0xa9, 0x12, 0x91, 0xce, 0x9a, 0xca, 0x99,
+	0x8c, 0x49, 0x94, 0x1a, 0xff, 0x62, 0x40, 0x65, 0x1e, 0xc5, 0x45, 0x73, 0xc7, 0xd6, 0x2f, 0x37,
+	0x90, 0x69, 0x71, 0xd3, 0x3f, 0xe5, 0x6c, 0xff, 0x6c, 0x9f, 0x53, 0x63, 0x51, 0xc9, 0x60, 0x31,
+	0x80, 0x96, 0xcd, 0xb8, 0x70, 0x6f, 0x5c, 0x9b, 0x0a, 0xa6, 0x0a, 0xad, 0x4d, 0xb2, 0xaa, 0x1c,
+	0x3a, 0xb5, 0x2d, 0x74, 0x4e, 0xa1, 0x4b, 0x18, 0x75, 0xd6, 0x92, 0x9a, 0x07, 0xb0, 0x82, 0x7f,
+	0x35, 0xe1, 0xd1, 0x05, 0x5d, 0xf9, 0xf6, 0x22, 0x25, 0xf5, 0x81, 0xb8, 0xbe, 0x0d, 0x9d, 0x48,
+	0x6f, 0xcd, 0x00, 0x9b, 0x57, 0xca, 0x5c, 0x9c, 0xa4, 0xe4, 0x14, 0x6c, 0xa6, 0x3a, 0x5e, 0x4e,
+	0x97, 0x45, 0x55, 0x92, 0x9f, 0x41, 0xb5, 0x0f, 0x0d, 0xdd, 0x71, 0x56, 0x75, 0x60, 0x0e, 0x3b,
+	0x24, 0x95, 0xd1, 0x21, 0x98, 0x42, 0x84, 0xaa, 0xe7, 0x5a, 0x23, 0x4b, 0xe5, 0xb9, 0x75, 0xa0,
+	0xa3, 0xd9, 0xec, 0x8a, 0x48, 0xa3, 0xe2, 0x96, 0xae, 0xdf, 0xa7, 0xa5, 0x1b, 0x5b, 0x2d, 0xbd,
+	0xc5, 0x56, 0x53, 0xe5, 0x9b, 0x63, 0x0b, 0x41, 0x25, 0x62, 0xd4, 0xb3, 0x20, 0xee, 0x1f, 0xb9,
+	0xee, 0x7f, 0x08, 0xe6, 0x6c, 0x76, 0x25, 0x1d, 0x53, 0xc7, 0xe1, 0xaa, 0x04, 0x62, 0x52, 0x52,
+	0x39, 0x6d, 0xc5, 0xf2, 0xa6, 0x15, 0xf1, 0x95, 0xbc, 0x68, 0x5f, 0x05, 0x36, 0x15, 0x6e, 0xe0,
+	0x5f, 0xb8, 0xd1, 0x43, 0xef, 0x80, 0x2e, 0x98, 0x36, 0xf7, 0x12, 0x97, 0x72, 0x89, 0x7f, 0x37,
+	0xa0, 0x31, 0x7d, 0xe5, 0x3a, 0x0a, 0xc1, 0x1d, 0x06, 0x8d, 0x22, 0x06, 0xa5, 0x15, 0xf3, 0x1d,
+	0xc6, 0xbf, 0x48, 0x38, 0x8a, 0xdd, 0xe5, 0x95, 0xe8, 0x10, 0xba, 0x9c, 0xd9, 0x6e, 0x28, 0xaf,
+	0x7c, 0x6d, 0x18, 0x73, 0xbd, 0xa3, 0x97, 0x7c, 0x87, 0x74, 0xed, 0x05, 0xd4, 0x51, 0x8d, 0xd0,
+	0x26, 0x5a, 0xdc, 0xa9, 0xdc, 0x6a, 0x41, 0xe5, 0x7e, 0x2b, 0x67, 0x89, 0x47, 0xd7, 0x0f, 0xb9,
+	0x83, 0xee, 0x55, 0xab, 0xf8, 0x63, 0xe8, 0x7d, 0xc6, 0x84, 0xbd, 0xd0, 0x00, 0xe9, 0x08, 0xf7,
+	0xc2, 0x09, 0xdf, 0x42, 0x5b, 0x6f, 0xfc, 0x0f, 0x54, 0xbd, 0x0b, 0x0d, 0x96, 0xec, 0xb6, 0xca,
+	0x03, 0x73, 0xd8, 0x1a, 0x75, 0x62, 0x63, 0x9d, 0x4b, 0xfa, 0x79, 0xf4, 0x47, 0x0d, 0x1a, 0x57,
+	0xc9, 0x5b, 0x06, 0x8d, 0xa0, 0xa1, 0x67, 0x31, 0xea, 0xa9, 0x1d, 0x5b, 0xef, 0x99, 0xfe, 0x56,
+	0x50, 0x5c, 0x42, 0xc7, 0x50, 0x91, 0xa3, 0x1f, 0x75, 0xd5, 0x97, 0xcc, 0x2b, 0xa0, 0x7f, 0x90,
+	0xf3, 0x10, 0x0f, 0x77, 0x5c, 0x42, 0x87, 0x00, 0x73, 0x9f, 0xeb, 0x30, 0x10, 0x3b, 0x94, 0x53,
+	0xba, 0xc0, 0xf9, 0xfb, 0x50, 0x55, 0xa3, 0x1e, 0xed, 0x27, 0xbe, 0x36, 0x63, 0xff, 0x75, 0xee,
+	0x9f, 0x41, 0x3b, 0x3b, 0xe9, 0x51, 0xdc, 0xcc, 0x05, 0xc3, 0xbf, 0x20, 0xdc, 0x53, 0x68, 0x65,
+	0x46, 0x29, 0x7a, 0x43, 0x19, 0xec, 0x0e, 0xd7, 0x7e, 0x8c, 0xa6, 0xd6, 0xe2, 0x12, 0x7a, 0x0e,
+	0x9d, 0xdc, 0x48, 0x43, 0x6f, 0x2a, 0x8b, 0xa2, 0x31, 0xd7, 0x47, 0xe9, 0x40, 0x48, 0x87, 0x10,
+	0x2e, 0x9d, 0x18, 0xe8, 0x19, 0x34, 0xd3, 0xcb, 0x17, 0xfd, 0x2f, 0x39, 0x5c, 0xfe, 0x32, 0xee,
+	0xf7, 0x8a, 0x6e, 0x26, 0x5c, 0x42, 0xa7, 0xb0, 0x7f, 0xc6, 0xc4, 0x56, 0x7b, 0x67, 0xa1, 0xd5,
+	0x60, 0x65, 0x0d, 0x14, 0x79, 0x0a, 0xac, 0xb4, 0x89, 0xf3, 0x65, 0x52, 0x80, 0xd0, 0xa7, 0xd0,
+	0xc9, 0x55, 0x75, 0x72, 0xd0, 0xa2, 0x4a, 0xef, 0xef, 0xe7, 0x9c, 0x25, 0x11, 0xdf, 0x93, 0x8c,
+	0x7a, 0x74, 0x9d, 0x32, 0xba, 0x69, 0xbe, 0x7e, 0x3e, 0xba, 0x02, 0xe5, 0x04, 0x3a, 0xca, 0xe4,
+	0xfe, 0x19, 0x9e, 0xc6, 0x47, 0xd2, 0x4f, 0x13, 0x14, 0xc3, 0x9d, 0x7b, 0xa9, 0xec, 0xee, 0x1a,
+	0x1a, 0xe8, 0x13, 0xc5, 0x7d, 0xba, 0xed, 0xb5, 0xdc, 0x17, 0xf8, 0x93, 0x99, 0x7e, 0x57, 0x53,
+	0xff, 0x08, 0x7c, 0xf0, 0xcf, 0x00, 0x74, 0x14, 0x1e, 0xd4, 0x29, 0x0c, 0x00, 0x00,
 }
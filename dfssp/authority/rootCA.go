@@ -2,7 +2,7 @@
 package authority
 
 import (
-	"crypto/rsa"
+	"crypto"
 	"crypto/x509"
 	"io/ioutil"
 	"os"
@@ -14,7 +14,7 @@ import (
 
 // PlatformID contains platform private key and root certificate
 type PlatformID struct {
-	Pkey   *rsa.PrivateKey
+	Pkey   crypto.Signer
 	RootCA *x509.Certificate
 }
 
@@ -22,9 +22,13 @@ type PlatformID struct {
 // If ca and rKey are not nil, they will be used as the root certificate and root private key instead of creating a ones.
 // The files are saved at the specified path by viper.
 // The returned `hash` is the SHA-512 hash of the generated certificate.
-func Initialize(v *viper.Viper, ca *x509.Certificate, rKey *rsa.PrivateKey) (hash []byte, err error) {
-	// Generate the private key.
-	key, err := auth.GeneratePrivateKey(v.GetInt("key_size"))
+func Initialize(v *viper.Viper, ca *x509.Certificate, rKey crypto.Signer) (hash []byte, err error) {
+	// Generate the private key, RSA being the default type.
+	keyType := v.GetString("key_type")
+	if keyType == "" {
+		keyType = auth.KeyTypeRSA
+	}
+	key, err := auth.GenerateKey(keyType, v.GetInt("key_size"))
 
 	if err != nil {
 		return nil, err
@@ -71,7 +75,7 @@ func Initialize(v *viper.Viper, ca *x509.Certificate, rKey *rsa.PrivateKey) (has
 	return auth.GetCertificateHash(rawCert), ioutil.WriteFile(certPath, cert, 0600)
 }
 
-// Start fetches the platform's private rsa key and root certificate, and create a PlatformID accordingly.
+// Start fetches the platform's private key and root certificate, and create a PlatformID accordingly.
 //
 // The specified path should not end by a separator.
 //
@@ -80,7 +84,7 @@ func Start(path string) (*PlatformID, error) {
 	keyPath := filepath.Join(path, viper.GetString("pkey_filename"))
 	certPath := filepath.Join(path, viper.GetString("ca_filename"))
 
-	// Recover the private rsa key from file.
+	// Recover the private key from file.
 	keyBytes, err := ioutil.ReadFile(keyPath)
 	if err != nil {
 		return nil, err
@@ -21,8 +21,9 @@ var initCmd = &cobra.Command{
 		_ = viper.BindPFlag("organization", cmd.Flags().Lookup("org"))
 		_ = viper.BindPFlag("unit", cmd.Flags().Lookup("unit"))
 		_ = viper.BindPFlag("key_size", cmd.Flags().Lookup("key"))
+		_ = viper.BindPFlag("key_type", cmd.Flags().Lookup("type"))
 
-		_, err := authority.Initialize(common.SubViper("key_size", "validity", "country", "organization", "unit", "cn", "path"), nil, nil)
+		_, err := authority.Initialize(common.SubViper("key_type", "key_size", "validity", "country", "organization", "unit", "cn", "path"), nil, nil)
 		if err != nil {
 			fmt.Fprintln(os.Stderr, "An error occured during the initialization operation:", err)
 			os.Exit(1)
@@ -2,7 +2,10 @@
 package cmd
 
 import (
+	"strings"
+
 	"dfss"
+	"dfss/auth"
 	dapi "dfss/dfssd/api"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
@@ -39,6 +42,7 @@ func init() {
 	initCmd.Flags().String("org", "DFSS", "organization for the root certificate")
 	initCmd.Flags().String("unit", "INSA Rennes", "organizational unit for the root certificate")
 	initCmd.Flags().IntP("key", "k", 2048, "encoding size for the private key of the platform")
+	initCmd.Flags().String("type", auth.KeyTypeRSA, "type of the private key of the platform: "+strings.Join(auth.KeyTypes, ", "))
 
 	ttpCmd.Flags().String("cn", "ttp", "common name for the ttp certificate")
 	ttpCmd.Flags().IntP("validity", "c", 365, "validity duration for the ttp certificate (days)")
@@ -46,6 +50,7 @@ func init() {
 	ttpCmd.Flags().String("org", "DFSS", "organization for the ttp certificate")
 	ttpCmd.Flags().String("unit", "INSA Rennes", "organizational unit for the ttp certificate")
 	ttpCmd.Flags().IntP("key", "k", 2048, "encoding size for the private key of the ttp")
+	ttpCmd.Flags().String("type", auth.KeyTypeRSA, "type of the private key of the ttp: "+strings.Join(auth.KeyTypes, ", "))
 	ttpCmd.Flags().StringP("ttps", "t", "ttps", "file containing available TTPs list")
 	ttpCmd.Flags().StringP("addr", "a", "localhost:9020", "address of the ttp to be transmitted to signers")
 
@@ -54,6 +59,10 @@ func init() {
 	startCmd.Flags().StringP("port", "p", "9000", "port to bind for listening")
 	startCmd.Flags().String("db", "mongodb://localhost/dfss", "server url in standard MongoDB format for accessing database")
 	startCmd.Flags().StringP("ttps", "t", "", "file containing available TTPs list, disabled by default")
+	startCmd.Flags().String("storage", "disk", "storage of the documents hosted for signers: disk, gridfs, or empty to disable hosting")
+	startCmd.Flags().String("documents", "", "directory of the documents hosted on disk, \"documents\" in the platform path by default")
+	startCmd.Flags().Int("maxdocument", 32, "maximum size of a hosted document (MiB)")
+	startCmd.Flags().Int("expiration", 24, "duration after which an uploaded document is deleted if no contract refers to it (hours)")
 
 	// Bind viper to flags
 	_ = viper.BindPFlag("verbose", RootCmd.PersistentFlags().Lookup("verbose"))
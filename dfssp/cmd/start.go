@@ -21,6 +21,10 @@ var startCmd = &cobra.Command{
 		_ = viper.BindPFlag("port", cmd.Flags().Lookup("port"))
 		_ = viper.BindPFlag("validity", cmd.Flags().Lookup("validity"))
 		_ = viper.BindPFlag("ttps", cmd.Flags().Lookup("ttps"))
+		_ = viper.BindPFlag("storage", cmd.Flags().Lookup("storage"))
+		_ = viper.BindPFlag("documents", cmd.Flags().Lookup("documents"))
+		_ = viper.BindPFlag("max_document_size", cmd.Flags().Lookup("maxdocument"))
+		_ = viper.BindPFlag("document_expiration", cmd.Flags().Lookup("expiration"))
 
 		address := viper.GetString("address")
 		port := viper.GetString("port")
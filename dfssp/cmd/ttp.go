@@ -35,6 +35,7 @@ You can setup as many TTPs as you want, but beware certificate and private key a
 		_ = viper.BindPFlag("organization", cmd.Flags().Lookup("org"))
 		_ = viper.BindPFlag("unit", cmd.Flags().Lookup("unit"))
 		_ = viper.BindPFlag("key_size", cmd.Flags().Lookup("key"))
+		_ = viper.BindPFlag("key_type", cmd.Flags().Lookup("type"))
 		_ = viper.BindPFlag("ttps", cmd.Flags().Lookup("ttps"))
 		_ = viper.BindPFlag("ttp_addr", cmd.Flags().Lookup("addr"))
 
@@ -46,7 +47,7 @@ You can setup as many TTPs as you want, but beware certificate and private key a
 			os.Exit(1)
 		}
 		ttpPath := filepath.Join(path, "ttp")
-		v := common.SubViper("key_size", "validity", "country", "organization", "unit", "cn")
+		v := common.SubViper("key_type", "key_size", "validity", "country", "organization", "unit", "cn")
 		v.Set("path", ttpPath)
 		hash, err := authority.Initialize(v, pid.RootCA, pid.Pkey)
 		if err != nil {
@@ -0,0 +1,65 @@
+package common
+
+import (
+	"sync"
+)
+
+// RelayMap is a synchronisation tool forwarding messages to the goroutine listening on a specific key.
+// Contrary to a WaitingGroupMap, messages are not broadcasted nor kept: a message sent to a key without listener is dropped.
+//
+// To avoid memory leaks, always call Unlisten when leaving a goroutine.
+type RelayMap struct {
+	data  map[string]chan interface{}
+	mutex sync.Mutex
+}
+
+// NewRelayMap returns a ready to use RelayMap.
+func NewRelayMap() *RelayMap {
+	return &RelayMap{
+		data: make(map[string]chan interface{}),
+	}
+}
+
+// Listen returns the channel of the messages sent to the key.
+// A previous listener of the same key is replaced, and its channel is closed.
+func (r *RelayMap) Listen(key string) chan interface{} {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if old, ok := r.data[key]; ok {
+		close(old)
+	}
+	listen := make(chan interface{}, 100)
+	r.data[key] = listen
+	return listen
+}
+
+// Unlisten removes the given chan from the key, if it is still its listener.
+func (r *RelayMap) Unlisten(key string, listen chan interface{}) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if current, ok := r.data[key]; ok && current == listen {
+		close(listen)
+		delete(r.data, key)
+	}
+}
+
+// Send forwards a message to the listener of the key, without blocking.
+// It returns false if there is no listener, or if the listener is too slow to keep the message.
+func (r *RelayMap) Send(key string, value interface{}) bool {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	listen, ok := r.data[key]
+	if !ok {
+		return false
+	}
+
+	select {
+	case listen <- value:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,36 @@
+package common
+
+import (
+	"testing"
+)
+
+func TestRelayMap(t *testing.T) {
+	r := NewRelayMap()
+
+	if r.Send("A", 1) {
+		t.Fatal("Message sent without listener")
+	}
+
+	listen := r.Listen("A")
+	if !r.Send("A", 1) || r.Send("B", 2) {
+		t.Fatal("Message sent to the wrong listener")
+	}
+	if v := <-listen; v != 1 {
+		t.Fatal("Unexpected message", v)
+	}
+
+	// A new listener replaces the old one
+	other := r.Listen("A")
+	if _, ok := <-listen; ok {
+		t.Fatal("Old listener not closed")
+	}
+	r.Unlisten("A", listen)
+	if !r.Send("A", 3) || <-other != 3 {
+		t.Fatal("New listener removed by the old one")
+	}
+
+	r.Unlisten("A", other)
+	if r.Send("A", 4) {
+		t.Fatal("Message sent after unlisten")
+	}
+}
@@ -2,6 +2,7 @@ package contract_test // Using another package to avoid import cycles
 
 import (
 	"fmt"
+	"io/ioutil"
 	"log"
 	"os"
 	"path/filepath"
@@ -19,6 +20,7 @@ import (
 var collection *mgdb.MongoCollection
 var manager *mgdb.MongoManager
 var dbURI string
+var documentsDir string
 
 var repository *entities.ContractRepository
 
@@ -41,12 +43,16 @@ func TestMain(m *testing.M) {
 	collection = manager.Get("contracts")
 	repository = entities.NewContractRepository(collection)
 
+	documentsDir, _ = ioutil.TempDir("", "dfssp_documents")
+
 	// Start platform server
 	keyPath := filepath.Join(os.Getenv("GOPATH"), "src", "dfss", "dfssp", "testdata")
 	viper.Set("path", keyPath)
 	viper.Set("dbURI", dbURI)
 	viper.Set("validity", 365)
 	viper.Set("verbose", true)
+	viper.Set("storage", "disk")
+	viper.Set("documents", documentsDir)
 
 	srv := server.GetServer()
 	go func() { log.Fatal(net.Listen("localhost:9090", srv)) }()
@@ -56,6 +62,7 @@ func TestMain(m *testing.M) {
 
 	dropDataset()
 	manager.Close()
+	_ = os.RemoveAll(documentsDir)
 	os.Exit(code)
 }
 
@@ -2,6 +2,7 @@
 package contract
 
 import (
+	"crypto"
 	"crypto/sha512"
 	"log"
 	"strings"
@@ -16,6 +17,7 @@ import (
 
 // Builder contains internal information to create a new contract.
 type Builder struct {
+	key            crypto.Signer
 	m              *mgdb.MongoManager
 	in             *api.PostContractRequest
 	signers        []entities.User
@@ -24,11 +26,13 @@ type Builder struct {
 }
 
 // NewContractBuilder creates a new builder from current context.
+// The private key of the platform seals the DFSS files sent to the signers.
 // Call Execute() on the builder to get a result from it.
-func NewContractBuilder(m *mgdb.MongoManager, in *api.PostContractRequest) *Builder {
+func NewContractBuilder(key crypto.Signer, m *mgdb.MongoManager, in *api.PostContractRequest) *Builder {
 	return &Builder{
-		m:  m,
-		in: in,
+		key: key,
+		m:   m,
+		in:  in,
 	}
 }
 
@@ -75,6 +79,14 @@ func (c *Builder) checkInput() *api.ErrorCode {
 		return &api.ErrorCode{Code: api.ErrorCode_INVARG, Message: "Expecting a valid sha512 hash"}
 	}
 
+	if _, err := GetSequenceGenerator(c.in.SequenceGenerator); err != nil {
+		return &api.ErrorCode{Code: api.ErrorCode_INVARG, Message: err.Error()}
+	}
+
+	if c.in.Deadline != 0 && time.Unix(c.in.Deadline, 0).Before(time.Now()) {
+		return &api.ErrorCode{Code: api.ErrorCode_INVARG, Message: "Expecting a deadline in the future"}
+	}
+
 	return nil
 }
 
@@ -128,10 +140,14 @@ func (c *Builder) addContract() error {
 	}
 
 	contract.Comment = c.in.Comment
+	contract.SequenceGenerator = c.in.SequenceGenerator
 	contract.Ready = len(c.missingSigners) == 0
+	if c.in.Deadline != 0 {
+		contract.Deadline = time.Unix(c.in.Deadline, 0).UTC()
+	}
 	contract.File.Name = c.in.Filename
 	contract.File.Hash = c.in.Hash
-	contract.File.Hosted = false
+	contract.File.Hosted = c.in.Hosted
 
 	_, err := c.m.Get("contracts").Insert(contract)
 	c.Contract = contract
@@ -158,7 +174,7 @@ func (c *Builder) SendNewContractMail() {
 		return
 	}
 
-	file, err := GetJSON(c.Contract)
+	file, err := GetJSON(c.Contract, c.key)
 	if err != nil {
 		log.Println(err)
 		return
@@ -48,6 +48,7 @@ func createDataset() {
 func dropDataset() {
 	_ = manager.Get("users").Drop()
 	_ = manager.Get("contracts").Drop()
+	_ = manager.Get("evidence").Drop()
 }
 
 func clientTest(t *testing.T) api.PlatformClient {
@@ -117,6 +118,38 @@ func TestAddContract(t *testing.T) {
 	assert.Equal(t, user2.Email, contracts[0].Signers[1].Email)
 }
 
+func TestAddContractDeadline(t *testing.T) {
+	dropDataset()
+	createDataset()
+
+	client := clientTest(t)
+	request := &api.PostContractRequest{
+		Hash:     defaultHash[:],
+		Filename: "ContractFilename",
+		Signer:   []string{user1.Email, user2.Email},
+		Deadline: time.Now().Add(-time.Minute).Unix(),
+	}
+	errorCode, err := client.PostContract(context.Background(), request)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, api.ErrorCode_INVARG, errorCode.Code)
+
+	deadline := time.Now().Add(time.Hour).Unix()
+	request.Deadline = deadline
+	errorCode, err = client.PostContract(context.Background(), request)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, api.ErrorCode_SUCCESS, errorCode.Code)
+
+	var contracts []entities.Contract
+	err = manager.Get("contracts").FindAll(nil, &contracts)
+	if err != nil {
+		t.Fatal("Unexpected db error:", err)
+	}
+
+	assert.Equal(t, 1, len(contracts))
+	assert.True(t, contracts[0].IsAsync())
+	assert.Equal(t, deadline, contracts[0].Deadline.Unix())
+}
+
 func TestAddContractMissingUser(t *testing.T) {
 	dropDataset()
 	createDataset()
@@ -243,3 +276,27 @@ func TestAddContractBadHash(t *testing.T) {
 
 	assert.Equal(t, 0, len(contracts))
 }
+
+func TestAddContractBadSequenceGenerator(t *testing.T) {
+	dropDataset()
+	createDataset()
+
+	client := clientTest(t)
+	errorCode, err := client.PostContract(context.Background(), &api.PostContractRequest{
+		Hash:              defaultHash[:],
+		Filename:          "ContractFilename",
+		Signer:            []string{user1.Email},
+		SequenceGenerator: "unknown",
+	})
+	assert.Equal(t, nil, err)
+	assert.Equal(t, api.ErrorCode_INVARG, errorCode.Code)
+
+	// Check database content
+	var contracts []entities.Contract
+	err = manager.Get("contracts").FindAll(nil, &contracts)
+	if err != nil {
+		t.Fatal("Unexpected db error:", err)
+	}
+
+	assert.Equal(t, 0, len(contracts))
+}
@@ -0,0 +1,171 @@
+package contract
+
+import (
+	"crypto/sha512"
+	"errors"
+	"io"
+	"log"
+	"time"
+
+	"dfss/dfssp/api"
+	"dfss/dfssp/entities"
+	"dfss/dfssp/storage"
+	"dfss/mgdb"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// MaxDocumentSize is the maximum size of a document hosted by the platform.
+var MaxDocumentSize int64 = 32 << 20
+
+// DocumentChunkSize is the size of the chunks sent when downloading a hosted document.
+var DocumentChunkSize = 64 << 10
+
+// DocumentExpiration is the duration after which an uploaded document is deleted, if no contract refers to it.
+var DocumentExpiration = 24 * time.Hour
+
+// errDocumentTooLarge is returned when an uploaded document exceeds MaxDocumentSize
+var errDocumentTooLarge = errors.New("document too large")
+
+// chunkReader reads the data of an uploaded document, chunk after chunk, up to MaxDocumentSize.
+type chunkReader struct {
+	stream api.Platform_PostDocumentServer
+	buffer []byte
+	size   int64
+}
+
+func (r *chunkReader) Read(p []byte) (int, error) {
+	for len(r.buffer) == 0 {
+		chunk, err := r.stream.Recv()
+		if err != nil {
+			return 0, err // io.EOF at the end of the upload
+		}
+		r.buffer = chunk.Data
+		r.size += int64(len(chunk.Data))
+		if r.size > MaxDocumentSize {
+			return 0, errDocumentTooLarge
+		}
+	}
+
+	n := copy(p, r.buffer)
+	r.buffer = r.buffer[n:]
+	return n, nil
+}
+
+// PostDocument stores the document uploaded through the stream, once its hash has been checked.
+// The hash is sent in the first chunk, with the beginning of the document.
+func PostDocument(store storage.Storage, stream api.Platform_PostDocumentServer) *api.ErrorCode {
+	if store == nil {
+		return &api.ErrorCode{Code: api.ErrorCode_INVARG, Message: "document hosting is disabled"}
+	}
+
+	first, err := stream.Recv()
+	if err != nil {
+		return &api.ErrorCode{Code: api.ErrorCode_INVARG, Message: "empty upload"}
+	}
+	if len(first.Hash) != sha512.Size {
+		return &api.ErrorCode{Code: api.ErrorCode_INVARG, Message: "Expecting a valid sha512 hash"}
+	}
+	if int64(len(first.Data)) > MaxDocumentSize {
+		return &api.ErrorCode{Code: api.ErrorCode_INVARG, Message: errDocumentTooLarge.Error()}
+	}
+
+	reader := &chunkReader{stream: stream, buffer: first.Data, size: int64(len(first.Data))}
+	err = store.Put(first.Hash, reader)
+	switch err {
+	case nil:
+		return &api.ErrorCode{Code: api.ErrorCode_SUCCESS}
+	case errDocumentTooLarge, storage.ErrHashMismatch:
+		return &api.ErrorCode{Code: api.ErrorCode_INVARG, Message: err.Error()}
+	}
+	log.Println(err)
+	return &api.ErrorCode{Code: api.ErrorCode_INTERR}
+}
+
+// CheckHostedDocument checks that the document of a new hosted contract has been uploaded beforehand.
+// It returns nil if the contract can be created.
+func CheckHostedDocument(store storage.Storage, in *api.PostContractRequest) *api.ErrorCode {
+	if !in.Hosted {
+		return nil
+	}
+	if store == nil {
+		return &api.ErrorCode{Code: api.ErrorCode_INVARG, Message: "document hosting is disabled"}
+	}
+
+	exists, err := store.Exists(in.Hash)
+	if err != nil {
+		return &api.ErrorCode{Code: api.ErrorCode_INVARG, Message: "Expecting a valid sha512 hash"}
+	}
+	if !exists {
+		return &api.ErrorCode{Code: api.ErrorCode_INVARG, Message: "document not uploaded"}
+	}
+	return nil
+}
+
+// ExpireDocuments deletes the documents uploaded more than DocumentExpiration ago, if no contract refers to them.
+// Uploading an existing document again postpones its expiration.
+func ExpireDocuments(db *mgdb.MongoManager, store storage.Storage) error {
+	hashes, err := store.List(time.Now().Add(-DocumentExpiration))
+	if err != nil {
+		return err
+	}
+
+	repository := entities.NewContractRepository(db.Get("contracts"))
+	for _, hash := range hashes {
+		referenced, err := repository.HasDocument(hash)
+		if err != nil {
+			return err
+		}
+		if !referenced {
+			if err = store.Delete(hash); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// GetDocument sends to the stream the hosted document of a contract, if the authenticated user is one of its signers.
+func GetDocument(db *mgdb.MongoManager, store storage.Storage, in *api.GetContractRequest, clientHash []byte, stream api.Platform_GetDocumentServer) error {
+	if !bson.IsObjectIdHex(in.Uuid) || len(clientHash) == 0 {
+		return errors.New("unauthorized document")
+	}
+
+	repository := entities.NewContractRepository(db.Get("contracts"))
+	contract, _ := repository.GetWithSigner(clientHash, bson.ObjectIdHex(in.Uuid))
+	if contract == nil {
+		return errors.New("unauthorized document")
+	}
+	if !contract.File.Hosted || store == nil {
+		return errors.New("document not hosted")
+	}
+
+	r, err := store.Get(contract.File.Hash)
+	if err != nil {
+		log.Println(err)
+		return errors.New("document not available")
+	}
+	defer func() { _ = r.Close() }()
+
+	first := true
+	buffer := make([]byte, DocumentChunkSize)
+	for {
+		n, err := io.ReadFull(r, buffer)
+		if n > 0 || first {
+			chunk := &api.DocumentChunk{Data: append([]byte(nil), buffer[:n]...)}
+			if first {
+				chunk.Hash = contract.File.Hash
+				first = false
+			}
+			if sendErr := stream.Send(chunk); sendErr != nil {
+				return sendErr
+			}
+		}
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			return nil
+		}
+		if err != nil {
+			log.Println(err)
+			return errors.New("document not available")
+		}
+	}
+}
@@ -0,0 +1,168 @@
+package contract_test
+
+import (
+	"bytes"
+	"crypto/sha512"
+	"io"
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"dfss/dfssp/api"
+	"dfss/dfssp/contract"
+	"dfss/dfssp/entities"
+	"dfss/dfssp/storage"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/net/context"
+)
+
+func postDocument(t *testing.T, client api.PlatformClient, hash []byte, chunks ...[]byte) api.ErrorCode_Code {
+	stream, err := client.PostDocument(context.Background())
+	assert.Equal(t, nil, err)
+	for i, data := range chunks {
+		chunk := &api.DocumentChunk{Data: data}
+		if i == 0 {
+			chunk.Hash = hash
+		}
+		if stream.Send(chunk) == io.EOF {
+			break
+		}
+	}
+	errorCode, err := stream.CloseAndRecv()
+	assert.Equal(t, nil, err)
+	return errorCode.Code
+}
+
+func getDocument(client api.PlatformClient, uuid string) ([]byte, []byte, error) {
+	stream, err := client.GetDocument(context.Background(), &api.GetContractRequest{Uuid: uuid})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var hash, data []byte
+	for {
+		chunk, err := stream.Recv()
+		if err == io.EOF {
+			return hash, data, nil
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		if chunk.Hash != nil {
+			hash = chunk.Hash
+		}
+		data = append(data, chunk.Data...)
+	}
+}
+
+func TestPostDocument(t *testing.T) {
+	client := clientTest(t)
+	document := []byte("hosted document")
+	hash := sha512.Sum512(document)
+
+	assert.Equal(t, api.ErrorCode_INVARG, postDocument(t, client, hash[:10], document))
+	assert.Equal(t, api.ErrorCode_INVARG, postDocument(t, client, hash[:], document[:5], []byte("other content")))
+
+	maxSize := contract.MaxDocumentSize
+	contract.MaxDocumentSize = 10
+	assert.Equal(t, api.ErrorCode_INVARG, postDocument(t, client, hash[:], document[:5], document[5:]))
+	contract.MaxDocumentSize = maxSize
+
+	assert.Equal(t, api.ErrorCode_SUCCESS, postDocument(t, client, hash[:], document[:5], document[5:]))
+}
+
+func TestAddHostedContract(t *testing.T) {
+	dropDataset()
+	createDataset()
+	client := clientTest(t)
+
+	missing := sha512.Sum512([]byte("missing document"))
+	errorCode, err := client.PostContract(context.Background(), &api.PostContractRequest{
+		Hash:     missing[:],
+		Filename: "ContractFilename",
+		Signer:   []string{user1.Email, user2.Email},
+		Hosted:   true,
+	})
+	assert.Equal(t, nil, err)
+	assert.Equal(t, api.ErrorCode_INVARG, errorCode.Code)
+
+	assert.Equal(t, 0, manager.Get("contracts").Count())
+}
+
+func TestGetDocument(t *testing.T) {
+	dropDataset()
+	createDataset()
+	client := clientTest(t)
+
+	document := make([]byte, contract.DocumentChunkSize*2+1)
+	for i := range document {
+		document[i] = byte(i)
+	}
+	hash := sha512.Sum512(document)
+	assert.Equal(t, api.ErrorCode_SUCCESS, postDocument(t, client, hash[:], document))
+
+	c := entities.NewContract()
+	c.AddSigner(&user1.ID, user1.Email, user1.CertHash)
+	c.AddSigner(&user2.ID, user2.Email, user2.CertHash)
+	c.File.Name = "ContractFilename"
+	c.File.Hash = hash[:]
+	c.Ready = true
+	_, _ = manager.Get("contracts").Insert(c)
+
+	// Not hosted
+	_, _, err := getDocument(client, c.ID.Hex())
+	assert.NotNil(t, err)
+
+	c.File.Hosted = true
+	_, _ = manager.Get("contracts").UpdateByID(*c)
+
+	fetchedHash, data, err := getDocument(client, c.ID.Hex())
+	assert.Equal(t, nil, err)
+	assert.Equal(t, hash[:], fetchedHash)
+	assert.Equal(t, document, data)
+
+	// Not a signer
+	c.Signers = c.Signers[1:]
+	_, _ = manager.Get("contracts").UpdateByID(*c)
+	_, _, err = getDocument(client, c.ID.Hex())
+	assert.NotNil(t, err)
+
+	_, _, err = getDocument(client, "invalid")
+	assert.NotNil(t, err)
+}
+
+func TestExpireDocuments(t *testing.T) {
+	dropDataset()
+	dir, _ := ioutil.TempDir("", "dfssp_expiration")
+	defer func() { _ = os.RemoveAll(dir) }()
+	store, err := storage.NewDisk(dir)
+	assert.Nil(t, err)
+
+	referenced := []byte("referenced document")
+	referencedHash := sha512.Sum512(referenced)
+	unreferenced := []byte("unreferenced document")
+	unreferencedHash := sha512.Sum512(unreferenced)
+	assert.Nil(t, store.Put(referencedHash[:], bytes.NewReader(referenced)))
+	assert.Nil(t, store.Put(unreferencedHash[:], bytes.NewReader(unreferenced)))
+
+	c := entities.NewContract()
+	c.File.Hash = referencedHash[:]
+	c.File.Hosted = true
+	_, _ = manager.Get("contracts").Insert(c)
+
+	// Recent uploads are kept
+	assert.Nil(t, contract.ExpireDocuments(manager, store))
+	exists, _ := store.Exists(unreferencedHash[:])
+	assert.True(t, exists)
+
+	expiration := contract.DocumentExpiration
+	contract.DocumentExpiration = -time.Minute
+	defer func() { contract.DocumentExpiration = expiration }()
+
+	assert.Nil(t, contract.ExpireDocuments(manager, store))
+	exists, _ = store.Exists(referencedHash[:])
+	assert.True(t, exists)
+	exists, _ = store.Exists(unreferencedHash[:])
+	assert.False(t, exists)
+}
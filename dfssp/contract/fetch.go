@@ -1,6 +1,8 @@
 package contract
 
 import (
+	"crypto"
+
 	"dfss/dfssp/api"
 	"dfss/dfssp/entities"
 	"dfss/mgdb"
@@ -8,7 +10,8 @@ import (
 )
 
 // Fetch returns the protobuf message when asking a specific contract containing a specific user.
-func Fetch(db *mgdb.MongoManager, contractUUID string, clientHash []byte) *api.Contract {
+// The returned DFSS file is sealed with the private key of the platform.
+func Fetch(key crypto.Signer, db *mgdb.MongoManager, contractUUID string, clientHash []byte) *api.Contract {
 	if !bson.IsObjectIdHex(contractUUID) {
 		return &api.Contract{
 			ErrorCode: &api.ErrorCode{Code: api.ErrorCode_INVARG},
@@ -23,7 +26,7 @@ func Fetch(db *mgdb.MongoManager, contractUUID string, clientHash []byte) *api.C
 		}
 	}
 
-	data, err := GetJSON(contract)
+	data, err := GetJSON(contract, key)
 	if err != nil {
 		return &api.Contract{
 			ErrorCode: &api.ErrorCode{Code: api.ErrorCode_INTERR},
@@ -2,8 +2,12 @@ package contract_test
 
 import (
 	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"testing"
 
+	"dfss/auth"
 	"dfss/dfssp/api"
 	"dfss/dfssp/contract"
 	"dfss/dfssp/entities"
@@ -41,6 +45,11 @@ func TestGetContract(t *testing.T) {
 	err = json.Unmarshal(c.Json, &entity)
 	assert.Equal(t, nil, err)
 	assert.Equal(t, 2, len(entity.Signers))
+
+	// The file is sealed by the platform
+	caData, _ := ioutil.ReadFile(filepath.Join(os.Getenv("GOPATH"), "src", "dfss", "dfssp", "testdata", "dfssp_rootCA.pem"))
+	ca, _ := auth.PEMToCertificate(caData)
+	assert.Equal(t, nil, entity.CheckSeal(ca))
 }
 
 func TestGetContractWrongSigner(t *testing.T) {
@@ -1,24 +1,31 @@
 package contract
 
 import (
+	"crypto"
+	"crypto/x509"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"time"
 
+	"dfss/auth"
 	"dfss/dfssp/entities"
 )
 
+// ErrUnsealed is returned by CheckSeal for files without any seal, generated by older platforms.
+var ErrUnsealed = errors.New("the contract file is not sealed by the platform, its signers and document hash cannot be trusted")
+
 // FileJSON is the structure used to store file information in JSON format
 type FileJSON struct {
-	Name   string
-	Hash   string
-	Hosted bool
+	Name   string `canonical:"1"`
+	Hash   string `canonical:"2"`
+	Hosted bool   `canonical:"3"`
 }
 
 // SignerJSON is the structure used to store signers information in JSON format
 type SignerJSON struct {
-	Email string
-	Hash  string
+	Email string `canonical:"1"`
+	Hash  string `canonical:"2"`
 }
 
 // JSON is the structure used to store contract information in JSON format
@@ -28,10 +35,68 @@ type JSON struct {
 	Comment string
 	File    *FileJSON
 	Signers []SignerJSON
+	// SequenceGenerator is the name of the signing sequence generator, empty for the default one
+	SequenceGenerator string `json:",omitempty"`
+	// Deadline is the deadline of an asynchronous signature, nil for a synchronous one
+	Deadline *time.Time `json:",omitempty"`
+	// Seal is the signature of the platform over the other fields, see SealJSON
+	Seal []byte `json:",omitempty"`
+}
+
+// sealedJSON is the content of a DFSS file covered by the seal of the platform.
+// Dates are unix timestamps, as times cannot be canonically encoded.
+// Field numbers are part of the seal, and must never change.
+type sealedJSON struct {
+	UUID              string       `canonical:"1"`
+	Date              int64        `canonical:"2"`
+	Comment           string       `canonical:"3"`
+	File              FileJSON     `canonical:"4"`
+	Signers           []SignerJSON `canonical:"5"`
+	SequenceGenerator string       `canonical:"6"`
+	Deadline          int64        `canonical:"7"`
+}
+
+func (c *JSON) sealed() sealedJSON {
+	s := sealedJSON{
+		UUID:              c.UUID,
+		Comment:           c.Comment,
+		Signers:           c.Signers,
+		SequenceGenerator: c.SequenceGenerator,
+	}
+	if c.Date != nil {
+		s.Date = c.Date.Unix()
+	}
+	if c.File != nil {
+		s.File = *c.File
+	}
+	if c.Deadline != nil {
+		s.Deadline = c.Deadline.Unix()
+	}
+	return s
+}
+
+// SealJSON seals a DFSS file with the private key of the platform, so that signers can detect forged files as soon as they load them.
+func SealJSON(key crypto.Signer, c *JSON) (err error) {
+	c.Seal, err = auth.SignStructure(key, c.sealed())
+	return
+}
+
+// CheckSeal checks that a DFSS file is sealed by the platform of the provided root certificate.
+// ErrUnsealed is returned if the file has no seal.
+func (c *JSON) CheckSeal(ca *x509.Certificate) error {
+	if len(c.Seal) == 0 {
+		return ErrUnsealed
+	}
+
+	ok, _ := auth.VerifyStructure(ca, c.sealed(), c.Seal)
+	if !ok {
+		return errors.New("the contract file does not match the seal of the platform, its signers or document hash may have been forged")
+	}
+	return nil
 }
 
-// GetJSON returns indented json from a contract and some ttp information (nil allowed)
-func GetJSON(c *entities.Contract) ([]byte, error) {
+// GetJSON returns indented json from a contract, sealed with the private key of the platform (nil allowed, for an unsealed file)
+func GetJSON(c *entities.Contract, key crypto.Signer) ([]byte, error) {
 	data := JSON{
 		UUID:    c.ID.Hex(),
 		Date:    &c.Date,
@@ -41,7 +106,12 @@ func GetJSON(c *entities.Contract) ([]byte, error) {
 			Hash:   fmt.Sprintf("%x", c.File.Hash),
 			Hosted: c.File.Hosted,
 		},
-		Signers: make([]SignerJSON, len(c.Signers)),
+		Signers:           make([]SignerJSON, len(c.Signers)),
+		SequenceGenerator: c.SequenceGenerator,
+	}
+
+	if c.IsAsync() {
+		data.Deadline = &c.Deadline
 	}
 
 	for i, s := range c.Signers {
@@ -49,5 +119,11 @@ func GetJSON(c *entities.Contract) ([]byte, error) {
 		data.Signers[i].Hash = fmt.Sprintf("%x", s.Hash)
 	}
 
+	if key != nil {
+		if err := SealJSON(key, &data); err != nil {
+			return nil, err
+		}
+	}
+
 	return json.MarshalIndent(data, "", "  ")
 }
@@ -1,9 +1,11 @@
 package contract
 
 import (
+	"encoding/json"
 	"testing"
 	"time"
 
+	"dfss/auth"
 	"dfss/dfssp/entities"
 	"github.com/stretchr/testify/assert"
 	"gopkg.in/mgo.v2/bson"
@@ -50,8 +52,49 @@ allow multiline and accents: éÉ`,
   ]
 }`
 
-	j, err := GetJSON(c)
+	j, err := GetJSON(c, nil)
 	assert.Equal(t, nil, err)
 	assert.Equal(t, expected, string(j))
 
 }
+
+func TestSealJSON(t *testing.T) {
+	key, _ := auth.GeneratePrivateKey(1024)
+	caPem, _ := auth.GetSelfSignedCertificate(1, 0, "FR", "DFSS", "TEST", "ca", key)
+	ca, _ := auth.PEMToCertificate(caPem)
+
+	c := &entities.Contract{
+		ID:       bson.NewObjectId(),
+		Date:     time.Now(),
+		File:     &entities.File{Name: "filename.pdf", Hash: []byte{0x01, 0x02}},
+		Signers:  []entities.Signer{{Email: "a", Hash: []byte{0xaa}}, {Email: "b", Hash: []byte{0xbb}}},
+		Deadline: time.Now().Add(time.Hour),
+	}
+
+	data, err := GetJSON(c, key)
+	assert.Nil(t, err)
+	file := &JSON{}
+	assert.Nil(t, json.Unmarshal(data, file))
+	assert.Nil(t, file.CheckSeal(ca))
+
+	// Forged signers
+	file.Signers[1].Hash = "cc"
+	assert.NotNil(t, file.CheckSeal(ca))
+	file.Signers[1].Hash = "bb"
+	file.Signers = append(file.Signers, SignerJSON{Email: "c", Hash: "cc"})
+	assert.NotNil(t, file.CheckSeal(ca))
+	file.Signers = file.Signers[:2]
+	assert.Nil(t, file.CheckSeal(ca))
+
+	// Forged document
+	file.File.Hash = "0103"
+	assert.NotNil(t, file.CheckSeal(ca))
+	file.File.Hash = "0102"
+
+	// Forged deadline
+	file.Deadline = nil
+	assert.NotNil(t, file.CheckSeal(ca))
+
+	file.Seal = nil
+	assert.Equal(t, ErrUnsealed, file.CheckSeal(ca))
+}
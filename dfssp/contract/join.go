@@ -2,6 +2,7 @@ package contract
 
 import (
 	n "net"
+	"strconv"
 	"time"
 
 	"dfss/auth"
@@ -44,10 +45,12 @@ func JoinSignature(db *mgdb.MongoManager, rooms *common.WaitingGroupMap, in *api
 	// Broadcast self identity
 	host, _, _ := n.SplitHostPort(addr.String())
 	rooms.Broadcast(roomID, &api.User{
-		KeyHash: hash,
-		Email:   net.GetCN(&ctx),
-		Ip:      append(in.Ip, host),
-		Port:    in.Port,
+		KeyHash:     hash,
+		Email:       net.GetCN(&ctx),
+		Ip:          append(in.Ip, host),
+		Port:        in.Port,
+		Certificate: state.VerifiedChains[0][0].Raw,
+		Endpoint:    joinEndpoints(in, host),
 	})
 
 	// Listen for others
@@ -72,6 +75,32 @@ func JoinSignature(db *mgdb.MongoManager, rooms *common.WaitingGroupMap, in *api
 	}
 }
 
+// joinEndpoints returns the valid endpoints (host:port) offered by a signer, built from its ips and port for older clients.
+// The address seen by the platform is added with the port of the signer, as it is the public one of a signer behind a NAT.
+func joinEndpoints(in *api.JoinSignatureRequest, host string) []string {
+	port := strconv.Itoa(int(in.Port))
+	offered := in.Endpoint
+	if len(offered) == 0 {
+		for _, ip := range in.Ip {
+			offered = append(offered, n.JoinHostPort(ip, port))
+		}
+	}
+
+	observed := n.JoinHostPort(host, port)
+	endpoints := make([]string, 0, len(offered)+1)
+	for _, e := range offered {
+		h, p, err := n.SplitHostPort(e)
+		if err != nil || h == "" || p == "" || e == observed {
+			continue
+		}
+		endpoints = append(endpoints, e)
+	}
+	if in.Port != 0 && host != "" {
+		endpoints = append(endpoints, observed)
+	}
+	return endpoints
+}
+
 func checkJoinSignatureRequest(db *mgdb.MongoManager, stream *api.Platform_JoinSignatureServer, contractUUID string, clientHash []byte) bool {
 	if !bson.IsObjectIdHex(contractUUID) {
 		_ = (*stream).Send(&api.UserConnected{
@@ -37,6 +37,30 @@ func TestJoinSignature(t *testing.T) {
 	assert.Equal(t, contractID.Hex(), user.ContractUuid)
 	assert.Equal(t, "test@test.com", user.User.Email)
 	assert.Equal(t, uint32(5050), user.User.Port)
+	assert.Equal(t, 1, len(user.User.Endpoint))
+}
+
+func TestJoinSignatureEndpoints(t *testing.T) {
+	dropDataset()
+	createDataset()
+	contractID := addTestContract()
+
+	client := clientTest(t)
+	stream, err := client.JoinSignature(context.Background(), &api.JoinSignatureRequest{
+		ContractUuid: contractID.Hex(),
+		Port:         5050,
+		Ip:           []string{"192.0.2.1"},
+		Endpoint:     []string{"[2001:db8::1]:5051", "192.0.2.1:5050", "bad", ":5050"},
+	})
+	assert.Equal(t, nil, err)
+
+	user, err := stream.Recv()
+	assert.Equal(t, nil, err)
+	assert.Equal(t, api.ErrorCode_SUCCESS, user.ErrorCode.Code)
+	// The address seen by the platform is added at the end
+	assert.Equal(t, 3, len(user.User.Endpoint))
+	assert.Equal(t, "[2001:db8::1]:5051", user.User.Endpoint[0])
+	assert.Equal(t, "192.0.2.1:5050", user.User.Endpoint[1])
 }
 
 func TestJoinSignatureBadContract(t *testing.T) {
@@ -0,0 +1,236 @@
+package contract
+
+import (
+	"bytes"
+	"log"
+	"time"
+
+	"dfss/auth"
+	"dfss/dfssp/api"
+	"dfss/dfssp/entities"
+	"dfss/mgdb"
+	"dfss/net"
+	"github.com/golang/protobuf/proto"
+	"golang.org/x/net/context"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// MaxEvidenceSize is the maximum size of an encrypted evidence stored in a mailbox.
+var MaxEvidenceSize = 1 << 20
+
+// MaxMailboxSize is the maximum number of evidence waiting for a recipient in the mailbox of a signature.
+var MaxMailboxSize = 256
+
+// ReadySignAsync is the asynchronous counterpart of ReadySign.
+// Signers of an asynchronous contract do not wait for each other: the first of them to be ready launches the signature,
+// and the others get the same launch signal until the deadline of the contract.
+// The provided seal function is called once, to assign a ttp to the signature and seal it.
+//
+// It returns nil if the contract is not an asynchronous one, in which case ReadySign should be used.
+func ReadySignAsync(db *mgdb.MongoManager, ctx *context.Context, in *api.ReadySignRequest, seal func(*api.LaunchSignature) error) *api.LaunchSignature {
+	if !bson.IsObjectIdHex(in.ContractUuid) {
+		return nil
+	}
+
+	c := entities.Contract{}
+	err := db.Get("contracts").FindByID(entities.Contract{ID: bson.ObjectIdHex(in.ContractUuid)}, &c)
+	if err != nil || !c.IsAsync() {
+		return nil
+	}
+
+	if !c.Ready {
+		return &api.LaunchSignature{ErrorCode: &api.ErrorCode{Code: api.ErrorCode_INVARG, Message: "some signers are not registered yet"}}
+	}
+	if c.GetSigner(net.GetClientHash(ctx)) < 0 {
+		return &api.LaunchSignature{ErrorCode: &api.ErrorCode{Code: api.ErrorCode_BADAUTH}}
+	}
+	if time.Now().After(c.Deadline) {
+		return &api.LaunchSignature{ErrorCode: &api.ErrorCode{Code: api.ErrorCode_TIMEOUT, Message: "signature deadline is over"}}
+	}
+
+	launch, err := getLaunch(db, &c, seal)
+	if renewed, ok := err.(*renewedCertificateError); ok {
+		return &api.LaunchSignature{ErrorCode: &api.ErrorCode{Code: api.ErrorCode_INVARG, Message: renewed.Error()}}
+	}
+	if err != nil {
+		log.Println(err)
+		return &api.LaunchSignature{ErrorCode: &api.ErrorCode{Code: api.ErrorCode_INTERR}}
+	}
+
+	launch.ErrorCode = &api.ErrorCode{Code: api.ErrorCode_SUCCESS}
+	return launch
+}
+
+// getLaunch returns the launch signal of an asynchronous contract, creating and storing it if needed.
+// The certificates of the signers are pinned in the stored launch signal, as they may be renewed during the signature.
+func getLaunch(db *mgdb.MongoManager, c *entities.Contract, seal func(*api.LaunchSignature) error) (*api.LaunchSignature, error) {
+	if len(c.Launch) == 0 {
+		certificates, err := getCertificates(db, c)
+		if err != nil {
+			return nil, err
+		}
+
+		name := c.SequenceGenerator
+		if name == "" {
+			name = DefaultSequenceGenerator
+		}
+		generator, err := GetSequenceGenerator(name)
+		if err != nil {
+			return nil, err
+		}
+
+		launch := &api.LaunchSignature{
+			SignatureUuid:     bson.NewObjectId().Hex(),
+			DocumentHash:      c.File.Hash,
+			KeyHash:           c.GetHashChain(),
+			Sequence:          generator.Generate(len(c.Signers)),
+			SequenceGenerator: name,
+			Deadline:          c.Deadline.Unix(),
+		}
+		if err = seal(launch); err != nil {
+			return nil, err
+		}
+		launch.Certificate = certificates // not sealed, the certificates are checked against the sealed hashes
+		data, err := proto.Marshal(launch)
+		if err != nil {
+			return nil, err
+		}
+
+		repository := entities.NewContractRepository(db.Get("contracts"))
+		stored, err := repository.SetLaunch(c.ID, launch.SignatureUuid, data)
+		if err != nil {
+			return nil, err
+		}
+		if stored {
+			return launch, nil
+		}
+
+		// Another signer has launched the signature in the meantime
+		if err = db.Get("contracts").FindByID(entities.Contract{ID: c.ID}, c); err != nil {
+			return nil, err
+		}
+	}
+
+	launch := &api.LaunchSignature{}
+	err := proto.Unmarshal(c.Launch, launch)
+	return launch, err
+}
+
+// renewedCertificateError is returned when the certificate of a signer does not match the contract anymore
+type renewedCertificateError struct {
+	email string
+}
+
+func (e *renewedCertificateError) Error() string {
+	return "the certificate of " + e.email + " has been renewed since the contract creation, try again once the contract is updated"
+}
+
+// getCertificates returns the DER certificates of the signers of a contract, in the order of the hash chain.
+// A renewedCertificateError is returned if the current certificate of a signer is not the one of the contract.
+func getCertificates(db *mgdb.MongoManager, c *entities.Contract) ([][]byte, error) {
+	certificates := make([][]byte, len(c.Signers))
+	for i, s := range c.Signers {
+		user := entities.User{}
+		err := db.Get("users").FindByID(entities.User{ID: s.UserID}, &user)
+		if err != nil {
+			return nil, err
+		}
+		cert, err := auth.PEMToCertificate([]byte(user.Certificate))
+		if err != nil {
+			return nil, err
+		}
+		// The certificate may have been renewed since the contract creation, the sealed hash is the reference.
+		// Contracts not launched yet are updated on renewal, see user.Renew.
+		if !bytes.Equal(auth.GetCertificateHash(cert), s.Hash) {
+			return nil, &renewedCertificateError{email: s.Email}
+		}
+		certificates[i] = cert.Raw
+	}
+	return certificates, nil
+}
+
+// PostEvidence stores an encrypted evidence in the mailbox of its recipient.
+// The sender is authenticated by its certificate, and both the sender and the recipient must be signers of the contract.
+func PostEvidence(db *mgdb.MongoManager, ctx *context.Context, in *api.Evidence) *api.ErrorCode {
+	c, sender, errorCode := getMailboxContract(db, ctx, in.SignatureUuid)
+	if errorCode != nil {
+		return errorCode
+	}
+
+	if c.GetSigner(in.RecipientKeyHash) < 0 || bytes.Equal(sender, in.RecipientKeyHash) {
+		return &api.ErrorCode{Code: api.ErrorCode_INVARG, Message: "bad recipient"}
+	}
+	if len(in.Payload) == 0 || len(in.Payload) > MaxEvidenceSize {
+		return &api.ErrorCode{Code: api.ErrorCode_INVARG, Message: "bad evidence size"}
+	}
+	if time.Now().After(c.Deadline) {
+		return &api.ErrorCode{Code: api.ErrorCode_TIMEOUT, Message: "signature deadline is over"}
+	}
+
+	repository := entities.NewEvidenceRepository(db.Get("evidence"))
+	count, err := repository.Count(in.SignatureUuid, in.RecipientKeyHash)
+	if err != nil {
+		log.Println(err)
+		return &api.ErrorCode{Code: api.ErrorCode_INTERR}
+	}
+	if count >= MaxMailboxSize {
+		return &api.ErrorCode{Code: api.ErrorCode_INVARG, Message: "mailbox is full"}
+	}
+
+	_, err = db.Get("evidence").Insert(entities.NewEvidence(in.SignatureUuid, sender, in.RecipientKeyHash, in.Payload))
+	if err != nil {
+		log.Println(err)
+		return &api.ErrorCode{Code: api.ErrorCode_INTERR}
+	}
+	return &api.ErrorCode{Code: api.ErrorCode_SUCCESS}
+}
+
+// FetchEvidence returns and removes the evidence waiting in the mailbox of the authenticated signer.
+// Mailboxes can still be emptied after the deadline.
+func FetchEvidence(db *mgdb.MongoManager, ctx *context.Context, in *api.FetchEvidenceRequest) *api.EvidenceList {
+	_, recipient, errorCode := getMailboxContract(db, ctx, in.SignatureUuid)
+	if errorCode != nil {
+		return &api.EvidenceList{ErrorCode: errorCode}
+	}
+
+	repository := entities.NewEvidenceRepository(db.Get("evidence"))
+	evidence, err := repository.Take(in.SignatureUuid, recipient)
+	if err != nil {
+		log.Println(err)
+		return &api.EvidenceList{ErrorCode: &api.ErrorCode{Code: api.ErrorCode_INTERR}}
+	}
+
+	list := make([]*api.Evidence, len(evidence))
+	for i, e := range evidence {
+		list[i] = &api.Evidence{
+			SignatureUuid:    e.SignatureUUID,
+			SenderKeyHash:    e.Sender,
+			RecipientKeyHash: e.Recipient,
+			Payload:          e.Payload,
+		}
+	}
+	return &api.EvidenceList{ErrorCode: &api.ErrorCode{Code: api.ErrorCode_SUCCESS}, Evidence: list}
+}
+
+// getMailboxContract returns the asynchronous contract of a signature and the hash of the authenticated signer.
+func getMailboxContract(db *mgdb.MongoManager, ctx *context.Context, signatureUUID string) (*entities.Contract, []byte, *api.ErrorCode) {
+	if !bson.IsObjectIdHex(signatureUUID) {
+		return nil, nil, &api.ErrorCode{Code: api.ErrorCode_INVARG, Message: "bad signature uuid"}
+	}
+
+	repository := entities.NewContractRepository(db.Get("contracts"))
+	c, err := repository.GetBySignature(signatureUUID)
+	if err != nil {
+		log.Println(err)
+		return nil, nil, &api.ErrorCode{Code: api.ErrorCode_INTERR}
+	}
+	if c == nil {
+		return nil, nil, &api.ErrorCode{Code: api.ErrorCode_INVARG, Message: "unknown signature"}
+	}
+
+	hash := net.GetClientHash(ctx)
+	if c.GetSigner(hash) < 0 {
+		return nil, nil, &api.ErrorCode{Code: api.ErrorCode_BADAUTH}
+	}
+	return c, hash, nil
+}
@@ -0,0 +1,155 @@
+package contract_test
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"dfss/auth"
+	"dfss/dfssp/api"
+	"dfss/dfssp/entities"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/net/context"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// createAsyncContract inserts a ready asynchronous contract between user1 and user2
+func createAsyncContract(deadline time.Time, signatureUUID string) *entities.Contract {
+	c := entities.NewContract()
+	c.AddSigner(&user1.ID, user1.Email, user1.CertHash)
+	c.AddSigner(&user2.ID, user2.Email, user2.CertHash)
+	c.File.Name = "ContractFilename"
+	c.File.Hash = defaultHash[:]
+	c.Ready = true
+	c.Deadline = deadline
+	c.SignatureUUID = signatureUUID
+	_, _ = manager.Get("contracts").Insert(c)
+	return c
+}
+
+// setTestCertificate gives a new self-signed certificate to the user
+func setTestCertificate(t *testing.T, user *entities.User) {
+	key, _ := auth.GeneratePrivateKey(1024)
+	certData, err := auth.GetSelfSignedCertificate(1, 0, "", "", "", user.Email, key)
+	assert.Nil(t, err)
+	cert, _ := auth.PEMToCertificate(certData)
+	user.Certificate = string(certData)
+	user.CertHash = auth.GetCertificateHash(cert)
+	_, _ = manager.Get("users").UpdateByID(*user)
+}
+
+func TestReadySignAsync(t *testing.T) {
+	dropDataset()
+	createDataset()
+
+	// Only user1 has a valid certificate in the dataset
+	certData, _ := ioutil.ReadFile(filepath.Join("..", "..", "dfssc", "testdata", "cert.pem"))
+	user1.Certificate = string(certData)
+	_, _ = manager.Get("users").UpdateByID(*user1)
+	setTestCertificate(t, user2)
+
+	c := createAsyncContract(time.Now().Add(time.Hour), "")
+	client := clientTest(t)
+
+	launch, err := client.ReadySign(context.Background(), &api.ReadySignRequest{ContractUuid: c.ID.Hex()})
+	assert.Equal(t, nil, err)
+	assert.Equal(t, api.ErrorCode_SUCCESS, launch.ErrorCode.Code, launch.ErrorCode.Message)
+	assert.Equal(t, c.Deadline.Unix(), launch.Deadline)
+	assert.Equal(t, c.GetHashChain(), launch.KeyHash)
+	assert.NotNil(t, launch.Seal)
+	assert.Equal(t, 2, len(launch.Certificate))
+	assert.NotNil(t, launch.Certificate[0])
+	assert.NotNil(t, launch.Certificate[1])
+
+	// Every signer gets the same launch signal, with the certificates pinned at launch even if renewed since
+	launched := c
+	setTestCertificate(t, user2)
+	again, err := client.ReadySign(context.Background(), &api.ReadySignRequest{ContractUuid: launched.ID.Hex()})
+	assert.Equal(t, nil, err)
+	assert.Equal(t, launch.SignatureUuid, again.SignatureUuid)
+	assert.Equal(t, launch.Seal, again.Seal)
+	assert.Equal(t, launch.Certificate, again.Certificate)
+
+	// No launch while the contract is not updated with the renewed certificate of a signer
+	c = createAsyncContract(time.Now().Add(time.Hour), "")
+	c.Signers[1].Hash = []byte{0x02}
+	_, _ = manager.Get("contracts").UpdateByID(*c)
+	launch, err = client.ReadySign(context.Background(), &api.ReadySignRequest{ContractUuid: c.ID.Hex()})
+	assert.Equal(t, nil, err)
+	assert.Equal(t, api.ErrorCode_INVARG, launch.ErrorCode.Code)
+	assert.Contains(t, launch.ErrorCode.Message, user2.Email)
+
+	// No launch after the deadline
+	c = createAsyncContract(time.Now().Add(-time.Minute), "")
+	launch, err = client.ReadySign(context.Background(), &api.ReadySignRequest{ContractUuid: c.ID.Hex()})
+	assert.Equal(t, nil, err)
+	assert.Equal(t, api.ErrorCode_TIMEOUT, launch.ErrorCode.Code)
+}
+
+func TestPostEvidence(t *testing.T) {
+	dropDataset()
+	createDataset()
+
+	uuid := bson.NewObjectId().Hex()
+	createAsyncContract(time.Now().Add(time.Hour), uuid)
+	client := clientTest(t)
+
+	post := func(uuid string, recipient, payload []byte) api.ErrorCode_Code {
+		errorCode, err := client.PostEvidence(context.Background(), &api.Evidence{
+			SignatureUuid:    uuid,
+			RecipientKeyHash: recipient,
+			Payload:          payload,
+		})
+		assert.Equal(t, nil, err)
+		return errorCode.Code
+	}
+
+	assert.Equal(t, api.ErrorCode_SUCCESS, post(uuid, user2.CertHash, []byte{0x01}))
+	assert.Equal(t, api.ErrorCode_INVARG, post(uuid, user1.CertHash, []byte{0x01}))
+	assert.Equal(t, api.ErrorCode_INVARG, post(uuid, user3.CertHash, []byte{0x01}))
+	assert.Equal(t, api.ErrorCode_INVARG, post(uuid, user2.CertHash, nil))
+	assert.Equal(t, api.ErrorCode_INVARG, post(bson.NewObjectId().Hex(), user2.CertHash, []byte{0x01}))
+	assert.Equal(t, api.ErrorCode_INVARG, post("bad", user2.CertHash, []byte{0x01}))
+
+	var evidence []entities.Evidence
+	_ = manager.Get("evidence").FindAll(nil, &evidence)
+	assert.Equal(t, 1, len(evidence))
+	assert.Equal(t, user1.CertHash, evidence[0].Sender)
+	assert.Equal(t, user2.CertHash, evidence[0].Recipient)
+
+	// No evidence after the deadline
+	uuid = bson.NewObjectId().Hex()
+	createAsyncContract(time.Now().Add(-time.Minute), uuid)
+	assert.Equal(t, api.ErrorCode_TIMEOUT, post(uuid, user2.CertHash, []byte{0x01}))
+}
+
+func TestFetchEvidence(t *testing.T) {
+	dropDataset()
+	createDataset()
+
+	uuid := bson.NewObjectId().Hex()
+	createAsyncContract(time.Now().Add(time.Hour), uuid)
+	_, _ = manager.Get("evidence").Insert(entities.NewEvidence(uuid, user2.CertHash, user1.CertHash, []byte{0x01}))
+	_, _ = manager.Get("evidence").Insert(entities.NewEvidence(uuid, user2.CertHash, user1.CertHash, []byte{0x02}))
+	_, _ = manager.Get("evidence").Insert(entities.NewEvidence(uuid, user1.CertHash, user2.CertHash, []byte{0x03}))
+	client := clientTest(t)
+
+	list, err := client.FetchEvidence(context.Background(), &api.FetchEvidenceRequest{SignatureUuid: uuid})
+	assert.Equal(t, nil, err)
+	assert.Equal(t, api.ErrorCode_SUCCESS, list.ErrorCode.Code)
+	assert.Equal(t, 2, len(list.Evidence))
+	assert.Equal(t, user2.CertHash, list.Evidence[0].SenderKeyHash)
+	assert.Equal(t, []byte{0x01}, list.Evidence[0].Payload)
+	assert.Equal(t, []byte{0x02}, list.Evidence[1].Payload)
+
+	// The mailbox is emptied, other mailboxes are kept
+	list, err = client.FetchEvidence(context.Background(), &api.FetchEvidenceRequest{SignatureUuid: uuid})
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 0, len(list.Evidence))
+	assert.Equal(t, 1, manager.Get("evidence").Count())
+
+	list, err = client.FetchEvidence(context.Background(), &api.FetchEvidenceRequest{SignatureUuid: bson.NewObjectId().Hex()})
+	assert.Equal(t, nil, err)
+	assert.Equal(t, api.ErrorCode_INVARG, list.ErrorCode.Code)
+}
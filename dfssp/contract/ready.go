@@ -14,11 +14,9 @@ import (
 
 // readySignal is the structure that is transmitted accross goroutines
 type readySignal struct {
-	ready        bool     // If true, this is the ready signal. If not, this is a new connection signal
-	data         string   // Various data (CN or SignatureUUID)
-	documentHash []byte   // Contract document SHA-512 hash
-	chain        [][]byte // Only used to broadcast hash chain (signers hashes in order)
-	sequence     []uint32 // Only used to broadcast signature sequence
+	ready  bool                 // If true, this is the ready signal. If not, this is a new connection signal
+	data   string               // CN of the new connection
+	launch *api.LaunchSignature // Only used to broadcast the sealed launch signal, nil if the contract is bad
 }
 
 // ReadySignTimeout is the delay users have to confirm the signature.
@@ -30,7 +28,9 @@ var ReadySignTimeout = time.Minute
 // When a new client is ready, it joins a waitingGroup and waits for a master broadcast announcing that everybody is ready.
 //
 // Doing it this way is efficient in time, as only one goroutine deals with the database and do global checks.
-func ReadySign(db *mgdb.MongoManager, rooms *common.WaitingGroupMap, ctx *context.Context, in *api.ReadySignRequest) *api.LaunchSignature {
+// The provided seal function is called once by this goroutine, to assign a ttp to the signature and seal it:
+// every signer gets the same launch signal.
+func ReadySign(db *mgdb.MongoManager, rooms *common.WaitingGroupMap, ctx *context.Context, in *api.ReadySignRequest, seal func(*api.LaunchSignature) error) *api.LaunchSignature {
 	roomID := "ready_" + in.ContractUuid
 	channel, _, first := rooms.Join(roomID)
 	defer rooms.Unjoin(roomID, channel)
@@ -44,7 +44,7 @@ func ReadySign(db *mgdb.MongoManager, rooms *common.WaitingGroupMap, ctx *contex
 	// If first in the room, create a goroutine for ready check.
 	// It is absolutely thread safe thanks to a mutex applied on the `first` variable.
 	if first {
-		go masterReadyRoutine(db, rooms, in.ContractUuid)
+		go masterReadyRoutine(db, rooms, in.ContractUuid, seal)
 	}
 
 	// Broadcast identity
@@ -60,15 +60,10 @@ func ReadySign(db *mgdb.MongoManager, rooms *common.WaitingGroupMap, ctx *contex
 			}
 			s := signal.(*readySignal)
 			if s.ready {
-				if len(s.data) > 0 {
-					return &api.LaunchSignature{
-						ErrorCode:     &api.ErrorCode{Code: api.ErrorCode_SUCCESS},
-						SignatureUuid: s.data,
-						DocumentHash:  s.documentHash,
-						KeyHash:       s.chain,
-						Sequence:      s.sequence,
-					}
-				} // data == "" means the contractUUID is bad
+				if s.launch != nil {
+					launch := *s.launch
+					return &launch
+				} // launch == nil means the contractUUID is bad
 				return &api.LaunchSignature{ErrorCode: &api.ErrorCode{Code: api.ErrorCode_INVARG}}
 			}
 		case <-(*ctx).Done(): // Client's disconnection
@@ -82,7 +77,7 @@ func ReadySign(db *mgdb.MongoManager, rooms *common.WaitingGroupMap, ctx *contex
 
 // masterReadyRoutine is a function to be started by the first signer ready as a goroutine.
 // It will join the associated ready room and check ready status of each signer when a new signer signals its readiness.
-func masterReadyRoutine(db *mgdb.MongoManager, rooms *common.WaitingGroupMap, contractUUID string) {
+func masterReadyRoutine(db *mgdb.MongoManager, rooms *common.WaitingGroupMap, contractUUID string, seal func(*api.LaunchSignature) error) {
 	roomID := "ready_" + contractUUID
 	channel, oldMessages, _ := rooms.Join(roomID)
 	defer rooms.Unjoin(roomID, channel)
@@ -98,10 +93,17 @@ func masterReadyRoutine(db *mgdb.MongoManager, rooms *common.WaitingGroupMap, co
 	contract := entities.Contract{}
 	err := db.Get("contracts").FindByID(fetch, &contract)
 	if err != nil {
-		rooms.Broadcast(roomID, &readySignal{
-			ready: true,
-			data:  "",
-		}) // This represents a "error" response
+		rooms.Broadcast(roomID, &readySignal{ready: true}) // This represents a "error" response
+		return
+	}
+
+	name := contract.SequenceGenerator
+	if name == "" {
+		name = DefaultSequenceGenerator
+	}
+	generator, err := GetSequenceGenerator(name)
+	if err != nil {
+		rooms.Broadcast(roomID, &readySignal{ready: true})
 		return
 	}
 
@@ -117,13 +119,24 @@ func masterReadyRoutine(db *mgdb.MongoManager, rooms *common.WaitingGroupMap, co
 			cn := signal.(*readySignal).data
 			ready := FindAndUpdatePendingSigner(cn, &signersReady, &contract.Signers)
 			if ready {
-				rooms.Broadcast(roomID, &readySignal{
-					ready:        true,
-					data:         bson.NewObjectId().Hex(),
-					documentHash: contract.File.Hash,
-					chain:        contract.GetHashChain(),
-					sequence:     GenerateSignSequence(len(contract.Signers)),
-				})
+				launch := &api.LaunchSignature{
+					SignatureUuid:     bson.NewObjectId().Hex(),
+					DocumentHash:      contract.File.Hash,
+					KeyHash:           contract.GetHashChain(),
+					Sequence:          generator.Generate(len(contract.Signers)),
+					SequenceGenerator: name,
+				}
+				// Renewed certificates must not replace the sealed ones from now on
+				err = entities.NewContractRepository(db.Get("contracts")).SetLaunched(contract.ID)
+				if err == nil {
+					err = seal(launch)
+				}
+				if err != nil {
+					launch = &api.LaunchSignature{ErrorCode: &api.ErrorCode{Code: api.ErrorCode_INTERR}}
+				} else {
+					launch.ErrorCode = &api.ErrorCode{Code: api.ErrorCode_SUCCESS}
+				}
+				rooms.Broadcast(roomID, &readySignal{ready: true, launch: launch})
 				work = false
 			}
 		case <-timeout:
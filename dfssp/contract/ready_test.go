@@ -1,13 +1,22 @@
 package contract_test
 
 import (
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"sync"
 	"testing"
 
+	"dfss/auth"
 	"dfss/dfssp/api"
+	"dfss/dfssp/authority"
+	"dfss/dfssp/common"
 	"dfss/dfssp/contract"
 	"dfss/dfssp/entities"
 	"github.com/stretchr/testify/assert"
 	"golang.org/x/net/context"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
 	"gopkg.in/mgo.v2/bson"
 )
 
@@ -51,3 +60,67 @@ func TestFindAndUpdatePendingSigner(t *testing.T) {
 	assert.Equal(t, true, contract.FindAndUpdatePendingSigner("b", &signersReady, &signers))
 	assert.Equal(t, true, contract.FindAndUpdatePendingSigner("a", &signersReady, &signers))
 }
+
+func signerContext(mail string) context.Context {
+	cert := &x509.Certificate{Subject: pkix.Name{CommonName: mail}}
+	return peer.NewContext(context.Background(), &peer.Peer{
+		AuthInfo: credentials.TLSInfo{State: tls.ConnectionState{
+			VerifiedChains: [][]*x509.Certificate{{cert}},
+		}},
+	})
+}
+
+func TestReadySignSameLaunch(t *testing.T) {
+	dropDataset()
+	createDataset()
+
+	c := entities.NewContract()
+	c.AddSigner(&user1.ID, user1.Email, user1.CertHash)
+	c.AddSigner(&user2.ID, user2.Email, user2.CertHash)
+	c.File.Hash = []byte{0xff}
+	c.Ready = true
+	_, err := manager.Get("contracts").Insert(c)
+	assert.Nil(t, err)
+
+	// Round-robin over two ttps, and randomized seals
+	ttps, _ := authority.NewTTPHolder("")
+	ttps.Add("ttp1:9020", []byte{0x01})
+	ttps.Add("ttp2:9020", []byte{0x02})
+	key, err := auth.GenerateKey(auth.KeyTypeECDSAP256, 0)
+	assert.Nil(t, err)
+
+	var calls int
+	seal := func(launch *api.LaunchSignature) error {
+		calls++
+		launch.Ttp = ttps.Get()
+		var sealErr error
+		launch.Seal, sealErr = auth.SignStructure(key, *launch)
+		return sealErr
+	}
+
+	rooms := common.NewWaitingGroupMap()
+	launches := make([]*api.LaunchSignature, 2)
+	var wg sync.WaitGroup
+	for i, mail := range []string{user1.Email, user2.Email} {
+		wg.Add(1)
+		go func(i int, mail string) {
+			defer wg.Done()
+			ctx := signerContext(mail)
+			launches[i] = contract.ReadySign(manager, rooms, &ctx, &api.ReadySignRequest{ContractUuid: c.ID.Hex()}, seal)
+		}(i, mail)
+	}
+	wg.Wait()
+
+	assert.Equal(t, 1, calls)
+	for _, launch := range launches {
+		assert.Equal(t, api.ErrorCode_SUCCESS, launch.ErrorCode.Code)
+	}
+	assert.Equal(t, launches[0].SignatureUuid, launches[1].SignatureUuid)
+	assert.Equal(t, launches[0].Ttp, launches[1].Ttp)
+	assert.Equal(t, launches[0].Seal, launches[1].Seal)
+
+	// Renewed certificates must not replace the sealed ones anymore
+	fetched := entities.Contract{}
+	assert.Nil(t, manager.Get("contracts").FindByID(*c, &fetched))
+	assert.True(t, fetched.Launched)
+}
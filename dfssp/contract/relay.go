@@ -0,0 +1,92 @@
+package contract
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"time"
+
+	"dfss/dfssp/api"
+	"dfss/dfssp/common"
+	"dfss/dfssp/entities"
+	"dfss/mgdb"
+	"dfss/net"
+	"golang.org/x/net/context"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// relayKey identifies the relay of a signer for a specific signature
+func relayKey(contractUUID, signatureUUID string, hash []byte) string {
+	return fmt.Sprintf("%s_%s_%x", contractUUID, signatureUUID, hash)
+}
+
+// getRelayContract returns the ready contract of a relay request, if the authenticated user is one of its signers.
+func getRelayContract(db *mgdb.MongoManager, contractUUID string, hash []byte) *entities.Contract {
+	if !bson.IsObjectIdHex(contractUUID) || len(hash) == 0 {
+		return nil
+	}
+
+	repository := entities.NewContractRepository(db.Get("contracts"))
+	contract, _ := repository.GetWithSigner(hash, bson.ObjectIdHex(contractUUID))
+	if contract == nil || !contract.Ready {
+		return nil
+	}
+	return contract
+}
+
+// Relay sends to the stream the evidence relayed to the authenticated signer, for a specific signature.
+// Only one relay can be opened by a signer for a signature, a new one replaces the previous one.
+//
+// There is no timeout, this function will shut down on stream disconnection or after an hour.
+func Relay(db *mgdb.MongoManager, relays *common.RelayMap, in *api.RelayRequest, stream api.Platform_RelayServer) error {
+	ctx := stream.Context()
+	hash := net.GetClientHash(&ctx)
+	if getRelayContract(db, in.ContractUuid, hash) == nil || len(in.SignatureUuid) == 0 {
+		return errors.New("unauthorized relay")
+	}
+
+	key := relayKey(in.ContractUuid, in.SignatureUuid, hash)
+	channel := relays.Listen(key)
+	defer relays.Unlisten(key, channel)
+
+	for {
+		select {
+		case evidence, ok := <-channel:
+			if !ok { // Replaced by another relay
+				return nil
+			}
+			err := stream.Send(evidence.(*api.Evidence))
+			if err != nil {
+				return err
+			}
+		case <-ctx.Done(): // Disconnect
+			return nil
+		case <-time.After(time.Hour): // Timeout
+			return nil
+		}
+	}
+}
+
+// RelayEvidence forwards an encrypted evidence to the relay of its recipient.
+// The sender is authenticated by its certificate, and both the sender and the recipient must be signers of the contract.
+// The evidence is not kept if the recipient has not opened its relay.
+func RelayEvidence(db *mgdb.MongoManager, relays *common.RelayMap, ctx *context.Context, in *api.Evidence) *api.ErrorCode {
+	sender := net.GetClientHash(ctx)
+	contract := getRelayContract(db, in.ContractUuid, sender)
+	if contract == nil {
+		return &api.ErrorCode{Code: api.ErrorCode_BADAUTH, Message: "unauthorized relay"}
+	}
+
+	if contract.GetSigner(in.RecipientKeyHash) < 0 || bytes.Equal(sender, in.RecipientKeyHash) {
+		return &api.ErrorCode{Code: api.ErrorCode_INVARG, Message: "bad recipient"}
+	}
+	if len(in.Payload) == 0 || len(in.Payload) > MaxEvidenceSize {
+		return &api.ErrorCode{Code: api.ErrorCode_INVARG, Message: "bad evidence size"}
+	}
+
+	in.SenderKeyHash = sender
+	if !relays.Send(relayKey(in.ContractUuid, in.SignatureUuid, in.RecipientKeyHash), in) {
+		return &api.ErrorCode{Code: api.ErrorCode_TIMEOUT, Message: "recipient is not connected to the relay"}
+	}
+	return &api.ErrorCode{Code: api.ErrorCode_SUCCESS}
+}
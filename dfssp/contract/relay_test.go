@@ -0,0 +1,58 @@
+package contract_test
+
+import (
+	"testing"
+	"time"
+
+	"dfss/dfssp/api"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/net/context"
+	"gopkg.in/mgo.v2/bson"
+)
+
+func TestRelay(t *testing.T) {
+	dropDataset()
+	createDataset()
+
+	c := createAsyncContract(time.Now().Add(time.Hour), "")
+	client := clientTest(t)
+
+	stream, err := client.Relay(context.Background(), &api.RelayRequest{ContractUuid: bson.NewObjectId().Hex(), SignatureUuid: "signature"})
+	assert.Equal(t, nil, err)
+	_, err = stream.Recv()
+	assert.NotNil(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stream, err = client.Relay(ctx, &api.RelayRequest{ContractUuid: c.ID.Hex(), SignatureUuid: "signature"})
+	assert.Equal(t, nil, err)
+	cancel()
+	_, err = stream.Recv()
+	assert.NotNil(t, err)
+}
+
+func TestRelayEvidence(t *testing.T) {
+	dropDataset()
+	createDataset()
+
+	c := createAsyncContract(time.Now().Add(time.Hour), "")
+	client := clientTest(t)
+
+	relay := func(contractUUID string, recipient, payload []byte) api.ErrorCode_Code {
+		errorCode, err := client.RelayEvidence(context.Background(), &api.Evidence{
+			ContractUuid:     contractUUID,
+			SignatureUuid:    "signature",
+			RecipientKeyHash: recipient,
+			Payload:          payload,
+		})
+		assert.Equal(t, nil, err)
+		return errorCode.Code
+	}
+
+	// user2 has not opened its relay
+	assert.Equal(t, api.ErrorCode_TIMEOUT, relay(c.ID.Hex(), user2.CertHash, []byte{0x01}))
+	assert.Equal(t, api.ErrorCode_INVARG, relay(c.ID.Hex(), user1.CertHash, []byte{0x01}))
+	assert.Equal(t, api.ErrorCode_INVARG, relay(c.ID.Hex(), user3.CertHash, []byte{0x01}))
+	assert.Equal(t, api.ErrorCode_INVARG, relay(c.ID.Hex(), user2.CertHash, nil))
+	assert.Equal(t, api.ErrorCode_BADAUTH, relay(bson.NewObjectId().Hex(), user2.CertHash, []byte{0x01}))
+	assert.Equal(t, api.ErrorCode_BADAUTH, relay("bad", user2.CertHash, []byte{0x01}))
+}
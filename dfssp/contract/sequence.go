@@ -1,12 +1,159 @@
 package contract
 
-// GenerateSignSequence for the contract signature
+import (
+	"errors"
+	"sort"
+)
+
+// SequenceGenerator builds the signing sequence of a contract.
+//
+// A sequence is an array of integers refering to the User array of the contract.
+// Longer sequences require more promise rounds, but each signer is involved more often in the protocol.
+type SequenceGenerator interface {
+	// Generate returns the signing sequence for n signers.
+	Generate(n int) []uint32
+}
+
+// Names of the built-in sequence generators, as stored in contracts and sealed in LaunchSignature
+const (
+	SequenceSquared = "squared" // n rounds of every signer, n² steps
+	SequenceCompact = "compact" // n-1 rounds of every signer and a final step, n²-n+1 steps
+	SequenceOptimal = "optimal" // shortest known sequence
+)
+
+// DefaultSequenceGenerator is used for contracts that do not specify any generator.
+const DefaultSequenceGenerator = SequenceSquared
+
+var sequenceGenerators = map[string]SequenceGenerator{
+	SequenceSquared: SquaredGenerator{},
+	SequenceCompact: CompactGenerator{},
+	SequenceOptimal: OptimalGenerator{},
+}
+
+// GetSequenceGenerator returns the built-in generator named name.
+// An empty name refers to the default generator.
+func GetSequenceGenerator(name string) (SequenceGenerator, error) {
+	if name == "" {
+		name = DefaultSequenceGenerator
+	}
+	g, ok := sequenceGenerators[name]
+	if !ok {
+		return nil, errors.New("Unknown sequence generator " + name)
+	}
+	return g, nil
+}
+
+// SequenceGenerators returns the names of the built-in generators, in alphabetical order.
+func SequenceGenerators() []string {
+	names := make([]string, 0, len(sequenceGenerators))
+	for name := range sequenceGenerators {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// GenerateSignSequence for the contract signature, using the default generator.
 //
 // The generated sequence is an array of integers refering to the User array.
 func GenerateSignSequence(n int) []uint32 {
 	return SquaredSignEngine(uint32(n))
 }
 
+// SquaredGenerator is the historical generator, see SquaredSignEngine.
+type SquaredGenerator struct{}
+
+// Generate implements SequenceGenerator.
+func (SquaredGenerator) Generate(n int) []uint32 {
+	return SquaredSignEngine(uint32(n))
+}
+
+// CompactGenerator repeats every signer n-1 times, and ends with the first signer.
+// The result is the shortest simple construction containing every permutation of the signers.
+//
+// With the resolve protocol of the ttp, the sequence remains fair against groups of up to n-2 dishonest signers,
+// see common.CheckFairness in the client.
+type CompactGenerator struct{}
+
+// Generate implements SequenceGenerator.
+func (CompactGenerator) Generate(n int) []uint32 {
+	if n < 2 {
+		return SquaredSignEngine(uint32(n))
+	}
+	sequence := SquaredSignEngine(uint32(n))[:n*(n-1)]
+	return append(sequence, 0)
+}
+
+// optimalSequences are sequences of minimal length containing every permutation of the signers, found by exhaustive search.
+// Their length is n²-2n+4 for n >= 3.
+var optimalSequences = [][]uint32{
+	{},
+	{0},
+	{0, 1, 0},
+	{0, 1, 2, 0, 1, 2, 0},
+	{0, 1, 2, 3, 0, 1, 2, 0, 3, 1, 0, 2},
+	{0, 1, 2, 3, 4, 0, 1, 2, 3, 0, 4, 1, 2, 0, 3, 1, 2, 4, 0},
+	{0, 1, 2, 3, 4, 5, 0, 1, 2, 3, 4, 0, 5, 1, 2, 3, 0, 4, 1, 2, 5, 0, 3, 1, 2, 4, 0, 5},
+}
+
+// OptimalGenerator returns the shortest known sequence containing every permutation of the signers.
+//
+// Sequences are of minimal length up to 6 signers. No general construction of minimal length is known,
+// so the CompactGenerator is used for larger groups.
+//
+// Such sequences are not fair against every group of dishonest signers with the resolve protocol of the ttp,
+// they are meant for large groups of signers trusting each other. See common.CheckFairness in the client.
+type OptimalGenerator struct{}
+
+// Generate implements SequenceGenerator.
+func (OptimalGenerator) Generate(n int) []uint32 {
+	if n < len(optimalSequences) {
+		return append([]uint32{}, optimalSequences[n]...)
+	}
+	return CompactGenerator{}.Generate(n)
+}
+
+// ContainsAllPermutations returns true if every permutation of the n signers is a subsequence of sequence.
+// It is a necessary condition for a sequence to provide fairness to every signer.
+//
+// The complexity is O(n*2^n), it should not be used with large groups of signers.
+func ContainsAllPermutations(sequence []uint32, n int) bool {
+	if n > 24 {
+		return false
+	}
+
+	// next[i][x] is the index of the first occurence of x at or after i, or len(sequence) if none
+	next := make([][]int, len(sequence)+1)
+	next[len(sequence)] = make([]int, n)
+	for x := range next[len(sequence)] {
+		next[len(sequence)][x] = len(sequence)
+	}
+	for i := len(sequence) - 1; i >= 0; i-- {
+		next[i] = append([]int{}, next[i+1]...)
+		if int(sequence[i]) < n {
+			next[i][sequence[i]] = i
+		}
+	}
+
+	// end[s] is the length of the shortest prefix containing every permutation of the subset s
+	end := make([]int, 1<<uint(n))
+	for s := 1; s < len(end); s++ {
+		for x := 0; x < n; x++ {
+			if s&(1<<uint(x)) == 0 {
+				continue
+			}
+			i := next[end[s^(1<<uint(x))]][x]
+			if i == len(sequence) {
+				return false
+			}
+			if i+1 > end[s] {
+				end[s] = i + 1
+			}
+		}
+	}
+	return true
+}
+
 // SquaredSignEngine is a basic ^2 engine for sequence generation
 func SquaredSignEngine(n uint32) []uint32 {
 	sequence := make([]uint32, n*n)
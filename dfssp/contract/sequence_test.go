@@ -26,3 +26,42 @@ func BenchmarkSquaredEngineSlice(b *testing.B) {
 		_ = contract.SquaredSignEngineSlice(10)
 	}
 }
+
+func TestSequenceGenerators(t *testing.T) {
+	assert.Equal(t, []string{"compact", "optimal", "squared"}, contract.SequenceGenerators())
+
+	g, err := contract.GetSequenceGenerator("")
+	assert.Nil(t, err)
+	assert.Equal(t, refSeq, g.Generate(3))
+
+	_, err = contract.GetSequenceGenerator("unknown")
+	assert.NotNil(t, err)
+
+	g, _ = contract.GetSequenceGenerator(contract.SequenceCompact)
+	assert.Equal(t, []uint32{0, 1, 2, 0, 1, 2, 0}, g.Generate(3))
+
+	g, _ = contract.GetSequenceGenerator(contract.SequenceOptimal)
+	assert.Equal(t, 12, len(g.Generate(4)))
+	assert.Equal(t, 28, len(g.Generate(6)))
+	assert.Equal(t, 43, len(g.Generate(7)))
+}
+
+func TestGeneratorsFairness(t *testing.T) {
+	for _, name := range contract.SequenceGenerators() {
+		g, _ := contract.GetSequenceGenerator(name)
+		for n := 1; n <= 8; n++ {
+			sequence := g.Generate(n)
+			assert.True(t, contract.ContainsAllPermutations(sequence, n), "%s generator, %d signers", name, n)
+			for i := 1; i < len(sequence); i++ {
+				assert.NotEqual(t, sequence[i-1], sequence[i], "%s generator, %d signers", name, n)
+			}
+		}
+	}
+}
+
+func TestContainsAllPermutations(t *testing.T) {
+	assert.True(t, contract.ContainsAllPermutations([]uint32{0, 1, 0}, 2))
+	assert.False(t, contract.ContainsAllPermutations([]uint32{0, 1}, 2))
+	assert.False(t, contract.ContainsAllPermutations([]uint32{0, 1, 2, 0, 1, 2}, 3))
+	assert.False(t, contract.ContainsAllPermutations([]uint32{0, 1, 2, 3, 0, 1, 2, 3, 0, 1, 2}, 4)) // shorter than the optimal one
+}
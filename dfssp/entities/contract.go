@@ -2,6 +2,7 @@
 package entities
 
 import (
+	"bytes"
 	"time"
 
 	"dfss/mgdb"
@@ -31,6 +32,14 @@ type Contract struct {
 	Ready   bool          `key:"ready" bson:"ready"`
 	File    *File         `key:"file" bson:"file"`
 	Signers []Signer      `key:"signers" bson:"signers"`
+
+	SequenceGenerator string `key:"sequenceGenerator" bson:"sequenceGenerator"` // Name of the signing sequence generator, empty for the default one
+
+	Deadline      time.Time `key:"deadline" bson:"deadline"`           // Deadline of an asynchronous signature, zero for a synchronous one
+	SignatureUUID string    `key:"signatureUuid" bson:"signatureUuid"` // UUID of the signature of an asynchronous contract, once launched
+	Launch        []byte    `key:"launch" bson:"launch"`               // Sealed launch signal of an asynchronous contract, once launched
+
+	Launched bool `key:"launched" bson:"launched"` // True once a signature of the contract has been launched, its signers cannot change anymore
 }
 
 // NewContract : Creates a new contract
@@ -60,6 +69,24 @@ func (c *Contract) AddSigner(id *bson.ObjectId, email string, hash []byte) {
 	c.Signers = append(c.Signers, *signer)
 }
 
+// IsAsync returns true if the signers of the contract exchange their evidence through the mailboxes of the platform.
+func (c *Contract) IsAsync() bool {
+	return !c.Deadline.IsZero()
+}
+
+// GetSigner returns the index of the signer owning a certificate hash, or -1 if there is no such signer.
+func (c *Contract) GetSigner(hash []byte) int {
+	if len(hash) == 0 {
+		return -1
+	}
+	for i, s := range c.Signers {
+		if bytes.Equal(s.Hash, hash) {
+			return i
+		}
+	}
+	return -1
+}
+
 // GetHashChain returns the ordered slice of signers hashes.
 // It's used to check the dfss file if needed.
 func (c *Contract) GetHashChain() [][]byte {
@@ -96,6 +123,42 @@ func (r *ContractRepository) GetWaitingForUser(email string) ([]Contract, error)
 	return res, err
 }
 
+// GetForSigner returns every contract containing a specific signer
+func (r *ContractRepository) GetForSigner(signerHash []byte) ([]Contract, error) {
+	var res []Contract
+	err := r.Collection.FindAll(bson.M{
+		"signers": bson.M{
+			"$elemMatch": bson.M{"hash": signerHash},
+		},
+	}, &res)
+	return res, err
+}
+
+// GetPendingForSigner returns every contract containing a specific signer, whose signature has not been launched yet
+func (r *ContractRepository) GetPendingForSigner(signerHash []byte) ([]Contract, error) {
+	var res []Contract
+	err := r.Collection.FindAll(bson.M{
+		"launched": bson.M{"$ne": true},
+		"signers": bson.M{
+			"$elemMatch": bson.M{"hash": signerHash},
+		},
+	}, &res)
+	return res, err
+}
+
+// ReplaceSignerHash replaces the certificate hash of a signer in a contract, unless its signature has been launched meanwhile.
+// It returns true if the contract has been updated.
+func (r *ContractRepository) ReplaceSignerHash(contractUUID bson.ObjectId, previousHash, newHash []byte) (bool, error) {
+	n, err := r.Collection.UpdateAll(bson.M{
+		"_id":          contractUUID,
+		"launched":     bson.M{"$ne": true},
+		"signers.hash": previousHash,
+	}, bson.M{"$set": bson.M{
+		"signers.$.hash": newHash,
+	}})
+	return n == 1, err
+}
+
 // GetWithSigner returns the contract corresponding to an UUID and containing a specific signer, or nil if no contract matches.
 func (r *ContractRepository) GetWithSigner(signerHash []byte, contractUUID bson.ObjectId) (contract *Contract, err error) {
 	contract = new(Contract)
@@ -113,3 +176,42 @@ func (r *ContractRepository) GetWithSigner(signerHash []byte, contractUUID bson.
 	}
 	return
 }
+
+// GetBySignature returns the asynchronous contract launched with a signature UUID, or nil if no contract matches.
+func (r *ContractRepository) GetBySignature(signatureUUID string) (contract *Contract, err error) {
+	contract = new(Contract)
+	err = r.Collection.Collection.Find(bson.M{"signatureUuid": signatureUUID}).One(contract)
+
+	if err == mgo.ErrNotFound {
+		contract = nil
+		err = nil
+		return
+	}
+	return
+}
+
+// SetLaunch stores the launch signal of an asynchronous contract, unless another one has already been stored.
+// It returns true if the provided signal has been stored.
+func (r *ContractRepository) SetLaunch(contractUUID bson.ObjectId, signatureUUID string, launch []byte) (bool, error) {
+	n, err := r.Collection.UpdateAll(bson.M{
+		"_id":           contractUUID,
+		"signatureUuid": "",
+	}, bson.M{"$set": bson.M{
+		"signatureUuid": signatureUUID,
+		"launch":        launch,
+		"launched":      true,
+	}})
+	return n == 1, err
+}
+
+// SetLaunched records that a signature of a synchronous contract has been launched.
+func (r *ContractRepository) SetLaunched(contractUUID bson.ObjectId) error {
+	_, err := r.Collection.UpdateAll(bson.M{"_id": contractUUID}, bson.M{"$set": bson.M{"launched": true}})
+	return err
+}
+
+// HasDocument returns true if a contract refers to the document of a hash.
+func (r *ContractRepository) HasDocument(hash []byte) (bool, error) {
+	count, err := r.Collection.Collection.Find(bson.M{"file.hash": hash}).Count()
+	return count > 0, err
+}
@@ -0,0 +1,69 @@
+package entities
+
+import (
+	"time"
+
+	"dfss/mgdb"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// Evidence : An encrypted promise or signature, kept by the platform until its recipient fetches it
+type Evidence struct {
+	ID            bson.ObjectId `key:"_id" bson:"_id"`                     // Internal id of an Evidence
+	Date          time.Time     `key:"date" bson:"date"`                   // Reception date of the Evidence
+	SignatureUUID string        `key:"signatureUuid" bson:"signatureUuid"` // UUID of the asynchronous signature
+	Sender        []byte        `key:"sender" bson:"sender"`               // Certificate hash of the sender
+	Recipient     []byte        `key:"recipient" bson:"recipient"`         // Certificate hash of the recipient
+	Payload       []byte        `key:"payload" bson:"payload"`             // Evidence encrypted for the recipient
+}
+
+// NewEvidence : Creates a new Evidence
+func NewEvidence(signatureUUID string, sender, recipient, payload []byte) *Evidence {
+	return &Evidence{
+		ID:            bson.NewObjectId(),
+		Date:          time.Now().UTC(),
+		SignatureUUID: signatureUUID,
+		Sender:        sender,
+		Recipient:     recipient,
+		Payload:       payload,
+	}
+}
+
+// EvidenceRepository : Holds the mailboxes of the asynchronous signatures
+type EvidenceRepository struct {
+	Collection *mgdb.MongoCollection
+}
+
+// NewEvidenceRepository : Creates a new evidence repository from the given connection
+func NewEvidenceRepository(collection *mgdb.MongoCollection) *EvidenceRepository {
+	return &EvidenceRepository{
+		collection,
+	}
+}
+
+// Take returns and removes the evidence waiting for a recipient in the mailbox of a signature, in arrival order
+func (r *EvidenceRepository) Take(signatureUUID string, recipient []byte) ([]Evidence, error) {
+	var res []Evidence
+	err := r.Collection.Collection.Find(bson.M{
+		"signatureUuid": signatureUUID,
+		"recipient":     recipient,
+	}).Sort("date", "_id").All(&res)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, e := range res {
+		if _, err = r.Collection.DeleteByID(e); err != nil {
+			return nil, err
+		}
+	}
+	return res, nil
+}
+
+// Count returns the number of evidence waiting for a recipient in the mailbox of a signature
+func (r *EvidenceRepository) Count(signatureUUID string, recipient []byte) (int, error) {
+	return r.Collection.Collection.Find(bson.M{
+		"signatureUuid": signatureUUID,
+		"recipient":     recipient,
+	}).Count()
+}
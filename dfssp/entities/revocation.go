@@ -0,0 +1,27 @@
+package entities
+
+import (
+	"time"
+
+	"gopkg.in/mgo.v2/bson"
+)
+
+// Revocation : Certificate revoked by the platform, published in the certificate revocation list
+type Revocation struct {
+	ID       bson.ObjectId `key:"_id" bson:"_id"`           // Internal id of a Revocation
+	Serial   string        `key:"serial" bson:"serial"`     // Serial number of the revoked certificate, in base 10
+	CertHash []byte        `key:"certHash" bson:"certHash"` // Hash of the revoked certificate
+	Email    string        `key:"email" bson:"email"`       // Email of the owner of the revoked certificate
+	Date     time.Time     `key:"date" bson:"date"`         // Time of revocation, in the future while the revocation is pending
+
+	ReplacedBy []byte `key:"replacedBy" bson:"replacedBy"` // Hash of the renewed certificate, whose first use makes the revocation effective
+}
+
+// NewRevocation : Creates a new Revocation for the provided certificate serial number
+func NewRevocation(serial string) *Revocation {
+	return &Revocation{
+		ID:     bson.NewObjectId(),
+		Serial: serial,
+		Date:   time.Now().UTC(),
+	}
+}
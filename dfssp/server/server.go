@@ -4,6 +4,8 @@ package server
 import (
 	"fmt"
 	"os"
+	"path/filepath"
+	"time"
 
 	"dfss/auth"
 	dAPI "dfss/dfssd/api"
@@ -11,6 +13,7 @@ import (
 	"dfss/dfssp/authority"
 	"dfss/dfssp/common"
 	"dfss/dfssp/contract"
+	"dfss/dfssp/storage"
 	"dfss/dfssp/user"
 	"dfss/mgdb"
 	"dfss/net"
@@ -20,10 +23,12 @@ import (
 )
 
 type platformServer struct {
-	Pid   *authority.PlatformID
-	DB    *mgdb.MongoManager
-	Rooms *common.WaitingGroupMap
-	TTPs  *authority.TTPHolder
+	Pid       *authority.PlatformID
+	DB        *mgdb.MongoManager
+	Rooms     *common.WaitingGroupMap
+	Relays    *common.RelayMap
+	TTPs      *authority.TTPHolder
+	Documents storage.Storage
 }
 
 // Register handler
@@ -44,7 +49,26 @@ func (s *platformServer) Auth(ctx context.Context, in *api.AuthRequest) (*api.Re
 //
 // Handle incoming UnregisterRequest messages
 func (s *platformServer) Unregister(ctx context.Context, in *api.Empty) (*api.ErrorCode, error) {
-	return user.Unregister(s.DB, net.GetClientHash(&ctx)), nil
+	res := user.Unregister(s.DB, net.GetClientHash(&ctx))
+	if res.Code == api.ErrorCode_SUCCESS {
+		if err := s.updateRevocationList(); err != nil {
+			dAPI.DLog("unable to update revocation list: " + err.Error())
+		}
+	}
+	return res, nil
+}
+
+// Renew handler
+//
+// Handle incoming RenewRequest messages
+func (s *platformServer) Renew(ctx context.Context, in *api.RenewRequest) (*api.RegisteredUser, error) {
+	res, err := user.Renew(s.Pid, s.DB, net.GetClientHash(&ctx), in)
+	if err == nil {
+		if err := s.updateRevocationList(); err != nil {
+			dAPI.DLog("unable to update revocation list: " + err.Error())
+		}
+	}
+	return res, err
 }
 
 // PostContract handler
@@ -56,7 +80,11 @@ func (s *platformServer) PostContract(ctx context.Context, in *api.PostContractR
 		return &api.ErrorCode{Code: api.ErrorCode_BADAUTH}, nil
 	}
 
-	builder := contract.NewContractBuilder(s.DB, in)
+	if errorCode := contract.CheckHostedDocument(s.Documents, in); errorCode != nil {
+		return errorCode, nil
+	}
+
+	builder := contract.NewContractBuilder(s.Pid.Pkey, s.DB, in)
 	return builder.Execute(), nil
 }
 
@@ -68,7 +96,7 @@ func (s *platformServer) GetContract(ctx context.Context, in *api.GetContractReq
 	if hash == nil {
 		return &api.Contract{ErrorCode: &api.ErrorCode{Code: api.ErrorCode_BADAUTH}}, nil
 	}
-	return contract.Fetch(s.DB, in.Uuid, hash), nil
+	return contract.Fetch(s.Pid.Pkey, s.DB, in.Uuid, hash), nil
 }
 
 // JoinSignature handler
@@ -97,23 +125,141 @@ func (s *platformServer) ReadySign(ctx context.Context, in *api.ReadySignRequest
 		return &api.LaunchSignature{ErrorCode: &api.ErrorCode{Code: api.ErrorCode_BADAUTH}}, nil
 	}
 
-	signal := contract.ReadySign(s.DB, s.Rooms, &ctx, in)
-	if signal.ErrorCode.Code == api.ErrorCode_SUCCESS {
-		signal.Ttp = s.TTPs.Get() // Assign a ttp to this signature, if any available
-		sealedSignal := *signal
-		sealedSignal.ErrorCode = nil
-		sealedSignal.Seal = nil
-		var err error
-		signal.Seal, err = auth.SignStructure(s.Pid.Pkey, sealedSignal)
-		if err != nil {
-			return &api.LaunchSignature{ErrorCode: &api.ErrorCode{Code: api.ErrorCode_INTERR}}, nil
-		}
+	if signal := contract.ReadySignAsync(s.DB, &ctx, in, s.sealSignal); signal != nil {
+		dAPI.DLog("async launch for " + cn)
+		return signal, nil
 	}
 
+	signal := contract.ReadySign(s.DB, s.Rooms, &ctx, in, s.sealSignal)
+
 	dAPI.DLog("sync with " + cn)
 	return signal, nil
 }
 
+// unaryInterceptor confirms the renewal of the client certificate before every unary handler, see confirmRenewal
+func (s *platformServer) unaryInterceptor(ctx context.Context, in interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	s.confirmRenewal(ctx)
+	return handler(ctx, in)
+}
+
+// streamInterceptor confirms the renewal of the client certificate before every stream handler, see confirmRenewal
+func (s *platformServer) streamInterceptor(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	s.confirmRenewal(stream.Context())
+	return handler(srv, stream)
+}
+
+// confirmRenewal revokes the previous certificate of the client, if it is the first use of its renewed one.
+// Nothing is done for unauthenticated clients.
+func (s *platformServer) confirmRenewal(ctx context.Context) {
+	revoked, err := user.ConfirmRenewal(s.DB, net.GetClientHash(&ctx))
+	if err != nil {
+		dAPI.DLog("unable to confirm renewal: " + err.Error())
+		return
+	}
+	if revoked {
+		if err := s.updateRevocationList(); err != nil {
+			dAPI.DLog("unable to update revocation list: " + err.Error())
+		}
+	}
+}
+
+// sealSignal assigns a ttp to a signature, if any available, and seals its launch signal.
+// It is called once per signature, the same launch signal being sent to every signer.
+func (s *platformServer) sealSignal(signal *api.LaunchSignature) error {
+	signal.Ttp = s.TTPs.Get()
+	sealedSignal := *signal
+	sealedSignal.ErrorCode = nil
+	sealedSignal.Seal = nil
+	sealedSignal.Certificate = nil
+	var err error
+	signal.Seal, err = auth.SignStructure(s.Pid.Pkey, sealedSignal)
+	return err
+}
+
+// PostEvidence handler
+//
+// Handle incoming Evidence messages
+func (s *platformServer) PostEvidence(ctx context.Context, in *api.Evidence) (*api.ErrorCode, error) {
+	return contract.PostEvidence(s.DB, &ctx, in), nil
+}
+
+// FetchEvidence handler
+//
+// Handle incoming FetchEvidenceRequest messages
+func (s *platformServer) FetchEvidence(ctx context.Context, in *api.FetchEvidenceRequest) (*api.EvidenceList, error) {
+	return contract.FetchEvidence(s.DB, &ctx, in), nil
+}
+
+// Relay handler
+//
+// Handle incoming RelayRequest messages
+func (s *platformServer) Relay(in *api.RelayRequest, stream api.Platform_RelayServer) error {
+	return contract.Relay(s.DB, s.Relays, in, stream)
+}
+
+// RelayEvidence handler
+//
+// Handle incoming relayed Evidence messages
+func (s *platformServer) RelayEvidence(ctx context.Context, in *api.Evidence) (*api.ErrorCode, error) {
+	return contract.RelayEvidence(s.DB, s.Relays, &ctx, in), nil
+}
+
+// GetRevocationList handler
+//
+// Handle incoming GetRevocationList messages
+func (s *platformServer) GetRevocationList(ctx context.Context, in *api.Empty) (*api.RevocationList, error) {
+	crl, err := user.GetRevocationList(s.Pid, s.DB)
+	if err != nil {
+		return &api.RevocationList{ErrorCode: &api.ErrorCode{Code: api.ErrorCode_INTERR}}, nil
+	}
+	return &api.RevocationList{ErrorCode: &api.ErrorCode{Code: api.ErrorCode_SUCCESS}, Crl: crl}, nil
+}
+
+// updateRevocationList makes the platform reject the revoked certificates, as any other peer
+func (s *platformServer) updateRevocationList() error {
+	crl, err := user.GetRevocationList(s.Pid, s.DB)
+	if err != nil {
+		return err
+	}
+
+	list, err := auth.PEMToRevocationList(crl, s.Pid.RootCA)
+	if err != nil {
+		return err
+	}
+
+	net.SetRevocationList(list)
+	return nil
+}
+
+// PostDocument handler
+//
+// Handle incoming DocumentChunk streams, uploading a document before its contract
+func (s *platformServer) PostDocument(stream api.Platform_PostDocumentServer) error {
+	ctx := stream.Context()
+	if len(net.GetCN(&ctx)) == 0 {
+		return stream.SendAndClose(&api.ErrorCode{Code: api.ErrorCode_BADAUTH})
+	}
+	return stream.SendAndClose(contract.PostDocument(s.Documents, stream))
+}
+
+// expireDocuments periodically deletes the uploaded documents no contract refers to, see contract.ExpireDocuments
+func (s *platformServer) expireDocuments() {
+	ticker := time.NewTicker(time.Hour)
+	for range ticker.C {
+		if err := contract.ExpireDocuments(s.DB, s.Documents); err != nil {
+			fmt.Println("An error occured during the expiration of the uploaded documents:", err)
+		}
+	}
+}
+
+// GetDocument handler
+//
+// Handle incoming GetContractRequest messages for hosted documents
+func (s *platformServer) GetDocument(in *api.GetContractRequest, stream api.Platform_GetDocumentServer) error {
+	ctx := stream.Context()
+	return contract.GetDocument(s.DB, s.Documents, in, net.GetClientHash(&ctx), stream)
+}
+
 // GetServer returns the GRPC server associated with the platform
 func GetServer() *grpc.Server {
 	pid, err := authority.Start(viper.GetString("path"))
@@ -137,12 +283,45 @@ func GetServer() *grpc.Server {
 		fmt.Println("Warning: no TTP loaded. See `dfssp ttp --help`.")
 	}
 
-	server := net.NewServer(pid.RootCA, pid.Pkey, pid.RootCA)
-	api.RegisterPlatformServer(server, &platformServer{
-		Pid:   pid,
-		DB:    dbManager,
-		Rooms: common.NewWaitingGroupMap(),
-		TTPs:  ttpholder,
-	})
+	dir := viper.GetString("documents")
+	if dir == "" {
+		dir = filepath.Join(viper.GetString("path"), "documents")
+	}
+	documents, err := storage.New(viper.GetString("storage"), dir, dbManager.Database)
+	if err != nil {
+		fmt.Println("An error occured during the document storage initialization:", err)
+		os.Exit(1)
+	}
+	if size := viper.GetInt("max_document_size"); size > 0 {
+		contract.MaxDocumentSize = int64(size) << 20
+	}
+	if hours := viper.GetInt("document_expiration"); hours > 0 {
+		contract.DocumentExpiration = time.Duration(hours) * time.Hour
+	}
+
+	platform := &platformServer{
+		Pid:       pid,
+		DB:        dbManager,
+		Rooms:     common.NewWaitingGroupMap(),
+		Relays:    common.NewRelayMap(),
+		TTPs:      ttpholder,
+		Documents: documents,
+	}
+
+	if documents != nil {
+		go platform.expireDocuments()
+	}
+
+	err = platform.updateRevocationList()
+	if err != nil {
+		fmt.Println("An error occured during the revocation list generation:", err)
+		os.Exit(1)
+	}
+
+	server := net.NewServer(pid.RootCA, pid.Pkey, pid.RootCA,
+		grpc.UnaryInterceptor(platform.unaryInterceptor),
+		grpc.StreamInterceptor(platform.streamInterceptor),
+	)
+	api.RegisterPlatformServer(server, platform)
 	return server
 }
@@ -0,0 +1,118 @@
+package storage
+
+import (
+	"bytes"
+	"crypto/sha512"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Disk stores documents as files in a directory, named after their hexadecimal hash.
+type Disk struct {
+	dir string
+}
+
+// NewDisk creates the directory if needed, and returns a disk storage over it.
+func NewDisk(dir string) (*Disk, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, err
+	}
+	return &Disk{dir: dir}, nil
+}
+
+// Put implements Storage.
+// The document is written to a temporary file, which is renamed once its hash has been checked.
+func (d *Disk) Put(hash []byte, r io.Reader) error {
+	filename, err := name(hash)
+	if err != nil {
+		return err
+	}
+
+	tmp, err := ioutil.TempFile(d.dir, ".upload-")
+	if err != nil {
+		return err
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }()
+
+	h := sha512.New()
+	_, err = io.Copy(io.MultiWriter(tmp, h), r)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(h.Sum(nil), hash) {
+		return ErrHashMismatch
+	}
+
+	path := filepath.Join(d.dir, filename)
+	if _, err = os.Stat(path); err == nil {
+		now := time.Now()
+		return os.Chtimes(path, now, now) // Already stored
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// Get implements Storage.
+func (d *Disk) Get(hash []byte) (io.ReadCloser, error) {
+	filename, err := name(hash)
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := os.Open(filepath.Join(d.dir, filename))
+	if os.IsNotExist(err) {
+		return nil, ErrNotFound
+	}
+	return f, err
+}
+
+// Exists implements Storage.
+func (d *Disk) Exists(hash []byte) (bool, error) {
+	filename, err := name(hash)
+	if err != nil {
+		return false, err
+	}
+
+	_, err = os.Stat(filepath.Join(d.dir, filename))
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// Delete implements Storage.
+func (d *Disk) Delete(hash []byte) error {
+	filename, err := name(hash)
+	if err != nil {
+		return err
+	}
+
+	err = os.Remove(filepath.Join(d.dir, filename))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	return err
+}
+
+// List implements Storage.
+// The storage date of a document is the modification time of its file.
+func (d *Disk) List(before time.Time) ([][]byte, error) {
+	files, err := ioutil.ReadDir(d.dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var hashes [][]byte
+	for _, file := range files {
+		hash := parseName(file.Name())
+		if hash != nil && file.ModTime().Before(before) {
+			hashes = append(hashes, hash)
+		}
+	}
+	return hashes, nil
+}
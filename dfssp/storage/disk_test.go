@@ -0,0 +1,89 @@
+package storage
+
+import (
+	"bytes"
+	"crypto/sha512"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDisk(t *testing.T) {
+	dir, _ := ioutil.TempDir("", "dfssp_storage")
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	store, err := New("disk", dir, nil)
+	assert.Nil(t, err)
+
+	document := []byte("a document to be signed")
+	hash := sha512.Sum512(document)
+
+	exists, err := store.Exists(hash[:])
+	assert.Nil(t, err)
+	assert.False(t, exists)
+
+	_, err = store.Get(hash[:])
+	assert.Equal(t, ErrNotFound, err)
+
+	// Bad hash
+	err = store.Put(hash[:], bytes.NewReader([]byte("another document")))
+	assert.Equal(t, ErrHashMismatch, err)
+	files, _ := ioutil.ReadDir(dir)
+	assert.Equal(t, 0, len(files))
+
+	err = store.Put(hash[:10], bytes.NewReader(document))
+	assert.NotNil(t, err)
+
+	// Good hash, twice
+	assert.Nil(t, store.Put(hash[:], bytes.NewReader(document)))
+	assert.Nil(t, store.Put(hash[:], bytes.NewReader(document)))
+	files, _ = ioutil.ReadDir(dir)
+	assert.Equal(t, 1, len(files))
+
+	exists, err = store.Exists(hash[:])
+	assert.Nil(t, err)
+	assert.True(t, exists)
+
+	r, err := store.Get(hash[:])
+	assert.Nil(t, err)
+	data, _ := ioutil.ReadAll(r)
+	_ = r.Close()
+	assert.Equal(t, document, data)
+
+	// Listing by storage date, refreshed by a new upload
+	hashes, err := store.List(time.Now().Add(time.Minute))
+	assert.Nil(t, err)
+	assert.Equal(t, [][]byte{hash[:]}, hashes)
+
+	past := time.Now().Add(-time.Hour)
+	_ = os.Chtimes(filepath.Join(dir, fmt.Sprintf("%x", hash)), past, past)
+	hashes, _ = store.List(time.Now().Add(-time.Minute))
+	assert.Equal(t, 1, len(hashes))
+	assert.Nil(t, store.Put(hash[:], bytes.NewReader(document)))
+	hashes, _ = store.List(time.Now().Add(-time.Minute))
+	assert.Equal(t, 0, len(hashes))
+
+	// Deletion, twice
+	assert.Nil(t, store.Delete(hash[:]))
+	assert.Nil(t, store.Delete(hash[:]))
+	exists, _ = store.Exists(hash[:])
+	assert.False(t, exists)
+
+	// Names cannot escape the directory
+	_, err = store.Get([]byte("../../etc/passwd"))
+	assert.NotNil(t, err)
+}
+
+func TestNew(t *testing.T) {
+	store, err := New("", "", nil)
+	assert.Nil(t, err)
+	assert.Nil(t, store)
+
+	_, err = New("cloud", "", nil)
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,110 @@
+package storage
+
+import (
+	"bytes"
+	"crypto/sha512"
+	"io"
+	"time"
+
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// gridFSPrefix is the prefix of the GridFS collections storing documents
+const gridFSPrefix = "documents"
+
+// GridFS stores documents in the GridFS of the platform database, named after their hexadecimal hash.
+type GridFS struct {
+	fs *mgo.GridFS
+}
+
+// NewGridFS returns a GridFS storage over a database.
+func NewGridFS(db *mgo.Database) *GridFS {
+	return &GridFS{fs: db.GridFS(gridFSPrefix)}
+}
+
+// Put implements Storage.
+// The upload is aborted if its hash does not match, so that nothing is stored.
+func (g *GridFS) Put(hash []byte, r io.Reader) error {
+	filename, err := name(hash)
+	if err != nil {
+		return err
+	}
+	if exists, err := g.Exists(hash); err != nil || exists {
+		if exists {
+			_, err = g.fs.Files.UpdateAll(bson.M{"filename": filename}, bson.M{"$set": bson.M{"uploadDate": time.Now()}})
+		}
+		return err
+	}
+
+	file, err := g.fs.Create(filename)
+	if err != nil {
+		return err
+	}
+
+	h := sha512.New()
+	_, err = io.Copy(io.MultiWriter(file, h), r)
+	if err == nil && !bytes.Equal(h.Sum(nil), hash) {
+		err = ErrHashMismatch
+	}
+	if err != nil {
+		file.Abort()
+		_ = file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// Get implements Storage.
+func (g *GridFS) Get(hash []byte) (io.ReadCloser, error) {
+	filename, err := name(hash)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := g.fs.Open(filename)
+	if err == mgo.ErrNotFound {
+		return nil, ErrNotFound
+	}
+	return file, err
+}
+
+// Exists implements Storage.
+func (g *GridFS) Exists(hash []byte) (bool, error) {
+	filename, err := name(hash)
+	if err != nil {
+		return false, err
+	}
+
+	count, err := g.fs.Find(bson.M{"filename": filename}).Count()
+	return count > 0, err
+}
+
+// Delete implements Storage.
+func (g *GridFS) Delete(hash []byte) error {
+	filename, err := name(hash)
+	if err != nil {
+		return err
+	}
+	return g.fs.Remove(filename)
+}
+
+// List implements Storage.
+// The storage date of a document is its GridFS upload date.
+func (g *GridFS) List(before time.Time) ([][]byte, error) {
+	var files []struct {
+		Filename string `bson:"filename"`
+	}
+	err := g.fs.Find(bson.M{"uploadDate": bson.M{"$lt": before}}).Select(bson.M{"filename": 1}).All(&files)
+	if err != nil {
+		return nil, err
+	}
+
+	var hashes [][]byte
+	for _, file := range files {
+		if hash := parseName(file.Filename); hash != nil {
+			hashes = append(hashes, hash)
+		}
+	}
+	return hashes, nil
+}
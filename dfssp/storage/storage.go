@@ -0,0 +1,66 @@
+// Package storage stores the documents hosted by the platform, identified by their SHA-512 hash.
+package storage
+
+import (
+	"crypto/sha512"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"gopkg.in/mgo.v2"
+)
+
+// ErrNotFound is returned when the requested document is not stored.
+var ErrNotFound = errors.New("document not found")
+
+// ErrHashMismatch is returned when a stored document does not match its announced hash.
+var ErrHashMismatch = errors.New("document does not match its hash")
+
+// Storage is a backend storing documents by hash.
+// Documents are immutable: storing the same hash twice keeps the first document, and only refreshes its storage date.
+type Storage interface {
+	// Put stores the document read from r, checking its SHA-512 hash once fully read.
+	// Nothing is stored if the reader fails or if the hash does not match.
+	Put(hash []byte, r io.Reader) error
+	// Get returns a reader over the stored document, or ErrNotFound.
+	Get(hash []byte) (io.ReadCloser, error)
+	// Exists returns true if the document is stored.
+	Exists(hash []byte) (bool, error)
+	// Delete removes the document, if it is stored.
+	Delete(hash []byte) error
+	// List returns the hashes of the documents stored before a date.
+	List(before time.Time) ([][]byte, error)
+}
+
+// New returns the storage backend of the given kind: "disk" stores documents in dir, "gridfs" in db.
+// An empty kind disables the storage and returns nil.
+func New(kind, dir string, db *mgo.Database) (Storage, error) {
+	switch kind {
+	case "":
+		return nil, nil
+	case "disk":
+		return NewDisk(dir)
+	case "gridfs":
+		return NewGridFS(db), nil
+	}
+	return nil, errors.New("unknown storage: " + kind)
+}
+
+// name returns the name of the document of a hash, or an error if the hash is invalid.
+func name(hash []byte) (string, error) {
+	if len(hash) != sha512.Size {
+		return "", errors.New("expecting a valid sha512 hash")
+	}
+	return fmt.Sprintf("%x", hash), nil
+}
+
+// parseName returns the hash of the document of a name, or nil if the name is not the one of a document.
+func parseName(filename string) []byte {
+	hash, err := hex.DecodeString(filename)
+	if err != nil || len(hash) != sha512.Size {
+		return nil
+	}
+	return hash
+}
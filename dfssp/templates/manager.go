@@ -26,6 +26,7 @@ func Init() {
 	_ = template.Must(tpl.Parse("{{define `invitation`}}" + invitation + "{{end}}"))
 	_ = template.Must(tpl.Parse("{{define `contractDetails`}}" + contractDetails + "{{end}}"))
 	_ = template.Must(tpl.Parse("{{define `verificationMail`}}" + verificationMail + "{{end}}"))
+	_ = template.Must(tpl.Parse("{{define `renewalMail`}}" + renewalMail + "{{end}}"))
 	ready = true
 
 }
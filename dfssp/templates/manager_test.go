@@ -2,6 +2,7 @@ package templates
 
 import (
 	"testing"
+	"time"
 
 	"dfss/dfssp/entities"
 	"github.com/stretchr/testify/assert"
@@ -44,3 +45,34 @@ The DFSS Platform
 	assert.Equal(t, nil, err)
 	assert.Equal(t, expected, s)
 }
+
+func TestGetRenewalMail(t *testing.T) {
+	mail := RenewalMail{
+		Expiration: time.Date(2017, 3, 14, 0, 0, 0, 0, time.UTC),
+		Contracts:  []string{"a.pdf", "b.pdf"},
+	}
+
+	s, err := Get("renewalMail", mail)
+
+	expected := `Dear Sir or Madam,
+
+The certificate of your DFSS account has been renewed.
+It is now valid until 2017-03-14.
+
+The following contracts awaiting signature have been updated with
+your new certificate, and sent again to their signers:
+
+  - a.pdf
+  - b.pdf
+
+If you did not ask for this renewal, please unregister as soon as
+possible, as your private key may have been compromised.
+
+Yours faithfully,
+
+The DFSS Platform
+`
+
+	assert.Equal(t, nil, err)
+	assert.Equal(t, expected, s)
+}
@@ -0,0 +1,25 @@
+package templates
+
+import "time"
+
+const renewalMail = `Dear Sir or Madam,
+
+The certificate of your DFSS account has been renewed.
+It is now valid until {{.Expiration.Format "2006-01-02"}}.
+{{if .Contracts}}
+The following contracts awaiting signature have been updated with
+your new certificate, and sent again to their signers:
+{{range .Contracts}}
+  - {{.}}{{end}}
+{{end}}
+If you did not ask for this renewal, please unregister as soon as
+possible, as your private key may have been compromised.
+
+{{template "signature"}}
+`
+
+// RenewalMail contains the information to be sent in the renewal mail
+type RenewalMail struct {
+	Expiration time.Time
+	Contracts  []string // Names of the updated contracts
+}
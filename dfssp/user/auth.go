@@ -1,7 +1,7 @@
 package user
 
 import (
-	"crypto/rsa"
+	"crypto"
 	"crypto/x509"
 	"errors"
 	"log"
@@ -32,7 +32,7 @@ func checkTokenTimeout(user *entities.User) error {
 // Gerenate the user's certificate and certificate hash according to the specified parameters
 //
 // This function should only be called AFTER checking the AuthRequest for validity
-func generateUserCert(csr string, parent *x509.Certificate, key *rsa.PrivateKey) ([]byte, []byte, error) {
+func generateUserCert(csr string, parent *x509.Certificate, key crypto.Signer) ([]byte, []byte, error) {
 	x509csr, err := auth.PEMToCertificateRequest([]byte(csr))
 	if err != nil {
 		return nil, nil, err
@@ -108,13 +108,13 @@ func Auth(pid *authority.PlatformID, manager *mgdb.MongoManager, in *api.AuthReq
 	}
 
 	// Update missed contracts in background
-	go launchMissedContracts(manager, &user)
+	go launchMissedContracts(pid, manager, &user)
 
 	// Returning the RegisteredUser message
 	return &api.RegisteredUser{ClientCert: user.Certificate}, nil
 }
 
-func launchMissedContracts(manager *mgdb.MongoManager, user *entities.User) {
+func launchMissedContracts(pid *authority.PlatformID, manager *mgdb.MongoManager, user *entities.User) {
 
 	repository := entities.NewContractRepository(manager.Get("contracts"))
 	contracts, err := repository.GetWaitingForUser(user.Email)
@@ -144,7 +144,7 @@ func launchMissedContracts(manager *mgdb.MongoManager, user *entities.User) {
 
 		if c.Ready {
 			// Send required mails
-			builder := contract.NewContractBuilder(manager, nil)
+			builder := contract.NewContractBuilder(pid.Pkey, manager, nil)
 			builder.Contract = &c
 			builder.SendNewContractMail()
 		}
@@ -1,13 +1,40 @@
 package user
 
 import (
+	"dfss/auth"
 	api "dfss/dfssp/api"
+	"dfss/dfssp/entities"
 	"dfss/mgdb"
 	"gopkg.in/mgo.v2/bson"
 )
 
-// Unregister delete a user based on the provided certificate hash
+// Unregister delete a user based on the provided certificate hash.
+// The certificate of the user, if any, is revoked and will appear in the next revocation lists,
+// as well as the previous certificate of a renewed user (see ConfirmRenewal).
 func Unregister(manager *mgdb.MongoManager, userCertificateHash []byte) *api.ErrorCode {
+	var users []entities.User
+	err := manager.Get("users").FindAll(bson.M{
+		"certHash": userCertificateHash,
+	}, &users)
+	if err != nil || len(users) == 0 {
+		return &api.ErrorCode{Code: api.ErrorCode_INVARG, Message: "No user matching provided certificate"}
+	}
+
+	for _, u := range users {
+		if u.Certificate == "" {
+			continue // authentication not completed, no certificate to revoke
+		}
+
+		err = revoke(manager, &u)
+		if err != nil {
+			return &api.ErrorCode{Code: api.ErrorCode_INTERR, Message: "Unable to revoke certificate"}
+		}
+	}
+
+	if _, err = ConfirmRenewal(manager, userCertificateHash); err != nil {
+		return &api.ErrorCode{Code: api.ErrorCode_INTERR, Message: "Unable to revoke certificate"}
+	}
+
 	count, err := manager.Get("users").DeleteAll(bson.M{
 		"certHash": userCertificateHash,
 	})
@@ -17,3 +44,18 @@ func Unregister(manager *mgdb.MongoManager, userCertificateHash []byte) *api.Err
 
 	return &api.ErrorCode{Code: api.ErrorCode_SUCCESS}
 }
+
+// revoke stores the certificate of the user in the revocations collection
+func revoke(manager *mgdb.MongoManager, user *entities.User) error {
+	cert, err := auth.PEMToCertificate([]byte(user.Certificate))
+	if err != nil {
+		return err
+	}
+
+	revocation := entities.NewRevocation(cert.SerialNumber.String())
+	revocation.CertHash = user.CertHash
+	revocation.Email = user.Email
+
+	_, err = manager.Get("revocations").Insert(revocation)
+	return err
+}
@@ -0,0 +1,212 @@
+package user
+
+import (
+	"bytes"
+	"errors"
+	"log"
+	"strings"
+	"time"
+
+	"dfss/auth"
+	"dfss/dfssp/api"
+	"dfss/dfssp/authority"
+	"dfss/dfssp/contract"
+	"dfss/dfssp/entities"
+	"dfss/dfssp/templates"
+	"dfss/mgdb"
+	"github.com/spf13/viper"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// Check if the renewal request has usable fields, and if it matches the identity of the user
+func checkRenewRequest(in *api.RenewRequest, user *entities.User) error {
+	if len(in.Request) == 0 {
+		return errors.New("Invalid request length")
+	}
+
+	if viper.GetInt("validity") < 1 {
+		return errors.New("Invalid validity duration")
+	}
+
+	csr, err := auth.PEMToCertificateRequest([]byte(in.Request))
+	if err != nil {
+		return err
+	}
+
+	err = csr.CheckSignature()
+	if err != nil {
+		return err
+	}
+
+	if !strings.EqualFold(csr.Subject.CommonName, user.Email) {
+		return errors.New("Certificate request does not match the user email")
+	}
+
+	return nil
+}
+
+// Renew issues a new certificate for an authenticated user, from a certificate request signed by its current or a new key.
+//
+// The previous certificate is revoked on the first use of the new one (see ConfirmRenewal), or after RenewalGracePeriod.
+// Meanwhile, it can still be used to sign already launched contracts, or to renew again if the new certificate is lost.
+// It is replaced by the new one in every contract of the user whose signature has not been launched yet.
+func Renew(pid *authority.PlatformID, manager *mgdb.MongoManager, certHash []byte, in *api.RenewRequest) (*api.RegisteredUser, error) {
+	if len(certHash) == 0 {
+		return nil, errors.New("Authentication required")
+	}
+
+	// Find the user in the database
+	user, err := findRenewingUser(manager, certHash)
+	if err != nil {
+		return nil, errors.New("No user matching provided certificate")
+	}
+
+	// Check the request validity
+	err = checkRenewRequest(in, user)
+	if err != nil {
+		return nil, err
+	}
+
+	// Generate the new certificate and hash
+	cert, newHash, err := generateUserCert(in.Request, pid.RootCA, pid.Pkey)
+	if err != nil {
+		return nil, err
+	}
+
+	previous := *user
+	user.Csr = in.Request
+	user.Certificate = string(cert)
+	user.CertHash = newHash
+	user.Expiration = time.Now().AddDate(0, 0, viper.GetInt("validity"))
+
+	// Updating the database
+	ok, err := manager.Get("users").UpdateByID(*user)
+	if !ok {
+		return nil, err
+	}
+
+	if bytes.Equal(previous.CertHash, certHash) {
+		err = revokeRenewed(manager, &previous, newHash)
+	} else {
+		// The certificate issued by the last renewal has never been used, and the older one is still pending revocation
+		err = revoke(manager, &previous)
+		if err == nil {
+			_, err = manager.Get("revocations").UpdateAll(bson.M{"replacedBy": previous.CertHash}, bson.M{"$set": bson.M{"replacedBy": newHash}})
+		}
+	}
+	if err != nil {
+		log.Println("Cannot revoke previous certificate of user", user.Email+":", err)
+	}
+
+	// Update contracts and notify signers in background
+	go updateRenewedContracts(pid, manager, user, previous.CertHash)
+
+	return &api.RegisteredUser{ClientCert: user.Certificate}, nil
+}
+
+// findRenewingUser returns the user owning a certificate, or the renewed user whose previous certificate it is,
+// as long as its revocation is pending.
+func findRenewingUser(manager *mgdb.MongoManager, certHash []byte) (*entities.User, error) {
+	user := new(entities.User)
+	err := manager.Get("users").Collection.Find(bson.M{
+		"certHash": certHash,
+	}).One(user)
+	if err == nil {
+		return user, nil
+	}
+
+	var pending entities.Revocation
+	err = manager.Get("revocations").Collection.Find(bson.M{
+		"certHash": certHash,
+		"date":     bson.M{"$gt": time.Now().UTC()},
+	}).One(&pending)
+	if err != nil {
+		return nil, err
+	}
+
+	err = manager.Get("users").Collection.Find(bson.M{
+		"certHash": pending.ReplacedBy,
+	}).One(user)
+	return user, err
+}
+
+// revokeRenewed stores the previous certificate of a renewed user in the revocations collection,
+// the revocation being pending until the first use of the new certificate, or the end of the grace period.
+func revokeRenewed(manager *mgdb.MongoManager, previous *entities.User, newHash []byte) error {
+	cert, err := auth.PEMToCertificate([]byte(previous.Certificate))
+	if err != nil {
+		return err
+	}
+
+	revocation := entities.NewRevocation(cert.SerialNumber.String())
+	revocation.CertHash = previous.CertHash
+	revocation.Email = previous.Email
+	revocation.Date = revocation.Date.Add(RenewalGracePeriod)
+	revocation.ReplacedBy = newHash
+
+	_, err = manager.Get("revocations").Insert(revocation)
+	return err
+}
+
+// updateRenewedContracts replaces the previous certificate hash of the user in its contracts whose signature has not been
+// launched yet, and sends the updated contract files to their signers.
+//
+// Launched contracts keep the sealed certificate hashes: their signature goes on with the previous certificate.
+func updateRenewedContracts(pid *authority.PlatformID, manager *mgdb.MongoManager, user *entities.User, previousHash []byte) {
+	repository := entities.NewContractRepository(manager.Get("contracts"))
+	contracts, err := repository.GetPendingForSigner(previousHash)
+	if err != nil {
+		log.Println("Cannot get contracts for renewed user", user.Email+":", err)
+	}
+
+	var names []string
+	for _, c := range contracts {
+		// Update contract in database, unless it has been launched meanwhile
+		ok, err := repository.ReplaceSignerHash(c.ID, previousHash, user.CertHash)
+		if err != nil {
+			log.Println("Cannot update contract", c.ID, "for renewed user", user.Email+":", err)
+			continue
+		}
+		if !ok {
+			continue
+		}
+		for i := range c.Signers {
+			if bytes.Equal(c.Signers[i].Hash, previousHash) {
+				c.Signers[i].Hash = user.CertHash
+			}
+		}
+
+		if c.Ready {
+			// Previous contract files are now outdated
+			builder := contract.NewContractBuilder(pid.Pkey, manager, nil)
+			builder.Contract = &c
+			builder.SendNewContractMail()
+			names = append(names, c.File.Name)
+		}
+	}
+
+	sendRenewalMail(user, names)
+}
+
+// sendRenewalMail notifies the user that its certificate has been renewed
+func sendRenewalMail(user *entities.User, contracts []string) {
+	conn := templates.MailConn()
+	if conn == nil {
+		return
+	}
+	defer func() { _ = conn.Close() }()
+
+	content, err := templates.Get("renewalMail", templates.RenewalMail{
+		Expiration: user.Expiration,
+		Contracts:  contracts,
+	})
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	err = conn.Send([]string{user.Email}, "[DFSS] Certificate renewal", content, nil, nil, nil)
+	if err != nil {
+		log.Println(err)
+	}
+}
@@ -0,0 +1,64 @@
+package user
+
+import (
+	"crypto/x509"
+	"errors"
+	"math/big"
+	"time"
+
+	"dfss/auth"
+	"dfss/dfssp/authority"
+	"dfss/dfssp/entities"
+	"dfss/mgdb"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// RevocationListValidity is the duration after which users should fetch a new revocation list.
+// It bounds the delay between the revocation of a certificate and its rejection by the other users.
+var RevocationListValidity = time.Hour
+
+// RenewalGracePeriod is the duration during which the previous certificate of a renewed user is still valid,
+// unless the new certificate is used before.
+var RenewalGracePeriod = 24 * time.Hour
+
+// GetRevocationList builds the current certificate revocation list, signed by the platform, as a PEM-encoded array of bytes.
+// Pending revocations of renewed certificates are not part of it.
+func GetRevocationList(pid *authority.PlatformID, manager *mgdb.MongoManager) ([]byte, error) {
+	var revocations []entities.Revocation
+	err := manager.Get("revocations").FindAll(bson.M{
+		"date": bson.M{"$lte": time.Now().UTC()},
+	}, &revocations)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]x509.RevocationListEntry, len(revocations))
+	for i, r := range revocations {
+		serial, ok := new(big.Int).SetString(r.Serial, 10)
+		if !ok {
+			return nil, errors.New("Invalid serial number in revocation " + r.ID.Hex())
+		}
+		entries[i] = x509.RevocationListEntry{
+			SerialNumber:   serial,
+			RevocationTime: r.Date,
+		}
+	}
+
+	// Effective revocations are never removed, so their count strictly increases with each new revocation
+	return auth.GetRevocationList(uint64(len(revocations))+1, entries, RevocationListValidity, pid.RootCA, pid.Pkey)
+}
+
+// ConfirmRenewal makes the pending revocation of the previous certificate of a renewed user effective,
+// as soon as the new certificate is used. It returns true if a revocation became effective.
+func ConfirmRenewal(manager *mgdb.MongoManager, certHash []byte) (bool, error) {
+	if len(certHash) == 0 {
+		return false, nil
+	}
+
+	now := time.Now().UTC()
+	n, err := manager.Get("revocations").UpdateAll(bson.M{
+		"replacedBy": certHash,
+		"date":       bson.M{"$gt": now},
+	}, bson.M{"$set": bson.M{"date": now}})
+	return n > 0, err
+}
@@ -1,7 +1,7 @@
 package user_test
 
 import (
-	"crypto/rsa"
+	"crypto"
 	"crypto/x509"
 	"fmt"
 	"io/ioutil"
@@ -9,9 +9,11 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"dfss/auth"
 	"dfss/dfssp/api"
+	"dfss/dfssp/authority"
 	"dfss/dfssp/entities"
 	"dfss/dfssp/server"
 	u "dfss/dfssp/user"
@@ -26,7 +28,7 @@ var (
 	mail          string
 	csr           []byte
 	rootCA        *x509.Certificate
-	rootKey, pkey *rsa.PrivateKey
+	rootKey, pkey crypto.Signer
 )
 
 const (
@@ -93,8 +95,10 @@ func TestMain(m *testing.M) {
 
 	// Run
 	err = collection.Drop()
+	_ = manager.Get("revocations").Drop()
 	code := m.Run()
 	err = collection.Drop()
+	_ = manager.Get("revocations").Drop()
 
 	if err != nil {
 		fmt.Println("An error occurred while droping the collection")
@@ -142,6 +146,259 @@ func TestUnregisterUser(t *testing.T) {
 	}
 }
 
+func TestUnregisterRevokesCertificate(t *testing.T) {
+	x509csr, _ := auth.PEMToCertificateRequest(csr)
+	certPem, _ := auth.GetCertificate(1, 42, x509csr, rootCA, rootKey)
+	cert, _ := auth.PEMToCertificate(certPem)
+	hash := auth.GetCertificateHash(cert)
+
+	user := entities.NewUser()
+	user.Email = "dfss3@mpcs.tk"
+	user.CertHash = hash
+	user.Certificate = string(certPem)
+
+	_, err = repository.Collection.Insert(user)
+	if err != nil {
+		t.Fatal("An error occurred while inserting the user")
+	}
+
+	response := u.Unregister(manager, hash)
+	if response.Code != api.ErrorCode_SUCCESS {
+		t.Fatal("An error occured while deleting the user:" + response.Message)
+	}
+
+	var revocations []entities.Revocation
+	err = manager.Get("revocations").FindAll(bson.M{"certHash": hash}, &revocations)
+	if err != nil || len(revocations) != 1 || revocations[0].Serial != "42" {
+		t.Fatal("Certificate has not been revoked:", revocations, err)
+	}
+
+	crl, err := u.GetRevocationList(&authority.PlatformID{Pkey: rootKey, RootCA: rootCA}, manager)
+	if err != nil {
+		t.Fatal("Unable to build revocation list:", err)
+	}
+	list, err := auth.PEMToRevocationList(crl, rootCA)
+	if err != nil {
+		t.Fatal("Invalid revocation list:", err)
+	}
+
+	found := false
+	for _, entry := range list.RevokedCertificateEntries {
+		found = found || entry.SerialNumber.Cmp(cert.SerialNumber) == 0
+	}
+	if !found {
+		t.Fatal("Certificate is missing from the revocation list")
+	}
+}
+
+func TestRenewUser(t *testing.T) {
+	x509csr, _ := auth.PEMToCertificateRequest(csr)
+	certPem, _ := auth.GetCertificate(1, 43, x509csr, rootCA, rootKey)
+	cert, _ := auth.PEMToCertificate(certPem)
+	hash := auth.GetCertificateHash(cert)
+
+	user := entities.NewUser()
+	user.Email = mail
+	user.CertHash = hash
+	user.Certificate = string(certPem)
+	user.Csr = string(csr)
+
+	_, err = repository.Collection.Insert(user)
+	if err != nil {
+		t.Fatal("An error occurred while inserting the user")
+	}
+
+	c := entities.NewContract()
+	c.AddSigner(&user.ID, mail, hash)
+	_, err = manager.Get("contracts").Insert(c)
+	if err != nil {
+		t.Fatal("An error occurred while inserting the contract")
+	}
+	defer func() { _, _ = manager.Get("contracts").DeleteByID(*c) }()
+
+	launched := entities.NewContract()
+	launched.AddSigner(&user.ID, mail, hash)
+	launched.Launched = true
+	_, err = manager.Get("contracts").Insert(launched)
+	if err != nil {
+		t.Fatal("An error occurred while inserting the contract")
+	}
+	defer func() { _, _ = manager.Get("contracts").DeleteByID(*launched) }()
+
+	pid := &authority.PlatformID{Pkey: rootKey, RootCA: rootCA}
+
+	// Request for another user
+	otherCsr, _ := auth.GetCertificateRequest("country", "organization", "unit", "other@foo.foo", pkey)
+	_, err = u.Renew(pid, manager, hash, &api.RenewRequest{Request: string(otherCsr)})
+	if err == nil {
+		t.Fatal("Certificate renewed for another user")
+	}
+
+	// Unknown user
+	_, err = u.Renew(pid, manager, []byte{0x01}, &api.RenewRequest{Request: string(csr)})
+	if err == nil {
+		t.Fatal("Certificate renewed for an unknown user")
+	}
+
+	// Rotated key
+	newKey, _ := auth.GenerateKey(auth.KeyTypeECDSAP256, 0)
+	newCsr, _ := auth.GetCertificateRequest("country", "organization", "unit", mail, newKey)
+	res, err := u.Renew(pid, manager, hash, &api.RenewRequest{Request: string(newCsr)})
+	if err != nil {
+		t.Fatal("Unable to renew certificate:", err)
+	}
+
+	newCert, err := auth.PEMToCertificate([]byte(res.ClientCert))
+	if err != nil {
+		t.Fatal("Invalid renewed certificate:", err)
+	}
+	newHash := auth.GetCertificateHash(newCert)
+
+	renewed, _ := repository.FetchByMailAndHash(mail, newHash)
+	if renewed == nil || renewed.ID != user.ID {
+		t.Fatal("User has not been updated")
+	}
+
+	var revocations []entities.Revocation
+	_ = manager.Get("revocations").FindAll(bson.M{"certHash": hash}, &revocations)
+	if len(revocations) != 1 || string(revocations[0].ReplacedBy) != string(newHash) {
+		t.Fatal("Previous certificate has not been revoked")
+	}
+
+	// The revocation is pending until the new certificate is used
+	if isRevoked(t, pid, cert) {
+		t.Fatal("Previous certificate revoked before the use of the new one")
+	}
+
+	revoked, err := u.ConfirmRenewal(manager, newHash)
+	if err != nil || !revoked {
+		t.Fatal("Unable to confirm renewal:", err)
+	}
+	if !isRevoked(t, pid, cert) {
+		t.Fatal("Previous certificate not revoked after the use of the new one")
+	}
+	revoked, _ = u.ConfirmRenewal(manager, newHash)
+	if revoked {
+		t.Fatal("Renewal confirmed twice")
+	}
+
+	// Contracts are updated in background
+	updated := false
+	for i := 0; i < 50 && !updated; i++ {
+		time.Sleep(10 * time.Millisecond)
+		fetched := entities.Contract{}
+		_ = manager.Get("contracts").FindByID(*c, &fetched)
+		updated = len(fetched.Signers) == 1 && string(fetched.Signers[0].Hash) == string(newHash)
+	}
+	if !updated {
+		t.Fatal("Contract has not been updated")
+	}
+
+	// Launched contracts keep the sealed hash
+	fetched := entities.Contract{}
+	_ = manager.Get("contracts").FindByID(*launched, &fetched)
+	if len(fetched.Signers) != 1 || string(fetched.Signers[0].Hash) != string(hash) {
+		t.Fatal("Launched contract has been updated")
+	}
+}
+
+func TestRenewLostCertificate(t *testing.T) {
+	x509csr, _ := auth.PEMToCertificateRequest(csr)
+	certPem, _ := auth.GetCertificate(1, 44, x509csr, rootCA, rootKey)
+	cert, _ := auth.PEMToCertificate(certPem)
+	hash := auth.GetCertificateHash(cert)
+
+	user := entities.NewUser()
+	user.Email = "lost@foo.foo"
+	user.CertHash = hash
+	user.Certificate = string(certPem)
+
+	_, err = repository.Collection.Insert(user)
+	if err != nil {
+		t.Fatal("An error occurred while inserting the user")
+	}
+
+	pid := &authority.PlatformID{Pkey: rootKey, RootCA: rootCA}
+	lostCsr, _ := auth.GetCertificateRequest("country", "organization", "unit", user.Email, pkey)
+	res, err := u.Renew(pid, manager, hash, &api.RenewRequest{Request: string(lostCsr)})
+	if err != nil {
+		t.Fatal("Unable to renew certificate:", err)
+	}
+	lost, _ := auth.PEMToCertificate([]byte(res.ClientCert))
+
+	// The new certificate is lost, the previous one can still be used to renew
+	res, err = u.Renew(pid, manager, hash, &api.RenewRequest{Request: string(lostCsr)})
+	if err != nil {
+		t.Fatal("Unable to renew certificate with the previous one:", err)
+	}
+	renewed, _ := auth.PEMToCertificate([]byte(res.ClientCert))
+
+	if !isRevoked(t, pid, lost) {
+		t.Fatal("Unused certificate has not been revoked")
+	}
+	if isRevoked(t, pid, cert) {
+		t.Fatal("Previous certificate revoked before the use of the new one")
+	}
+
+	revoked, _ := u.ConfirmRenewal(manager, auth.GetCertificateHash(renewed))
+	if !revoked || !isRevoked(t, pid, cert) {
+		t.Fatal("Previous certificate not revoked after the use of the new one")
+	}
+}
+
+func TestUnregisterConfirmsRenewal(t *testing.T) {
+	x509csr, _ := auth.PEMToCertificateRequest(csr)
+	certPem, _ := auth.GetCertificate(1, 45, x509csr, rootCA, rootKey)
+	cert, _ := auth.PEMToCertificate(certPem)
+	hash := auth.GetCertificateHash(cert)
+
+	user := entities.NewUser()
+	user.Email = "unregistered@foo.foo"
+	user.CertHash = hash
+	user.Certificate = string(certPem)
+
+	_, err = repository.Collection.Insert(user)
+	if err != nil {
+		t.Fatal("An error occurred while inserting the user")
+	}
+
+	pid := &authority.PlatformID{Pkey: rootKey, RootCA: rootCA}
+	renewCsr, _ := auth.GetCertificateRequest("country", "organization", "unit", user.Email, pkey)
+	res, err := u.Renew(pid, manager, hash, &api.RenewRequest{Request: string(renewCsr)})
+	if err != nil {
+		t.Fatal("Unable to renew certificate:", err)
+	}
+	renewed, _ := auth.PEMToCertificate([]byte(res.ClientCert))
+
+	// The previous certificate must not outlive the unregistration
+	response := u.Unregister(manager, auth.GetCertificateHash(renewed))
+	if response.Code != api.ErrorCode_SUCCESS {
+		t.Fatal("An error occured while deleting the user:" + response.Message)
+	}
+	if !isRevoked(t, pid, cert) || !isRevoked(t, pid, renewed) {
+		t.Fatal("Certificates not revoked after the unregistration")
+	}
+}
+
+// isRevoked returns true if the certificate is part of the current revocation list
+func isRevoked(t *testing.T, pid *authority.PlatformID, cert *x509.Certificate) bool {
+	crl, err := u.GetRevocationList(pid, manager)
+	if err != nil {
+		t.Fatal("Unable to build revocation list:", err)
+	}
+	list, err := auth.PEMToRevocationList(crl, rootCA)
+	if err != nil {
+		t.Fatal("Invalid revocation list:", err)
+	}
+
+	for _, entry := range list.RevokedCertificateEntries {
+		if entry.SerialNumber.Cmp(cert.SerialNumber) == 0 {
+			return true
+		}
+	}
+	return false
+}
+
 func equalUsers(t *testing.T, user1, user2 *entities.User) {
 	if user1.ID != user2.ID {
 		t.Fatal("ID doesn't match : received ", user1.ID, " and ", user2.ID)
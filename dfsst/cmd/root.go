@@ -37,6 +37,7 @@ func init() {
 	// Bind flags to the dfsst command
 	RootCmd.PersistentFlags().BoolP("verbose", "v", false, "print verbose messages")
 	RootCmd.PersistentFlags().String("ca", "ca.pem", "path to the root certificate")
+	RootCmd.PersistentFlags().String("crl", "crl.pem", "path to the cached certificate revocation list")
 	RootCmd.PersistentFlags().String("cert", "cert.pem", "path to the ttp's certificate")
 	RootCmd.PersistentFlags().String("key", "key.pem", "path to the ttp's private key")
 	RootCmd.PersistentFlags().StringP("demo", "d", "", "demonstrator address and port, empty will disable it")
@@ -44,10 +45,12 @@ func init() {
 	startCmd.Flags().StringP("address", "a", "0.0.0.0", "address to bind for listening")
 	startCmd.Flags().String("db", "mongodb://localhost/dfss", "server url in standard MongoDB format to access the database")
 	startCmd.Flags().IntP("port", "p", 9020, "port to bind for listening")
+	startCmd.Flags().String("platform", "localhost:9000", "host of the dfss platform, to fetch the certificate revocation list")
 
 	// Store flag values into viper
 	_ = viper.BindPFlag("verbose", RootCmd.PersistentFlags().Lookup("verbose"))
 	_ = viper.BindPFlag("file_ca", RootCmd.PersistentFlags().Lookup("ca"))
+	_ = viper.BindPFlag("file_crl", RootCmd.PersistentFlags().Lookup("crl"))
 	_ = viper.BindPFlag("file_cert", RootCmd.PersistentFlags().Lookup("cert"))
 	_ = viper.BindPFlag("file_key", RootCmd.PersistentFlags().Lookup("key"))
 	_ = viper.BindPFlag("demo", RootCmd.PersistentFlags().Lookup("demo"))
@@ -55,6 +58,7 @@ func init() {
 	_ = viper.BindPFlag("port", startCmd.Flags().Lookup("port"))
 	_ = viper.BindPFlag("address", startCmd.Flags().Lookup("address"))
 	_ = viper.BindPFlag("dbURI", startCmd.Flags().Lookup("db"))
+	_ = viper.BindPFlag("platform_addrport", startCmd.Flags().Lookup("platform"))
 
 	if err := viper.BindEnv("password", "DFSS_TTP_PASSWORD"); err != nil {
 		fmt.Println("Warning: The DFSS_TTP_PASSWORD environment variable is not set, assuming the private key is decrypted")
@@ -29,6 +29,10 @@ func (manager *ArchivesManager) InitializeArchives(promise *cAPI.Promise, signat
 
 	if !present {
 		archives = NewSignatureArchives(signatureUUID, promise.Context.Sequence, *signers, promise.Context.ContractDocumentHash, promise.Context.Seal)
+		archives.TTPAddrport = promise.Context.TtpAddrPort
+		archives.TTPHash = promise.Context.TtpHash
+		archives.SequenceGenerator = promise.Context.SequenceGenerator
+		archives.Deadline = promise.Context.Deadline
 		ok, err := manager.DB.Get("signatures").Insert(*archives)
 		if !ok {
 			return err
@@ -85,19 +89,16 @@ func (manager *ArchivesManager) HasSignerPromised(signer uint32) bool {
 	return false
 }
 
-// AddToAbort : adds the specified signer to the aborted signers of the signatureArchives.
+// AddToAbort : adds the specified signer to the aborted signers of the signatureArchives, with the sequence index
+// at which he contacted the ttp.
 // If the signer is already present, does nothing.
-func (manager *ArchivesManager) AddToAbort(signerIndex uint32) {
+func (manager *ArchivesManager) AddToAbort(signerIndex, abortIndex uint32) {
 	for _, s := range manager.Archives.AbortedSigners {
 		if s.SignerIndex == signerIndex {
 			return
 		}
 	}
 
-	// TODO
-	// This requires the implementation of promises
-	var abortIndex uint32
-	abortIndex = 0
 	abortedSigner := NewAbortedSigner(signerIndex, abortIndex)
 
 	manager.Archives.AbortedSigners = append(manager.Archives.AbortedSigners, *abortedSigner)
@@ -115,11 +116,26 @@ func (manager *ArchivesManager) AddToDishonest(signerIndex uint32) {
 	manager.Archives.DishonestSigners = append(manager.Archives.DishonestSigners, signerIndex)
 }
 
+// IsSignerDishonest : determines if the specified signer has been evaluated as dishonest.
+func (manager *ArchivesManager) IsSignerDishonest(signerIndex uint32) bool {
+	for _, s := range manager.Archives.DishonestSigners {
+		if s == signerIndex {
+			return true
+		}
+	}
+
+	return false
+}
+
 // AddPromise : adds the specified promises to the list of received promises of the SignatureArchives.
-// If we have already a promise from this client, does not add it.
+// If we have already a promise from this client, only keeps the one with the highest sequence index,
+// as it is the evidence used to prove that an aborted signer kept on signing.
 func (manager *ArchivesManager) AddPromise(promise *Promise) {
-	for _, p := range manager.Archives.ReceivedPromises {
+	for i, p := range manager.Archives.ReceivedPromises {
 		if p.SenderKeyIndex == promise.SenderKeyIndex {
+			if p.SequenceIndex < promise.SequenceIndex {
+				manager.Archives.ReceivedPromises[i] = *promise
+			}
 			return
 		}
 	}
@@ -1,12 +1,15 @@
 package entities
 
 import (
-	"crypto/sha512"
+	"crypto/rsa"
+	"crypto/x509"
 	"fmt"
 	"os"
 	"testing"
 
+	"dfss/auth"
 	cAPI "dfss/dfssc/api"
+	"dfss/dfssc/security"
 	"dfss/mgdb"
 	"github.com/stretchr/testify/assert"
 	"gopkg.in/mgo.v2/bson"
@@ -27,6 +30,9 @@ var (
 
 	signersEntities []Signer
 
+	signersKeys  []*rsa.PrivateKey
+	signersCerts []*x509.Certificate
+
 	err error
 )
 
@@ -38,10 +44,18 @@ func init() {
 
 	sequence = []uint32{0, 1, 2, 0, 1, 2, 0, 1, 2}
 
+	// Credentials are needed to create and convert promises
+	caKey, _ := auth.GeneratePrivateKey(1024)
+	caPem, _ := auth.GetSelfSignedCertificate(1, 0, "FR", "DFSS", "TEST", "ca", caKey)
+	ca, _ := auth.PEMToCertificate(caPem)
+	ttpKey, ttpCert := newCredentials(ca, caKey, "ttp")
+	AuthContainer = &security.AuthContainer{CA: ca, Cert: ttpCert, Key: ttpKey}
+
 	for i := 0; i < 3; i++ {
-		h := sha512.Sum512([]byte{byte(i)})
-		signer := h[:]
-		signers = append(signers, signer)
+		key, cert := newCredentials(ca, caKey, fmt.Sprintf("signer%d", i))
+		signersKeys = append(signersKeys, key)
+		signersCerts = append(signersCerts, cert)
+		signers = append(signers, auth.GetCertificateHash(cert))
 	}
 
 	contractDocumentHash = []byte{1, 2, 3, 4, 5, 6, 7, 8, 9}
@@ -57,6 +71,15 @@ func init() {
 	}
 }
 
+func newCredentials(ca *x509.Certificate, caKey *rsa.PrivateKey, cn string) (*rsa.PrivateKey, *x509.Certificate) {
+	key, _ := auth.GeneratePrivateKey(1024)
+	csrPem, _ := auth.GetCertificateRequest("FR", "DFSS", "TEST", cn, key)
+	csr, _ := auth.PEMToCertificateRequest(csrPem)
+	certPem, _ := auth.GetCertificate(1, auth.GenerateUID(), csr, ca, caKey)
+	cert, _ := auth.PEMToCertificate(certPem)
+	return key, cert
+}
+
 func TestMain(m *testing.M) {
 	dbManager, err = mgdb.NewManager(db)
 	if err != nil {
@@ -212,10 +235,7 @@ func TestHasSignerPromised(t *testing.T) {
 	assert.Equal(t, ok, true)
 }
 
-// TO MODIFY WHEN SOURCE FUNCTION WILL BE UPDATED
 func TestAddToAbort(t *testing.T) {
-	// TODO
-	// Test the abortedIndex field, when promises will be implemented
 	archives := NewSignatureArchives(signatureUUIDBson, sequence, signersEntities, contractDocumentHash, seal)
 	manager := &ArchivesManager{
 		DB:       dbManager,
@@ -238,13 +258,16 @@ func TestAddToAbort(t *testing.T) {
 	assert.Equal(t, err, nil)
 	assert.Equal(t, sIndex, uint32(1))
 
-	manager.AddToAbort(sIndex)
+	manager.AddToAbort(sIndex, 4)
 	assert.Equal(t, len(archives.AbortedSigners), 1)
 	assert.Equal(t, archives.AbortedSigners[0].SignerIndex, uint32(1))
+	assert.Equal(t, archives.AbortedSigners[0].AbortIndex, uint32(4))
 
-	manager.AddToAbort(sIndex)
+	// The first abort index is kept
+	manager.AddToAbort(sIndex, 7)
 	assert.Equal(t, len(archives.AbortedSigners), 1)
 	assert.Equal(t, archives.AbortedSigners[0].SignerIndex, uint32(1))
+	assert.Equal(t, archives.AbortedSigners[0].AbortIndex, uint32(4))
 }
 
 func TestAddToDishonest(t *testing.T) {
@@ -270,9 +293,11 @@ func TestAddToDishonest(t *testing.T) {
 	assert.Equal(t, err, nil)
 	assert.Equal(t, sIndex, uint32(1))
 
+	assert.False(t, manager.IsSignerDishonest(sIndex))
 	manager.AddToDishonest(sIndex)
 	assert.Equal(t, len(archives.DishonestSigners), 1)
 	assert.Equal(t, archives.DishonestSigners[0], uint32(1))
+	assert.True(t, manager.IsSignerDishonest(sIndex))
 
 	manager.AddToDishonest(sIndex)
 	assert.Equal(t, len(archives.DishonestSigners), 1)
@@ -312,4 +337,19 @@ func TestAddPromise(t *testing.T) {
 
 	manager.AddPromise(promise0)
 	assert.Equal(t, len(archives.ReceivedPromises), 2)
+
+	// A later promise from the same sender replaces the previous one
+	promise2 := &Promise{
+		RecipientKeyIndex: 2,
+		SenderKeyIndex:    0,
+		SequenceIndex:     3,
+	}
+	manager.AddPromise(promise2)
+	assert.Equal(t, len(archives.ReceivedPromises), 2)
+	assert.Equal(t, archives.ReceivedPromises[0].RecipientKeyIndex, uint32(2))
+	assert.Equal(t, archives.ReceivedPromises[0].SequenceIndex, uint32(3))
+
+	manager.AddPromise(promise0)
+	assert.Equal(t, len(archives.ReceivedPromises), 2)
+	assert.Equal(t, archives.ReceivedPromises[0].SequenceIndex, uint32(3))
 }
@@ -4,16 +4,22 @@ import (
 	"bytes"
 	"errors"
 
+	"dfss/auth"
 	cAPI "dfss/dfssc/api"
+	"dfss/dfssc/common"
 )
 
 // ArePromisesValid : determines if the specified promises contains coherent information wrt the ASSUMED TESTED platform signed information.
-func ArePromisesValid(promises []*cAPI.Promise) (bool, []*Promise) {
+//
+// The alerter is the signer who sent these promises. Its clients only accept promises that the ttp is able to convert,
+// but an invalid promise from another signer is not held against the alerter: it is returned without signature,
+// and the request is rejected by the ttp without recording anything.
+func ArePromisesValid(promises []*cAPI.Promise, alerter uint32) (bool, []*Promise) {
 	var tmpPromises []*Promise
 
 	for _, promise := range promises {
 		valid, promiseEntity := IsPromiseValid(promise)
-		if !valid {
+		if !valid && (promiseEntity == nil || promiseEntity.SenderKeyIndex == alerter) {
 			return false, nil
 		}
 		tmpPromises = append(tmpPromises, promiseEntity)
@@ -25,19 +31,50 @@ func ArePromisesValid(promises []*cAPI.Promise) (bool, []*Promise) {
 // IsPromiseValid : determines if the specified promise contains coherent information wrt the ASSUMED TESTED platform signed information.
 // ie: the sender and recipient's hashes are correct
 //     the index of the promise coresponds to an expected message from the sender in the signed sequence
-// If true, returns a new promise entity
+//     the payload is a private contract signature from the sender, that we are able to convert
+// If true, returns a new promise entity.
+// If only the conversion failed, returns the promise entity without signature, otherwise returns nil.
 func IsPromiseValid(promise *cAPI.Promise) (bool, *Promise) {
 	// This checks if the index of the specified promise corresponds to an expected promise from the sender hash of the promise
 	sender, recipient, index, err := GetPromiseProfile(promise)
 	if err != nil {
-		return false, &Promise{}
+		return false, nil
 	}
 
 	entityPromise := NewPromise(sender, recipient, index)
+	entityPromise.Signature, err = ConvertPromise(promise)
+	if err != nil {
+		entityPromise.Signature = nil
+		return false, entityPromise
+	}
 
 	return true, entityPromise
 }
 
+// ConvertPromise : checks the private contract signature contained in the payload of the specified promise,
+// and converts it into the real signature of its sender, as the payload of a signature (see auth.CertifiedSignature).
+func ConvertPromise(promise *cAPI.Promise) ([]byte, error) {
+	if AuthContainer == nil {
+		return nil, errors.New("Missing ttp credentials")
+	}
+
+	cert, signature, err := auth.ConvertPCS(AuthContainer.Key, promise.Payload, common.SignatureStatement(promise.Context), common.PromiseLabel(promise))
+	if err != nil {
+		return nil, err
+	}
+
+	err = auth.CheckCertificate(cert, AuthContainer.CA, promise.Context.SenderKeyHash)
+	if err != nil {
+		return nil, err
+	}
+
+	certified := &auth.CertifiedSignature{
+		Certificate: cert.Raw,
+		Signature:   signature,
+	}
+	return certified.Marshal()
+}
+
 // IsPromiseFromAtoB : determines if the specified promise, supposedly from 'A' to 'B' was indeed created by 'A' for 'B'.
 func IsPromiseFromAtoB(promise *cAPI.Promise, from, to []byte, at uint32) bool {
 	if !(bytes.Equal(promise.Context.SenderKeyHash, from)) {
@@ -3,7 +3,9 @@ package entities
 import (
 	"testing"
 
+	"dfss/auth"
 	cAPI "dfss/dfssc/api"
+	"dfss/dfssc/common"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -29,21 +31,36 @@ func TestArePromisesValid(t *testing.T) {
 	}
 
 	promises := []*cAPI.Promise{promise0, promise1}
-	valid, promiseEntities := ArePromisesValid(promises)
+	valid, promiseEntities := ArePromisesValid(promises, 2)
 	assert.Equal(t, valid, false)
 	assert.Equal(t, promiseEntities, []*Promise(nil))
 
 	promise0.Context.RecipientKeyHash = signers[2]
 	promise0.Context.SenderKeyHash = signers[1]
 	promise0.Index = 1
-	valid, promiseEntities = ArePromisesValid(promises)
+	valid, promiseEntities = ArePromisesValid(promises, 2)
 	assert.Equal(t, valid, false)
 	assert.Equal(t, promiseEntities, []*Promise(nil))
 
 	promise1.Context.RecipientKeyHash = signers[2]
 	promise1.Context.SenderKeyHash = signers[0]
 	promise1.Index = 0
-	valid, promiseEntities = ArePromisesValid(promises)
+
+	// The payloads of the other signers cannot be converted, they are kept without signature
+	valid, promiseEntities = ArePromisesValid(promises, 2)
+	assert.Equal(t, valid, true)
+	assert.Equal(t, len(promiseEntities), 2)
+	assert.Equal(t, len(promiseEntities[0].Signature), 0)
+	assert.Equal(t, len(promiseEntities[1].Signature), 0)
+
+	// Unless the alerter sent one of them
+	valid, promiseEntities = ArePromisesValid(promises, 1)
+	assert.Equal(t, valid, false)
+	assert.Equal(t, promiseEntities, []*Promise(nil))
+
+	signPromise(promise0, 1, 2)
+	signPromise(promise1, 0, 2)
+	valid, promiseEntities = ArePromisesValid(promises, 2)
 	assert.Equal(t, valid, true)
 	assert.Equal(t, promiseEntities[0].RecipientKeyIndex, uint32(2))
 	assert.Equal(t, promiseEntities[0].SenderKeyIndex, uint32(1))
@@ -68,16 +85,66 @@ func TestIsPromiseValid(t *testing.T) {
 
 	valid, promiseEntity := IsPromiseValid(promise)
 	assert.Equal(t, valid, false)
-	assert.Equal(t, promiseEntity, &Promise{})
+	assert.Nil(t, promiseEntity)
 
+	// Unable to convert the payload
 	promise.Context.RecipientKeyHash = signers[2]
 	promise.Context.SenderKeyHash = signers[1]
 	promise.Index = 1
 	valid, promiseEntity = IsPromiseValid(promise)
+	assert.Equal(t, valid, false)
+	assert.Equal(t, promiseEntity.SenderKeyIndex, uint32(1))
+	assert.Equal(t, len(promiseEntity.Signature), 0)
+
+	signPromise(promise, 1, 2)
+	valid, promiseEntity = IsPromiseValid(promise)
 	assert.Equal(t, valid, true)
 	assert.Equal(t, promiseEntity.RecipientKeyIndex, uint32(2))
 	assert.Equal(t, promiseEntity.SenderKeyIndex, uint32(1))
 	assert.Equal(t, promiseEntity.SequenceIndex, uint32(1))
+	assert.True(t, len(promiseEntity.Signature) > 0)
+
+	// The payload is bound to the sequence index
+	promise.Index = 4
+	valid, _ = IsPromiseValid(promise)
+	assert.Equal(t, valid, false)
+}
+
+func TestConvertPromise(t *testing.T) {
+	promise := &cAPI.Promise{
+		Context: &cAPI.Context{
+			RecipientKeyHash:     signers[0],
+			SenderKeyHash:        signers[1],
+			Sequence:             sequence,
+			Signers:              signers,
+			ContractDocumentHash: contractDocumentHash,
+			SignatureUUID:        signatureUUID,
+			Seal:                 seal,
+		},
+		Index: 1,
+	}
+
+	_, err := ConvertPromise(promise)
+	assert.NotNil(t, err)
+
+	signPromise(promise, 1, 0)
+	signature, err := ConvertPromise(promise)
+	assert.Nil(t, err)
+	certified, err := auth.ParseCertifiedSignature(signature)
+	assert.Nil(t, err)
+	cert, err := certified.Verify(common.SignatureStatement(promise.Context))
+	assert.Nil(t, err)
+	assert.Equal(t, signersCerts[1].Raw, cert.Raw)
+
+	// The sender of the promise is not the owner of the certificate
+	promise.Context.SenderKeyHash = signers[2]
+	_, err = ConvertPromise(promise)
+	assert.NotNil(t, err)
+}
+
+// signPromise sets a private contract signature from 'from' to 'to' as the payload of the specified promise
+func signPromise(promise *cAPI.Promise, from, to int) {
+	promise.Payload, _ = auth.CreatePCS(signersKeys[from], signersCerts[from], common.SignatureStatement(promise.Context), common.PromiseLabel(promise), signersCerts[to], AuthContainer.Cert)
 }
 
 func TestIsPromiseFromAtoB(t *testing.T) {
@@ -5,9 +5,8 @@ import (
 	"crypto/sha512"
 	"errors"
 
-	"dfss/auth"
 	cAPI "dfss/dfssc/api"
-	pAPI "dfss/dfssp/api"
+	"dfss/dfssc/common"
 	"dfss/net"
 	"golang.org/x/net/context"
 	"gopkg.in/mgo.v2/bson"
@@ -130,21 +129,5 @@ func IsPlatformSealValid(promise *cAPI.Promise) bool {
 		return false
 	}
 
-	var ttp *pAPI.LaunchSignature_TTP
-	if promise.Context.TtpAddrPort != "" {
-		ttp = new(pAPI.LaunchSignature_TTP)
-		ttp.Addrport = promise.Context.TtpAddrPort
-		ttp.Hash = promise.Context.TtpHash
-	}
-
-	theoric := pAPI.LaunchSignature{
-		SignatureUuid: promise.Context.SignatureUUID,
-		DocumentHash:  promise.Context.ContractDocumentHash,
-		KeyHash:       promise.Context.Signers,
-		Sequence:      promise.Context.Sequence,
-		Ttp:           ttp,
-	}
-
-	ok, _ := auth.VerifyStructure(AuthContainer.CA, theoric, promise.Context.Seal)
-	return ok
+	return common.IsSealValid(AuthContainer.CA, promise.Context)
 }
@@ -31,6 +31,12 @@ type SignatureArchives struct {
 	TextHash []byte   `key:"textHash" bson:"textHash"` // Small hash of the contract
 	Seal     []byte   `key:"seal" bson:"seal"`         // Seal provided by the platform to authentify the context
 
+	TTPAddrport string `key:"ttpAddrport" bson:"ttpAddrport"` // Address of the ttp, as sealed by the platform
+	TTPHash     []byte `key:"ttpHash" bson:"ttpHash"`         // Hash of the ttp certificate, as sealed by the platform
+
+	SequenceGenerator string `key:"sequenceGenerator" bson:"sequenceGenerator"` // Name of the sequence generator, as sealed by the platform
+	Deadline          int64  `key:"deadline" bson:"deadline"`                   // Deadline of an asynchronous signature (unix time), as sealed by the platform
+
 	ReceivedPromises []Promise       `key:"receivedPromises" bson:"receivedPromises"` // Set of valid received promises (1 by sender)
 	AbortedSigners   []AbortedSigner `key:"abortedSigners" bson:"abortedSigners"`     // Signers that were sent an abort token
 	DishonestSigners []uint32        `key:"dishonestSigners" bson:"dishonestSigners"` // Indexes of the signers that were evaluated as dishonest
@@ -65,6 +71,7 @@ type Promise struct {
 	SenderKeyIndex uint32 `key:"senderKeyIndex" bson:"senderKeyIndex"` // Index of the hash of the sender's certificate in
 	// the `Signers` field of the enclosing SignatureArchives (identical to the one in the signers hashes array of the incoming promises)
 	SequenceIndex uint32 `key:"sequenceIndex" bson:"sequenceIndex"` // Sequence index of the promise
+	Signature     []byte `key:"signature" bson:"signature"`         // Signature of the sender, converted from the promise (see ConvertPromise)
 }
 
 // NewPromise : creates a new Promise with the specified fields
@@ -2,12 +2,16 @@
 package resolve
 
 import (
+	"crypto/x509"
+	"encoding/json"
 	"errors"
 	"fmt"
 
+	"dfss/auth"
 	cAPI "dfss/dfssc/api"
 	"dfss/dfssc/common"
 	dAPI "dfss/dfssd/api"
+	"dfss/dfssp/contract"
 	"dfss/dfsst/entities"
 )
 
@@ -163,6 +167,9 @@ func containsPromise(promises []*entities.Promise, promise *entities.Promise) bo
 }
 
 // Solve : tries to generate the signed contract from present evidence.
+//
+// An abort token is final for its recipient: the contract cannot be generated as long as an aborted signer
+// has not been evaluated as dishonest, as he would be the only one not to get it.
 func Solve(manager *entities.ArchivesManager) (bool, []byte) {
 	// Test if we can generate the contract
 	for i := range manager.Archives.Signers {
@@ -172,22 +179,76 @@ func Solve(manager *entities.ArchivesManager) (bool, []byte) {
 		}
 	}
 
-	return true, GenerateSignedContract(manager.Archives)
+	for _, s := range manager.Archives.AbortedSigners {
+		if !manager.IsSignerDishonest(s.SignerIndex) {
+			dAPI.DLog("signer " + fmt.Sprint(s.SignerIndex) + " was sent an abort token")
+			return false, nil
+		}
+	}
+
+	contract, err := GenerateSignedContract(manager.Archives)
+	if err != nil {
+		dAPI.DLog("unable to generate the signed contract: " + err.Error())
+		return false, nil
+	}
+
+	return true, contract
 }
 
-// GenerateSignedContract : generates the signed contract.
-// Does not take into account if we have the evidence to do it (see function 'CanGenerate').
+// GenerateSignedContract : generates the signed contract, using the signatures converted from the received promises.
+// Does not take into account if we have the evidence to do it (see function 'Solve').
 //
-// XXX : Implementation needs cryptographic promises
-func GenerateSignedContract(archives *entities.SignatureArchives) []byte {
-	var pseudoContract string
+// The signed contract has the same format as the proof files created by the clients after a signature (see common.SignedContractJSON).
+// As the ttp does not know the original contract, only the document hash and the signers are filled in its description.
+func GenerateSignedContract(archives *entities.SignatureArchives) ([]byte, error) {
+	var hashes [][]byte
+	signedContract := common.SignedContractJSON{
+		Contract: contract.JSON{
+			File:    &contract.FileJSON{Hash: fmt.Sprintf("%x", archives.TextHash)},
+			Signers: make([]contract.SignerJSON, len(archives.Signers)),
+		},
+	}
+
+	for i, s := range archives.Signers {
+		hashes = append(hashes, s.Hash)
+		signedContract.Contract.Signers[i].Hash = fmt.Sprintf("%x", s.Hash)
+	}
+
 	for _, p := range archives.ReceivedPromises {
-		signature := "SIGNATURE FROM SIGNER " + fmt.Sprintf("%x", archives.Signers[p.SenderKeyIndex].Hash)
-		signature += " ON SIGNATURE n° " + fmt.Sprint(archives.ID) + "\n"
-		pseudoContract += signature
+		if len(p.Signature) == 0 {
+			return nil, errors.New("Missing signature from signer " + fmt.Sprint(p.SenderKeyIndex))
+		}
+
+		// The mail of the signer is only available in its certificate
+		certified, err := auth.ParseCertifiedSignature(p.Signature)
+		if err != nil {
+			return nil, err
+		}
+		cert, err := x509.ParseCertificate(certified.Certificate)
+		if err != nil {
+			return nil, err
+		}
+		signedContract.Contract.Signers[p.SenderKeyIndex].Email = cert.Subject.CommonName
+
+		signedContract.Signatures = append(signedContract.Signatures, cAPI.Signature{
+			Context: &cAPI.Context{
+				RecipientKeyHash:     hashes[p.RecipientKeyIndex],
+				SenderKeyHash:        hashes[p.SenderKeyIndex],
+				Sequence:             archives.Sequence,
+				Signers:              hashes,
+				ContractDocumentHash: archives.TextHash,
+				SignatureUUID:        archives.ID.Hex(),
+				TtpAddrPort:          archives.TTPAddrport,
+				TtpHash:              archives.TTPHash,
+				Seal:                 archives.Seal,
+				SequenceGenerator:    archives.SequenceGenerator,
+				Deadline:             archives.Deadline,
+			},
+			Payload: p.Signature,
+		})
 	}
 
-	return []byte(pseudoContract)
+	return json.MarshalIndent(signedContract, "", "  ")
 }
 
 // ComputeDishonestSigners : computes the dishonest signers from the provided evidence.
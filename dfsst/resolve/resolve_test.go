@@ -2,11 +2,14 @@ package resolve
 
 import (
 	"crypto/sha512"
+	"encoding/json"
 	"fmt"
 	"os"
 	"testing"
 
+	"dfss/auth"
 	cAPI "dfss/dfssc/api"
+	"dfss/dfssc/common"
 	"dfss/dfsst/entities"
 	"dfss/mgdb"
 	"github.com/stretchr/testify/assert"
@@ -26,6 +29,9 @@ var (
 
 	signersEntities []entities.Signer
 
+	// Certified signatures of each signer, as converted from promises
+	signersSignatures [][]byte
+
 	err error
 )
 
@@ -46,9 +52,16 @@ func init() {
 	signatureUUIDBson = bson.NewObjectId()
 
 	signersEntities = make([]entities.Signer, 0)
-	for _, s := range signers {
+	for i, s := range signers {
 		signerEntity := entities.NewSigner(s)
 		signersEntities = append(signersEntities, *signerEntity)
+
+		key, _ := auth.GeneratePrivateKey(1024)
+		certPem, _ := auth.GetSelfSignedCertificate(1, 0, "FR", "DFSS", "TEST", fmt.Sprintf("signer%d@example.com", i), key)
+		cert, _ := auth.PEMToCertificate(certPem)
+		certified, _ := auth.NewCertifiedSignature(key, cert, []byte("statement"))
+		signature, _ := certified.Marshal()
+		signersSignatures = append(signersSignatures, signature)
 	}
 }
 
@@ -384,10 +397,12 @@ func TestSolve(t *testing.T) {
 	promise0 := &entities.Promise{
 		RecipientKeyIndex: 1,
 		SenderKeyIndex:    0,
+		Signature:         signersSignatures[0],
 	}
 	promise1 := &entities.Promise{
 		RecipientKeyIndex: 2,
 		SenderKeyIndex:    1,
+		Signature:         signersSignatures[1],
 	}
 	promise2 := &entities.Promise{
 		RecipientKeyIndex: 1,
@@ -408,17 +423,29 @@ func TestSolve(t *testing.T) {
 	assert.Equal(t, ok, false)
 	assert.Equal(t, len(contract), 0)
 
+	// The promise of the last signer cannot be converted
 	manager.Archives.ReceivedPromises = append(manager.Archives.ReceivedPromises, *promise2)
 	ok, contract = Solve(manager)
+	assert.Equal(t, ok, false)
+	assert.Equal(t, len(contract), 0)
+
+	manager.Archives.ReceivedPromises[2].Signature = signersSignatures[2]
+
+	// An aborted signer must be evaluated as dishonest
+	manager.AddToAbort(1, 2)
+	ok, contract = Solve(manager)
+	assert.Equal(t, ok, false)
+	assert.Equal(t, len(contract), 0)
+
+	manager.AddToDishonest(1)
+	ok, contract = Solve(manager)
 	assert.Equal(t, ok, true)
 	if len(contract) == 0 {
 		t.Fatal("Contract should have beed generated")
 	}
 }
 
-// TO MODIFY WHEN SOURCE FUNCTION WILL BE UPDATED
 func TestGenerateSignedContract(t *testing.T) {
-	// TODO
 	id := bson.NewObjectId()
 	var promises []entities.Promise
 	for i := 1; i > -1; i-- {
@@ -426,6 +453,7 @@ func TestGenerateSignedContract(t *testing.T) {
 			RecipientKeyIndex: 2,
 			SenderKeyIndex:    sequence[i],
 			SequenceIndex:     uint32(i),
+			Signature:         signersSignatures[sequence[i]],
 		}
 		promises = append(promises, p)
 	}
@@ -433,26 +461,43 @@ func TestGenerateSignedContract(t *testing.T) {
 		RecipientKeyIndex: 2,
 		SenderKeyIndex:    2,
 		SequenceIndex:     2,
+		Signature:         signersSignatures[2],
 	}
 	promises = append(promises, selfPromise)
 
 	archives := &entities.SignatureArchives{
 		ID:               id,
+		Sequence:         sequence,
 		Signers:          signersEntities,
+		TextHash:         contractDocumentHash,
+		Seal:             signedHash,
+		TTPAddrport:      "localhost:9020",
 		ReceivedPromises: promises,
 	}
 
-	contract := GenerateSignedContract(archives)
+	data, err := GenerateSignedContract(archives)
+	assert.Nil(t, err)
+
+	var contract common.SignedContractJSON
+	assert.Nil(t, json.Unmarshal(data, &contract))
+	assert.Equal(t, fmt.Sprintf("%x", contractDocumentHash), contract.Contract.File.Hash)
+	assert.Equal(t, len(signers), len(contract.Contract.Signers))
+	for i, s := range contract.Contract.Signers {
+		assert.Equal(t, fmt.Sprintf("%x", signers[i]), s.Hash)
+		assert.Equal(t, fmt.Sprintf("signer%d@example.com", i), s.Email)
+	}
 
-	var pseudoContract string
-	for _, p := range promises {
-		signature := "SIGNATURE FROM SIGNER " + fmt.Sprintf("%x", signersEntities[p.SenderKeyIndex].Hash)
-		signature += " ON SIGNATURE n° " + fmt.Sprint(id) + "\n"
-		pseudoContract += signature
+	assert.Equal(t, len(promises), len(contract.Signatures))
+	for i, s := range contract.Signatures {
+		assert.Equal(t, signers[promises[i].SenderKeyIndex], s.Context.SenderKeyHash)
+		assert.Equal(t, id.Hex(), s.Context.SignatureUUID)
+		assert.Equal(t, "localhost:9020", s.Context.TtpAddrPort)
+		assert.Equal(t, promises[i].Signature, s.Payload)
 	}
 
-	expected := []byte(pseudoContract)
-	assert.Equal(t, contract, expected)
+	archives.ReceivedPromises[0].Signature = nil
+	_, err = GenerateSignedContract(archives)
+	assert.NotNil(t, err)
 }
 
 func TestComputeDishonestSigners(t *testing.T) {
@@ -0,0 +1,650 @@
+package server
+
+import (
+	"crypto/rsa"
+	"crypto/sha512"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"dfss/auth"
+	cAPI "dfss/dfssc/api"
+	"dfss/dfssc/common"
+	"dfss/dfssc/security"
+	pAPI "dfss/dfssp/api"
+	"dfss/dfssp/contract"
+	tAPI "dfss/dfsst/api"
+	"dfss/dfsst/entities"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// The fairness harness explores every execution of the signing protocol for small groups of signers,
+// and drives the real Alert and Recover routes of the ttp along each of them.
+//
+// Honest signers follow the protocol of the sign package (see common.CheckFairness for the abstract model):
+// they wait for their pending set, send their promises, then their signature, and resolve with the promises
+// of the rounds they completed as soon as they time out, including during the signature round.
+//
+// Dishonest signers share their keys, never send their signature, and can at any moment alert the ttp at every
+// index of the sequence with the evidence they hold, replay their alerts after an abort token, or call Recover.
+// They can also send promises that cannot be converted by the ttp, which honest signers must reject, and alert
+// with malformed promises of the other signers, which the ttp must never hold against an honest signer.
+
+// Status of a signer in a harnessNode
+const (
+	harnessRunning byte = iota
+	harnessSigned
+	harnessContract
+	harnessAborted
+)
+
+// Kinds of promises created by the harness
+const (
+	harnessValid         byte = iota
+	harnessUnconvertible      // well-formed, but escrowing a signature of another statement
+	harnessMalformed          // truncated, rejected by the ttp before opening the escrow
+)
+
+const harnessMaxSigners = 4
+
+var (
+	harnessOnce  sync.Once
+	harnessCA    *x509.Certificate
+	harnessCAKey *rsa.PrivateKey
+	harnessTTP   *security.AuthContainer
+	harnessKeys  []*rsa.PrivateKey
+	harnessCerts []*x509.Certificate
+)
+
+// harnessCredentials creates the credentials of the platform, the ttp and the signers, once for every test.
+func harnessCredentials(t *testing.T) {
+	harnessOnce.Do(func() {
+		harnessCAKey, _ = auth.GeneratePrivateKey(1024)
+		caPem, _ := auth.GetSelfSignedCertificate(1, 0, "FR", "DFSS", "TEST", "platform", harnessCAKey)
+		harnessCA, _ = auth.PEMToCertificate(caPem)
+
+		ttpKey, ttpCert := harnessCertificate(harnessCA, harnessCAKey, "ttp")
+		harnessTTP = &security.AuthContainer{CA: harnessCA, Cert: ttpCert, Key: ttpKey}
+
+		for i := 0; i < harnessMaxSigners; i++ {
+			key, cert := harnessCertificate(harnessCA, harnessCAKey, fmt.Sprintf("signer%d@example.com", i))
+			harnessKeys = append(harnessKeys, key)
+			harnessCerts = append(harnessCerts, cert)
+		}
+	})
+	if harnessCA == nil || len(harnessCerts) != harnessMaxSigners {
+		t.Fatal("Unable to create the credentials of the harness")
+	}
+}
+
+func harnessCertificate(ca *x509.Certificate, caKey *rsa.PrivateKey, cn string) (*rsa.PrivateKey, *x509.Certificate) {
+	key, _ := auth.GeneratePrivateKey(1024)
+	csrPem, _ := auth.GetCertificateRequest("FR", "DFSS", "TEST", cn, key)
+	csr, _ := auth.PEMToCertificateRequest(csrPem)
+	certPem, _ := auth.GetCertificate(1, auth.GenerateUID(), csr, ca, caKey)
+	cert, _ := auth.PEMToCertificate(certPem)
+	return key, cert
+}
+
+type harnessPromiseKey struct {
+	from, to, index uint32
+	kind            byte
+}
+
+// fairnessHarness contains the static data of the exploration for a sequence and a set of dishonest signers
+type fairnessHarness struct {
+	t         *testing.T
+	server    *ttpServer
+	sequence  []uint32
+	n         int
+	dishonest uint32
+	occ       [][]int
+	pending   [][][]common.SequenceCoordinate
+
+	hashes       [][]byte
+	uuid         bson.ObjectId
+	documentHash []byte
+	seal         []byte
+	promises     map[harnessPromiseKey]*cAPI.Promise
+	accepted     map[harnessPromiseKey]bool // promises already checked by their honest recipient
+	contracts    map[string]bool            // signed contracts already checked
+	calls        map[string]*harnessCall    // outcomes of the requests sent to the ttp, by archives and request
+}
+
+// harnessNode is a state of the exploration
+type harnessNode struct {
+	phase    []int  // number of completed occurrences of each honest signer
+	status   []byte // status of each signer
+	archives *entities.SignatureArchives
+	trace    []string
+}
+
+func newFairnessHarness(t *testing.T, sequence []uint32, n int, dishonest uint32) *fairnessHarness {
+	harnessCredentials(t)
+
+	h := &fairnessHarness{
+		t: t,
+		server: &ttpServer{
+			DB:        ttp.DB,
+			globalMut: &sync.Mutex{},
+			mutMap:    make(map[bson.ObjectId]*sync.Mutex),
+		},
+		sequence:  sequence,
+		n:         n,
+		dishonest: dishonest,
+		occ:       make([][]int, n),
+		pending:   make([][][]common.SequenceCoordinate, n),
+		uuid:      bson.NewObjectId(),
+		promises:  make(map[harnessPromiseKey]*cAPI.Promise),
+		accepted:  make(map[harnessPromiseKey]bool),
+		contracts: make(map[string]bool),
+		calls:     make(map[string]*harnessCall),
+	}
+
+	for i, s := range sequence {
+		h.occ[s] = append(h.occ[s], i)
+		set, _ := common.GetPendingSet(sequence, s, i)
+		h.pending[s] = append(h.pending[s], set)
+	}
+
+	for i := 0; i < n; i++ {
+		h.hashes = append(h.hashes, auth.GetCertificateHash(harnessCerts[i]))
+	}
+	documentHash := sha512.Sum512([]byte("harness contract"))
+	h.documentHash = documentHash[:]
+
+	var err error
+	h.seal, err = auth.SignStructure(harnessCAKey, pAPI.LaunchSignature{
+		SignatureUuid: h.uuid.Hex(),
+		DocumentHash:  h.documentHash,
+		KeyHash:       h.hashes,
+		Sequence:      sequence,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return h
+}
+
+func (h *fairnessHarness) isDishonest(i int) bool {
+	return h.dishonest&(1<<uint(i)) != 0
+}
+
+// context returns a grpc context authenticated as the specified signer
+func (h *fairnessHarness) context(i int) context.Context {
+	state := tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{harnessCerts[i]}}}
+	return peer.NewContext(context.Background(), &peer.Peer{AuthInfo: credentials.TLSInfo{State: state}})
+}
+
+// promise returns a promise from 'from' to 'to' at the specified index, as created by the client
+func (h *fairnessHarness) promise(from, to, index uint32) *cAPI.Promise {
+	return h.createPromise(harnessPromiseKey{from, to, index, harnessValid})
+}
+
+// malformed returns a truncated promise from 'from' to 'to' at the specified index
+func (h *fairnessHarness) malformed(from, to, index uint32) *cAPI.Promise {
+	return h.createPromise(harnessPromiseKey{from, to, index, harnessMalformed})
+}
+
+// createPromise creates the promise of the specified kind.
+// Promises that cannot be converted are made by their sender if he is dishonest, or by their recipient otherwise,
+// as he cannot use the key of an honest signer.
+func (h *fairnessHarness) createPromise(key harnessPromiseKey) *cAPI.Promise {
+	if p, ok := h.promises[key]; ok {
+		return p
+	}
+	from, to, index := key.from, key.to, key.index
+
+	if key.kind == harnessMalformed {
+		unconvertible := h.createPromise(harnessPromiseKey{from, to, index, harnessUnconvertible})
+		p := *unconvertible
+		p.Payload = p.Payload[:len(p.Payload)/2]
+		h.promises[key] = &p
+		return &p
+	}
+
+	p := &cAPI.Promise{
+		Index: index,
+		Context: &cAPI.Context{
+			RecipientKeyHash:     h.hashes[to],
+			SenderKeyHash:        h.hashes[from],
+			Sequence:             h.sequence,
+			Signers:              h.hashes,
+			ContractDocumentHash: h.documentHash,
+			SignatureUUID:        h.uuid.Hex(),
+			Seal:                 h.seal,
+		},
+	}
+	signer, statement := from, common.SignatureStatement(p.Context)
+	if key.kind == harnessUnconvertible {
+		if !h.isDishonest(int(from)) {
+			signer = to
+		}
+		statement = append([]byte("malformed "), statement...)
+	}
+
+	var err error
+	p.Payload, err = auth.CreatePCS(harnessKeys[signer], harnessCerts[signer], statement, common.PromiseLabel(p), harnessCerts[to], harnessTTP.Cert)
+	if err != nil {
+		h.t.Fatal(err)
+	}
+
+	h.promises[key] = p
+	return p
+}
+
+// accepts returns true if the honest signer i accepts the promise, as checked by the client before using it as evidence
+func (h *fairnessHarness) accepts(i int, key harnessPromiseKey) bool {
+	key.to = uint32(i)
+	if ok, checked := h.accepted[key]; checked {
+		return ok
+	}
+
+	p := h.createPromise(key)
+	sender, err := auth.VerifyPCS(harnessKeys[i], p.Payload, common.SignatureStatement(p.Context), common.PromiseLabel(p), harnessTTP.Cert)
+	ok := err == nil && auth.CheckCertificate(sender, harnessCA, p.Context.SenderKeyHash) == nil
+	h.accepted[key] = ok
+	return ok
+}
+
+// hasSent returns true if the honest signer i has sent its promises for the occurrence at index
+func (h *fairnessHarness) hasSent(node *harnessNode, i int, index uint32) bool {
+	for p := 0; p < node.phase[i]; p++ {
+		if h.occ[i][p] == int(index) {
+			return true
+		}
+	}
+	return false
+}
+
+// available returns true if the promise c has been sent, or can be forged by the dishonest signers
+func (h *fairnessHarness) available(node *harnessNode, c common.SequenceCoordinate) bool {
+	return h.isDishonest(int(c.Signer)) || h.hasSent(node, int(c.Signer), c.Index)
+}
+
+// received returns true if every promise of the pending set of the occurrence p of signer i is available
+func (h *fairnessHarness) received(node *harnessNode, i, p int) bool {
+	for _, c := range h.pending[i][p] {
+		if !h.available(node, c) {
+			return false
+		}
+	}
+	return true
+}
+
+// evidence returns the promises sent to the ttp by signer i resolving at step after the specified number of rounds:
+// the last available promise of every sender, as kept by the client, and a promise to itself at step.
+func (h *fairnessHarness) evidence(node *harnessNode, i, step, rounds int) []*cAPI.Promise {
+	last := make(map[uint32]uint32)
+	var senders []uint32
+	for p := 0; p < rounds; p++ {
+		for _, c := range h.pending[i][p] {
+			if !h.available(node, c) {
+				continue
+			}
+			if _, ok := last[c.Signer]; !ok {
+				senders = append(senders, c.Signer)
+			}
+			last[c.Signer] = c.Index
+		}
+	}
+
+	var res []*cAPI.Promise
+	for _, s := range senders {
+		if !h.isDishonest(i) {
+			// Dishonest senders may send an unconvertible promise first, it must not be kept as evidence
+			if h.isDishonest(int(s)) && h.accepts(i, harnessPromiseKey{from: s, index: last[s], kind: harnessUnconvertible}) {
+				h.t.Fatalf("Signer %d accepts an unconvertible promise from signer %d", i, s)
+			}
+			if !h.accepts(i, harnessPromiseKey{from: s, index: last[s]}) {
+				h.t.Fatalf("Signer %d rejects a valid promise from signer %d", i, s)
+			}
+		}
+		res = append(res, h.promise(s, uint32(i), last[s]))
+	}
+	return append(res, h.promise(uint32(i), uint32(i), uint32(step)))
+}
+
+// malformedEvidence returns the evidence of signer i, the promises of the other signers being replaced by malformed ones
+func (h *fairnessHarness) malformedEvidence(promises []*cAPI.Promise, i int) []*cAPI.Promise {
+	var res []*cAPI.Promise
+	for _, p := range promises[:len(promises)-1] {
+		from, _ := entities.GetIndexOfSigner(p, p.Context.SenderKeyHash)
+		res = append(res, h.malformed(from, uint32(i), p.Index))
+	}
+	return append(res, promises[len(promises)-1])
+}
+
+// restore sets the archives of the ttp to the ones of the specified node
+func (h *fairnessHarness) restore(node *harnessNode) {
+	collection := h.server.DB.Get("signatures")
+	_, _ = collection.DeleteByID(entities.SignatureArchives{ID: h.uuid})
+	if node.archives != nil {
+		if ok, err := collection.Insert(*node.archives); !ok {
+			h.t.Fatal(err)
+		}
+	}
+}
+
+// next returns a copy of the node, sharing its archives
+func (h *fairnessHarness) next(node *harnessNode, description string) *harnessNode {
+	return &harnessNode{
+		phase:    append([]int{}, node.phase...),
+		status:   append([]byte{}, node.status...),
+		archives: node.archives,
+		trace:    append(append([]string{}, node.trace...), description),
+	}
+}
+
+// harnessCall is the outcome of a request to the ttp
+type harnessCall struct {
+	outcome  string
+	status   byte // new status of the sender, harnessRunning if unchanged
+	archives *entities.SignatureArchives
+}
+
+// call sends a request to the ttp with the archives of the node, and returns the resulting node.
+//
+// As the ttp only depends on its archives, the outcome of a request already sent with the same archives is reused.
+func (h *fairnessHarness) call(node *harnessNode, i int, request, description string, send func() (*tAPI.TTPResponse, error)) *harnessNode {
+	key := h.archivesKey(node.archives) + fmt.Sprint("|", i, request)
+	c, ok := h.calls[key]
+	if !ok {
+		h.restore(node)
+		response, err := send()
+
+		c = &harnessCall{}
+		switch {
+		case err != nil:
+			c.outcome = " and gets an error"
+		case response.Abort:
+			c.outcome = " and gets an abort token"
+			c.status = harnessAborted
+		case len(response.Contract) == 0:
+			c.outcome = " and gets nothing"
+		default:
+			h.checkContract(response.Contract)
+			c.outcome = " and gets the signed contract"
+			c.status = harnessContract
+		}
+
+		manager := entities.NewArchivesManager(h.server.DB)
+		if present, archives := manager.ContainsSignature(h.uuid); present {
+			c.archives = archives
+			for _, s := range archives.DishonestSigners {
+				if !h.isDishonest(int(s)) {
+					h.t.Fatalf("Honest signer %d is evaluated as dishonest by the ttp:\n  %s", s, strings.Join(append(node.trace, description+c.outcome), "\n  "))
+				}
+			}
+		}
+		h.calls[key] = c
+	}
+
+	next := h.next(node, description+c.outcome)
+	next.archives = c.archives
+	if c.status == harnessContract || (c.status == harnessAborted && next.status[i] == harnessRunning) {
+		next.status[i] = c.status
+	}
+	return next
+}
+
+// alert sends an AlertRequest to the ttp
+func (h *fairnessHarness) alert(node *harnessNode, i, step int, promises []*cAPI.Promise, description string) *harnessNode {
+	var request []string
+	for _, p := range promises {
+		r := fmt.Sprintf("%x>%x@%d", p.Context.SenderKeyHash[:4], p.Context.RecipientKeyHash[:4], p.Index)
+		if from, _ := entities.GetIndexOfSigner(p, p.Context.SenderKeyHash); h.promises[harnessPromiseKey{from, uint32(i), p.Index, harnessMalformed}] == p {
+			r += "!"
+		}
+		request = append(request, r)
+	}
+	return h.call(node, i, fmt.Sprint("alert", step, request), description, func() (*tAPI.TTPResponse, error) {
+		return h.server.Alert(h.context(i), &tAPI.AlertRequest{Promises: promises, Index: uint32(step)})
+	})
+}
+
+// recover sends a RecoverRequest to the ttp
+func (h *fairnessHarness) recover(node *harnessNode, i int) *harnessNode {
+	return h.call(node, i, "recover", fmt.Sprintf("signer %d recovers", i), func() (*tAPI.TTPResponse, error) {
+		return h.server.Recover(h.context(i), &tAPI.RecoverRequest{SignatureUUID: h.uuid.Hex()})
+	})
+}
+
+// checkContract verifies that a signed contract sent by the ttp is a valid proof
+func (h *fairnessHarness) checkContract(signed []byte) {
+	if h.contracts[string(signed)] {
+		return
+	}
+	h.contracts[string(signed)] = true
+
+	var proof common.SignedContractJSON
+	if err := json.Unmarshal(signed, &proof); err != nil {
+		h.t.Fatal("Invalid signed contract from the ttp:", err)
+	}
+	if _, err := common.VerifyProof(&proof, harnessCA, time.Time{}); err != nil {
+		h.t.Fatal("Invalid signed contract from the ttp:", err)
+	}
+}
+
+// events returns the states reachable from node in one step
+func (h *fairnessHarness) events(node *harnessNode) []*harnessNode {
+	var res []*harnessNode
+
+	for i := 0; i < h.n; i++ {
+		if node.status[i] == harnessContract || node.status[i] == harnessSigned {
+			continue
+		}
+
+		if h.isDishonest(i) {
+			// Alert at every index of the sequence, even after an abort token
+			for step := range h.sequence {
+				rounds := 0
+				for rounds < len(h.occ[i]) && h.occ[i][rounds] <= step {
+					rounds++
+				}
+				promises := h.evidence(node, i, step, rounds)
+				res = append(res, h.alert(node, i, step, promises, fmt.Sprintf("dishonest signer %d resolves at step %d with %d promises", i, step, len(promises))))
+				if len(promises) > 1 {
+					res = append(res, h.alert(node, i, step, h.malformedEvidence(promises, i), fmt.Sprintf("dishonest signer %d resolves at step %d with %d malformed promises", i, step, len(promises)-1)))
+				}
+			}
+			res = append(res, h.recover(node, i))
+			continue
+		}
+
+		if node.status[i] != harnessRunning {
+			continue
+		}
+
+		p := node.phase[i]
+		if p < len(h.occ[i]) {
+			// Promise round
+			if h.received(node, i, p) {
+				next := h.next(node, fmt.Sprintf("signer %d completes step %d", i, h.occ[i][p]))
+				next.phase[i]++
+				res = append(res, next)
+			}
+		} else {
+			// Signature round
+			all := true
+			for j := 0; j < h.n && all; j++ {
+				all = j == i || (!h.isDishonest(j) && node.phase[j] == len(h.occ[j]))
+			}
+			if all {
+				next := h.next(node, fmt.Sprintf("signer %d receives every signature", i))
+				next.status[i] = harnessSigned
+				res = append(res, next)
+			}
+		}
+
+		// Timeout
+		step := 0
+		if p > 0 {
+			step = h.occ[i][p-1]
+		}
+		res = append(res, h.alert(node, i, step, h.evidence(node, i, step, p), fmt.Sprintf("signer %d times out and resolves at step %d", i, step)))
+	}
+
+	return res
+}
+
+// unfair returns true if an honest signer has been aborted while another signer holds the signed contract,
+// either from the ttp or because every honest signer has sent its signature.
+func (h *fairnessHarness) unfair(node *harnessNode) bool {
+	aborted, signed := false, false
+	allSignatures := h.dishonest != 0
+	for i := 0; i < h.n; i++ {
+		switch {
+		case h.isDishonest(i):
+			signed = signed || node.status[i] == harnessContract
+		case node.status[i] == harnessAborted:
+			aborted = true
+		case node.status[i] == harnessSigned || node.status[i] == harnessContract:
+			signed = true
+		}
+		if !h.isDishonest(i) && node.phase[i] < len(h.occ[i]) {
+			allSignatures = false
+		}
+	}
+	return aborted && (signed || allSignatures)
+}
+
+// key returns a representation of the node for deduplication
+func (h *fairnessHarness) key(node *harnessNode) string {
+	return fmt.Sprint(node.phase, node.status) + h.archivesKey(node.archives)
+}
+
+// archivesKey returns a representation of the archives of the ttp, keeping only the data used by the resolve protocol
+func (h *fairnessHarness) archivesKey(a *entities.SignatureArchives) string {
+	if a == nil {
+		return "none"
+	}
+
+	dishonest := append([]uint32{}, a.DishonestSigners...)
+	isDishonest := make(map[uint32]bool)
+	for _, s := range dishonest {
+		isDishonest[s] = true
+	}
+
+	var promises, aborted []string
+	for _, p := range a.ReceivedPromises {
+		promises = append(promises, fmt.Sprint(p.SenderKeyIndex, ">", p.RecipientKeyIndex, "@", p.SequenceIndex))
+	}
+	for _, s := range a.AbortedSigners {
+		if isDishonest[s.SignerIndex] {
+			// The abort index of a dishonest signer is not used anymore
+			aborted = append(aborted, fmt.Sprint(s.SignerIndex))
+		} else {
+			aborted = append(aborted, fmt.Sprint(s.SignerIndex, "@", s.AbortIndex))
+		}
+	}
+	sort.Strings(promises)
+	sort.Strings(aborted)
+	sort.Slice(dishonest, func(i, j int) bool { return dishonest[i] < dishonest[j] })
+	return fmt.Sprint(promises, aborted, dishonest, len(a.SignedContract) > 0)
+}
+
+// explore visits every reachable state, and returns the number of visited states and a counterexample, if any
+func (h *fairnessHarness) explore() (int, []string) {
+	defer func() {
+		_, _ = h.server.DB.Get("signatures").DeleteByID(entities.SignatureArchives{ID: h.uuid})
+	}()
+
+	initial := &harnessNode{
+		phase:  make([]int, h.n),
+		status: make([]byte, h.n),
+	}
+	visited := map[string]bool{h.key(initial): true}
+	queue := []*harnessNode{initial}
+
+	for len(queue) > 0 {
+		node := queue[0]
+		queue = queue[1:]
+		if h.unfair(node) {
+			return len(visited), node.trace
+		}
+		for _, next := range h.events(node) {
+			k := h.key(next)
+			if !visited[k] {
+				visited[k] = true
+				queue = append(queue, next)
+			}
+		}
+	}
+	return len(visited), nil
+}
+
+func (h *fairnessHarness) dishonestSigners() []int {
+	var res []int
+	for i := 0; i < h.n; i++ {
+		if h.isDishonest(i) {
+			res = append(res, i)
+		}
+	}
+	return res
+}
+
+// runFairnessHarness explores every set of dishonest signers but the whole group, and checks that the real ttp
+// keeps the signature fair exactly when the abstract model of the protocol does (see common.CheckFairness).
+func runFairnessHarness(t *testing.T, sequence []uint32, n int) {
+	report, err := common.CheckFairness(sequence, n)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := make(map[uint32]bool)
+	for _, s := range report.Scenarios {
+		var dishonest uint32
+		for _, i := range s.Dishonest {
+			dishonest |= 1 << i
+		}
+		expected[dishonest] = s.Fair
+	}
+
+	previous := entities.AuthContainer
+	harnessCredentials(t)
+	entities.AuthContainer = harnessTTP
+	defer func() { entities.AuthContainer = previous }()
+
+	for dishonest := uint32(0); dishonest < 1<<uint(n)-1; dishonest++ {
+		h := newFairnessHarness(t, sequence, n, dishonest)
+		states, counterexample := h.explore()
+		fair := counterexample == nil
+		switch {
+		case fair && !expected[dishonest]:
+			t.Errorf("Sequence %v should not be fair against dishonest signers %v", sequence, h.dishonestSigners())
+		case !fair && expected[dishonest]:
+			t.Errorf("Sequence %v is not fair against dishonest signers %v:\n  %s", sequence, h.dishonestSigners(), strings.Join(counterexample, "\n  "))
+		default:
+			t.Logf("Sequence %v, dishonest signers %v: %d states, fair: %v", sequence, h.dishonestSigners(), states, fair)
+		}
+	}
+}
+
+func TestFairnessHarness(t *testing.T) {
+	runFairnessHarness(t, contract.SquaredGenerator{}.Generate(2), 2)
+	runFairnessHarness(t, contract.SquaredGenerator{}.Generate(3), 3)
+}
+
+func TestFairnessHarnessUnfair(t *testing.T) {
+	// Fair against a single dishonest signer only
+	runFairnessHarness(t, contract.CompactGenerator{}.Generate(3), 3)
+	// Too short to be fair
+	runFairnessHarness(t, []uint32{0, 1, 0}, 2)
+}
+
+func TestFairnessHarnessFourSigners(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping the exploration for 4 signers in short mode")
+	}
+	runFairnessHarness(t, contract.SquaredGenerator{}.Generate(4), 4)
+}
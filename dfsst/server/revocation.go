@@ -0,0 +1,50 @@
+package server
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"dfss/dfssc/security"
+	dAPI "dfss/dfssd/api"
+	pAPI "dfss/dfssp/api"
+	"dfss/net"
+	"golang.org/x/net/context"
+)
+
+// revocationRetryDelay is the delay before trying again to update the revocation list after a failure
+var revocationRetryDelay = time.Minute
+
+// watchRevocationList keeps the certificate revocation list up to date, so that the TTP rejects revoked users.
+// It never returns, and should be started in its own goroutine.
+func watchRevocationList(addrPort, filename string, auth *security.AuthContainer) {
+	var platform pAPI.PlatformClient
+	for {
+		delay := revocationRetryDelay
+
+		if platform == nil {
+			conn, err := net.Connect(addrPort, auth.Cert, auth.Key, auth.CA, nil)
+			if err != nil {
+				fmt.Fprintln(os.Stderr, "Warning: unable to connect to the platform to fetch the revocation list:", err)
+			} else {
+				platform = pAPI.NewPlatformClient(conn)
+			}
+		}
+
+		if platform != nil {
+			ctx, cancel := context.WithTimeout(context.Background(), net.DefaultTimeout)
+			list, err := security.UpdateRevocationList(ctx, platform, auth.CA, filename)
+			cancel()
+			if err != nil {
+				fmt.Fprintln(os.Stderr, "Warning: unable to update the revocation list:", err)
+			} else {
+				dAPI.DLog("revocation list updated")
+				if d := list.NextUpdate.Sub(time.Now()); d > 0 {
+					delay = d
+				}
+			}
+		}
+
+		time.Sleep(delay)
+	}
+}
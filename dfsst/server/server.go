@@ -24,6 +24,9 @@ import (
 // InternalError : constant string used to return a generic error message through gRPC in case of an internal error.
 const InternalError string = "Internal server error"
 
+// UnconvertiblePromise : constant string used to reject an alert containing a promise that the ttp cannot convert.
+const UnconvertiblePromise string = "Unconvertible promise"
+
 type ttpServer struct {
 	DB        *mgdb.MongoManager
 	globalMut *sync.Mutex
@@ -76,11 +79,16 @@ func (server *ttpServer) Alert(ctx context.Context, in *tAPI.AlertRequest) (*tAP
 		return message, err
 	}
 
-	// We check that the sender of the request sent valid and complete information
-	stop, message, tmpPromises, err := server.handleInvalidPromises(manager, in.Promises, senderIndex, in.Index)
-	if stop {
-		dAPI.DLog("sent abort token to " + net.GetCN(&ctx))
-		return message, err
+	// We check that the sender of the request sent valid and complete information.
+	// A signer resolving during the first round before its own occurrence has not sent any promise yet:
+	// its promise to itself cannot match the sequence, and its request is not evidence against him.
+	var tmpPromises []*entities.Promise
+	if in.Index != 0 || in.Promises[0].Context.Sequence[0] == senderIndex {
+		stop, message, tmpPromises, err = server.handleInvalidPromises(manager, in.Promises, senderIndex, in.Index)
+		if stop {
+			dAPI.DLog("sent abort token to " + net.GetCN(&ctx))
+			return message, err
+		}
 	}
 	// Now we are sure that the sender of the AlertRequest is not dishonest
 
@@ -98,7 +106,7 @@ func (server *ttpServer) Alert(ctx context.Context, in *tAPI.AlertRequest) (*tAP
 	// Computing the dishonest signers wrt to the new evidence
 	server.updateArchiveWithEvidence(manager, tmpPromises)
 	// Try to generate the contract now
-	message, err = server.handleContractGenerationTry(manager)
+	message, err = server.handleContractGenerationTry(manager, senderIndex, in.Index)
 	// We manually update the database
 	ok, err = server.DB.Get("signatures").UpdateByID(*(manager.Archives))
 	if !ok {
@@ -145,6 +153,8 @@ func (server *ttpServer) handleAbortedSender(manager *entities.ArchivesManager,
 // OR
 // - he sent not enough information wrt the information signed by the platform and the signing protocol
 // then he is added to the dishonest signers.
+// Promises from other signers that cannot be converted are not held against him: as his client checks the escrow of
+// every promise it receives, the request is only rejected, without recording anything.
 //
 // Returns a boolean that states if we should stop the execution of the resolve protocol, and the response that should be sent back to him.
 // If the promises are valid, return them in the simplified form of an array of *entities.Promise
@@ -152,9 +162,15 @@ func (server *ttpServer) handleAbortedSender(manager *entities.ArchivesManager,
 // Updates the database with the new aborted signer.
 // If an error occurs during this process, it is returned.
 func (server *ttpServer) handleInvalidPromises(manager *entities.ArchivesManager, promises []*cAPI.Promise, senderIndex, stepIndex uint32) (bool, *tAPI.TTPResponse, []*entities.Promise, error) {
-	valid, tmpPromises := entities.ArePromisesValid(promises)
+	valid, tmpPromises := entities.ArePromisesValid(promises, senderIndex)
 	if valid {
 		dAPI.DLog("received promises are valid")
+		for _, p := range tmpPromises {
+			if len(p.Signature) == 0 {
+				dAPI.DLog("received promise from signer " + fmt.Sprint(p.SenderKeyIndex) + " cannot be converted")
+				return true, nil, nil, errors.New(UnconvertiblePromise)
+			}
+		}
 	}
 	complete := resolve.ArePromisesComplete(tmpPromises, promises[0], stepIndex)
 	if complete {
@@ -167,7 +183,7 @@ func (server *ttpServer) handleInvalidPromises(manager *entities.ArchivesManager
 		if !complete {
 			dAPI.DLog("received promises are not complete")
 		}
-		manager.AddToAbort(senderIndex)
+		manager.AddToAbort(senderIndex, stepIndex)
 		manager.AddToDishonest(senderIndex)
 
 		ok, err := manager.DB.Get("signatures").UpdateByID(*(manager.Archives))
@@ -185,18 +201,23 @@ func (server *ttpServer) handleInvalidPromises(manager *entities.ArchivesManager
 	return false, nil, tmpPromises, nil
 }
 
-// updateArchiveWithEvidence : computes the dishonest signers from the new provided evidence, and updates the specified signatureArchives accordingly.
+// updateArchiveWithEvidence : computes the dishonest signers from the new provided evidence and the already received promises,
+// and updates the specified signatureArchives accordingly.
 //
 // DOES NOT UPDATE THE DATABASE (should be handled manually)
 func (server *ttpServer) updateArchiveWithEvidence(manager *entities.ArchivesManager, tmpPromises []*entities.Promise) {
-	computedDishonest := resolve.ComputeDishonestSigners(manager.Archives, tmpPromises)
+	for _, p := range tmpPromises {
+		manager.AddPromise(p)
+	}
 
-	for _, di := range computedDishonest {
-		manager.AddToDishonest(di)
+	evidence := make([]*entities.Promise, len(manager.Archives.ReceivedPromises))
+	for i := range manager.Archives.ReceivedPromises {
+		evidence[i] = &manager.Archives.ReceivedPromises[i]
 	}
+	computedDishonest := resolve.ComputeDishonestSigners(manager.Archives, evidence)
 
-	for _, p := range tmpPromises {
-		manager.AddPromise(p)
+	for _, di := range computedDishonest {
+		manager.AddToDishonest(di)
 	}
 }
 
@@ -204,12 +225,16 @@ func (server *ttpServer) updateArchiveWithEvidence(manager *entities.ArchivesMan
 // Returns the response to send back to the sender of the request.
 //
 // Does not take into account if the sender is dishonest.
-// If the contract has been successfully generated, returns it. Otherwise, returns an abort token.
+// If the contract has been successfully generated, returns it. Otherwise, returns an abort token,
+// and records the sender as aborted at the specified sequence index.
 //
 // DOES NOT UPDATE THE DATABASE (should be handled manually)
-func (server *ttpServer) handleContractGenerationTry(manager *entities.ArchivesManager) (*tAPI.TTPResponse, error) {
+func (server *ttpServer) handleContractGenerationTry(manager *entities.ArchivesManager, senderIndex, stepIndex uint32) (*tAPI.TTPResponse, error) {
 	generated, contract := resolve.Solve(manager)
 	if !generated {
+		manager.AddToAbort(senderIndex, stepIndex)
+		// The sender may already have sent promises after this index
+		server.updateArchiveWithEvidence(manager, nil)
 		return &tAPI.TTPResponse{
 			Abort:    true,
 			Contract: nil,
@@ -290,6 +315,10 @@ func GetServer() *grpc.Server {
 		mutMap:    mutmap,
 	}
 
+	if addrPort := viper.GetString("platform_addrport"); addrPort != "" {
+		go watchRevocationList(addrPort, viper.GetString("file_crl"), entities.AuthContainer)
+	}
+
 	netServer := net.NewServer(cert, key, ca)
 	tAPI.RegisterTTPServer(netServer, server)
 	return netServer
@@ -1,7 +1,7 @@
 package server
 
 import (
-	"crypto/rsa"
+	"crypto"
 	"crypto/sha512"
 	"crypto/x509"
 	"fmt"
@@ -14,6 +14,7 @@ import (
 	"dfss/dfsst/entities"
 	"dfss/mgdb"
 	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
 	"gopkg.in/mgo.v2/bson"
 )
 
@@ -21,7 +22,7 @@ var (
 	fca, fcert, fkey string
 	db               string
 	platformCA, cert *x509.Certificate
-	pkey             *rsa.PrivateKey
+	pkey             crypto.Signer
 
 	sequence             []uint32
 	signers              [][]byte
@@ -102,3 +103,69 @@ func TestMain(m *testing.M) {
 
 	os.Exit(code)
 }
+
+func TestHandleContractGenerationTry(t *testing.T) {
+	archives := entities.NewSignatureArchives(signatureUUIDBson, sequence, signersEntities, contractDocumentHash, signedHash)
+	manager := &entities.ArchivesManager{
+		DB:       ttp.DB,
+		Archives: archives,
+	}
+
+	// Signer 1 already sent a promise at index 4
+	manager.AddPromise(&entities.Promise{
+		RecipientKeyIndex: 0,
+		SenderKeyIndex:    1,
+		SequenceIndex:     4,
+		Signature:         []byte{0x01},
+	})
+
+	// Not every signer has promised: the sender is aborted at the specified index
+	response, err := ttp.handleContractGenerationTry(manager, 2, 5)
+	assert.Nil(t, err)
+	assert.True(t, response.Abort)
+	assert.Nil(t, response.Contract)
+	assert.Equal(t, len(archives.AbortedSigners), 1)
+	assert.Equal(t, archives.AbortedSigners[0].SignerIndex, uint32(2))
+	assert.Equal(t, archives.AbortedSigners[0].AbortIndex, uint32(5))
+	assert.Equal(t, len(archives.DishonestSigners), 0)
+	assert.Equal(t, len(archives.SignedContract), 0)
+
+	// Signer 1 is aborted before the promise he already sent: he is dishonest
+	response, err = ttp.handleContractGenerationTry(manager, 1, 3)
+	assert.Nil(t, err)
+	assert.True(t, response.Abort)
+	assert.Equal(t, len(archives.AbortedSigners), 2)
+	assert.Equal(t, archives.AbortedSigners[1].SignerIndex, uint32(1))
+	assert.Equal(t, archives.AbortedSigners[1].AbortIndex, uint32(3))
+	assert.Equal(t, archives.DishonestSigners, []uint32{1})
+
+	// The first abort index is kept
+	_, _ = ttp.handleContractGenerationTry(manager, 2, 8)
+	assert.Equal(t, len(archives.AbortedSigners), 2)
+	assert.Equal(t, archives.AbortedSigners[0].AbortIndex, uint32(5))
+}
+
+func TestUpdateArchiveWithEvidence(t *testing.T) {
+	archives := entities.NewSignatureArchives(signatureUUIDBson, sequence, signersEntities, contractDocumentHash, signedHash)
+	manager := &entities.ArchivesManager{
+		DB:       ttp.DB,
+		Archives: archives,
+	}
+	manager.AddToAbort(0, 2)
+	manager.AddToAbort(1, 2)
+
+	ttp.updateArchiveWithEvidence(manager, []*entities.Promise{
+		{RecipientKeyIndex: 2, SenderKeyIndex: 0, SequenceIndex: 0, Signature: []byte{0x01}},
+		{RecipientKeyIndex: 2, SenderKeyIndex: 1, SequenceIndex: 1, Signature: []byte{0x01}},
+	})
+	assert.Equal(t, len(archives.ReceivedPromises), 2)
+	assert.Equal(t, len(archives.DishonestSigners), 0)
+
+	// Signer 1 kept on signing after his abort token
+	ttp.updateArchiveWithEvidence(manager, []*entities.Promise{
+		{RecipientKeyIndex: 2, SenderKeyIndex: 1, SequenceIndex: 7, Signature: []byte{0x01}},
+	})
+	assert.Equal(t, len(archives.ReceivedPromises), 2)
+	assert.Equal(t, archives.ReceivedPromises[1].SequenceIndex, uint32(7))
+	assert.Equal(t, archives.DishonestSigners, []uint32{1})
+}
@@ -36,6 +36,7 @@ func Load() {
 	// Setup file paths
 	viper.Set("home_dir", path)
 	viper.Set("file_ca", filepath.Join(path, viper.GetString("filename_ca")))
+	viper.Set("file_crl", filepath.Join(path, viper.GetString("filename_crl")))
 	viper.Set("file_cert", filepath.Join(path, viper.GetString("filename_cert")))
 	viper.Set("file_key", filepath.Join(path, viper.GetString("filename_key")))
 	viper.Set("file_config", filepath.Join(path, viper.GetString("filename_config"))+".json")
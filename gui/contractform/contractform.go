@@ -50,10 +50,14 @@ func NewWidget() *Widget {
 			}
 
 			err = sign.SendNewContract(
+				sign.ConfigFromViper(),
 				pwd,
 				fileField.Text(),
 				commentField.ToPlainText(),
 				w.SignersList(),
+				"",
+				0,
+				false,
 			)
 
 			if err != nil {
@@ -24,6 +24,7 @@ type widget interface {
 
 func init() {
 	viper.Set("filename_ca", "ca.pem")
+	viper.Set("filename_crl", "crl.pem")
 	viper.Set("filename_cert", "cert.pem")
 	viper.Set("filename_key", "key.pem")
 	viper.Set("filename_config", "config")
@@ -84,7 +84,7 @@ func (w *window) showFetchForm() {
 			uuid := dialog.TextValue()
 			path := viper.GetString("home_dir") + uuid + ".json"
 
-			err := sign.FetchContract(pwd, uuid, path)
+			err := sign.FetchContract(sign.ConfigFromViper(), pwd, uuid, path)
 
 			if err != nil {
 				common.ShowMsgBox(err.Error(), true)
@@ -35,6 +35,11 @@ func NewWidget(contract *contract.JSON, onSign func()) *Widget {
 	signersField.SetText(getSignersString(contract))
 	informationField.SetText("Contract #" + contract.UUID + "\nCreated on " + contract.Date.Format("2006-01-02 15:04:05 MST") + ".")
 
+	// Forged files are only detected at the beginning of the signature otherwise
+	if err := common.CheckDFSSFile(contract, viper.GetString("file_ca")); err != nil {
+		dialog.ShowMsgBox("Beware, "+err.Error()+"!", true)
+	}
+
 	w := &Widget{
 		QWidget: form,
 		checked: false,
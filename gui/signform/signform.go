@@ -2,6 +2,8 @@
 package signform
 
 import (
+	"sync"
+
 	"dfss/dfssc/sign"
 	"dfss/dfssp/contract"
 	"github.com/spf13/viper"
@@ -27,6 +29,10 @@ type Widget struct {
 	statusMax, statusCurrent int32
 	feedback                 string
 	running                  bool
+
+	phaseMutex sync.Mutex
+	phase      chan interface{} // closed at the end of the current cancellable phase, nil outside of them
+	cancelling bool             // a cancellation is pending in the current phase
 }
 
 func NewWidget(contract *contract.JSON, pwd string) *Widget {
@@ -60,15 +66,36 @@ func NewWidget(contract *contract.JSON, pwd string) *Widget {
 	}
 
 	w.cancelButton.OnClicked(func() {
+		w.phaseMutex.Lock()
+		phase := w.phase
+		if phase == nil || w.cancelling {
+			w.phaseMutex.Unlock()
+			return
+		}
+		w.cancelling = true
+		w.phaseMutex.Unlock()
+
 		// Render an immediate feedback to user
 		f := "Cancelling signature process..."
+		if w.running {
+			f = "Cancelling signature process, contacting the TTP..."
+		}
 		w.feedback = f
 		w.feedbackLabel.SetText(f)
 		w.cancelButton.SetDisabled(true)
 		w.statusMax = 1
 		w.statusCurrent = 0
-		// Ask for cancellation in a separate goroutine to avoid blocking Qt
-		go func() { w.manager.Cancel <- true }()
+		// Ask for cancellation in a separate goroutine to avoid blocking Qt.
+		// The manager only reads its Cancel channel during the current phase, the request is dropped once it ends.
+		go func() {
+			select {
+			case w.manager.Cancel <- true:
+			case <-phase:
+				w.phaseMutex.Lock()
+				w.cancelling = false
+				w.phaseMutex.Unlock()
+			}
+		}()
 	})
 
 	w.initLines()
@@ -77,7 +104,15 @@ func NewWidget(contract *contract.JSON, pwd string) *Widget {
 		err = w.execute()
 		if err != nil {
 			w.feedback = err.Error()
-		} else {
+			return
+		}
+
+		switch w.manager.Outcome() {
+		case sign.OutcomeAborted:
+			w.feedback = "Signature aborted by the TTP, the contract is not signed."
+		case sign.OutcomeResolved:
+			w.feedback = "Contract signed by the TTP!"
+		default:
 			w.feedback = "Contract signed successfully!"
 		}
 	}()
@@ -89,26 +124,46 @@ func NewWidget(contract *contract.JSON, pwd string) *Widget {
 // WE SHOULD NOT CALL ANY QT FUNCTION FROM IT.
 func (w *Widget) execute() error {
 	w.feedback = "Connecting to peers..."
-	err := w.manager.ConnectToPeers()
+	err := w.cancellable(w.manager.ConnectToPeers)
 	if err != nil {
 		return err
 	}
 
 	w.feedback = "Waiting for peers..."
-	_, err = w.manager.SendReadySign()
+	err = w.cancellable(func() error {
+		_, err := w.manager.SendReadySign()
+		return err
+	})
 	if err != nil {
 		return err
 	}
 
 	w.feedback = "Signature in progress..."
 	w.running = true
-	err = w.manager.Sign()
-	if err != nil {
-		return err
-	}
+	return w.cancellable(w.manager.Sign) // TODO choose destination
+}
+
+// cancellable runs a phase of the signature that can be cancelled through the Cancel channel of the manager
+func (w *Widget) cancellable(phase func() error) error {
+	done := make(chan interface{})
+	w.phaseMutex.Lock()
+	w.phase = done
+	w.phaseMutex.Unlock()
+
+	err := phase()
+
+	w.phaseMutex.Lock()
+	w.phase = nil
+	w.phaseMutex.Unlock()
+	close(done)
+	return err
+}
 
-	w.feedback = "Storing file..."
-	return w.manager.PersistSignaturesToFile() // TODO choose destination
+// isCancellable returns true if the signature is in a cancellable phase, and not already being cancelled
+func (w *Widget) isCancellable() bool {
+	w.phaseMutex.Lock()
+	defer w.phaseMutex.Unlock()
+	return w.phase != nil && !w.cancelling
 }
 
 func (w *Widget) signerUpdated(mail string, status sign.SignerStatus, data string) {
@@ -127,7 +182,7 @@ func (w *Widget) Tick() {
 	w.feedbackLabel.SetText(w.feedback)
 	w.progressBar.SetMaximum(w.statusMax)
 	w.progressBar.SetValue(w.statusCurrent)
-	w.cancelButton.SetDisabled(w.running || w.manager.IsTerminated())
+	w.cancelButton.SetDisabled(!w.isCancellable() || w.manager.IsTerminated())
 	for _, l := range w.lines {
 		l.cellA.SetIcon(icons[l.status])
 		l.cellA.SetText(icons_labels[l.status])
@@ -4,6 +4,7 @@ package userform
 import (
 	"io/ioutil"
 
+	"dfss/auth"
 	"dfss/dfssc/user"
 	"dfss/gui/common"
 	"dfss/gui/config"
@@ -39,7 +40,7 @@ func NewWidget(onRegistered func(pw string)) *Widget {
 
 		err := user.Register(
 			passwordField.Text(),
-			"", "", "", emailField.Text(), 2048,
+			"", "", "", emailField.Text(), auth.KeyTypeRSA, 2048,
 		)
 		if err != nil {
 			common.ShowMsgBox(err.Error(), true)
@@ -2,10 +2,12 @@
 package server
 
 import (
-	"crypto/rsa"
+	"crypto"
 	"crypto/x509"
 	"fmt"
+	"time"
 
+	"dfss/auth"
 	"dfss/dfssp/api"
 	"dfss/mockp/fixtures"
 	"dfss/net"
@@ -13,8 +15,11 @@ import (
 	"google.golang.org/grpc"
 )
 
-// mockServer empty struct to use grpc server
-type mockServer struct{}
+// mockServer holds the platform identity, to sign the revocation list
+type mockServer struct {
+	ca   *x509.Certificate
+	pkey crypto.Signer
+}
 
 // Register handler
 //
@@ -41,6 +46,13 @@ func (s *mockServer) Unregister(ctx context.Context, in *api.Empty) (*api.ErrorC
 	return nil, nil
 }
 
+// Renew handler
+//
+// Handle incoming RenewRequest messages
+func (s *mockServer) Renew(ctx context.Context, in *api.RenewRequest) (*api.RegisteredUser, error) {
+	return fixtures.AuthFixture["default"], nil
+}
+
 // PostContract handler
 //
 // Handle incoming PostContractRequest messages
@@ -71,15 +83,74 @@ func (s *mockServer) ReadySign(ctx context.Context, in *api.ReadySignRequest) (*
 	return nil, nil
 }
 
+// GetRevocationList handler
+//
+// Handle incoming GetRevocationList messages
+func (s *mockServer) GetRevocationList(ctx context.Context, in *api.Empty) (*api.RevocationList, error) {
+	crl, err := auth.GetRevocationList(1, nil, time.Hour, s.ca, s.pkey)
+	if err != nil {
+		return &api.RevocationList{ErrorCode: &api.ErrorCode{Code: api.ErrorCode_INTERR}}, nil
+	}
+	return &api.RevocationList{ErrorCode: &api.ErrorCode{Code: api.ErrorCode_SUCCESS}, Crl: crl}, nil
+}
+
+// PostEvidence handler
+//
+// Handle incoming Evidence messages
+func (s *mockServer) PostEvidence(ctx context.Context, in *api.Evidence) (*api.ErrorCode, error) {
+	// TODO
+	return nil, nil
+}
+
+// FetchEvidence handler
+//
+// Handle incoming FetchEvidenceRequest messages
+func (s *mockServer) FetchEvidence(ctx context.Context, in *api.FetchEvidenceRequest) (*api.EvidenceList, error) {
+	// TODO
+	return nil, nil
+}
+
+// Relay handler
+//
+// Handle incoming RelayRequest messages
+func (s *mockServer) Relay(in *api.RelayRequest, stream api.Platform_RelayServer) error {
+	// TODO
+	return nil
+}
+
+// RelayEvidence handler
+//
+// Handle incoming relayed Evidence messages
+func (s *mockServer) RelayEvidence(ctx context.Context, in *api.Evidence) (*api.ErrorCode, error) {
+	// TODO
+	return nil, nil
+}
+
+// PostDocument handler
+//
+// Handle incoming DocumentChunk streams
+func (s *mockServer) PostDocument(stream api.Platform_PostDocumentServer) error {
+	// TODO
+	return nil
+}
+
+// GetDocument handler
+//
+// Handle incoming GetContractRequest messages for hosted documents
+func (s *mockServer) GetDocument(in *api.GetContractRequest, stream api.Platform_GetDocumentServer) error {
+	// TODO
+	return nil
+}
+
 // GetServer returns the GRPC server associated with the platform
-func GetServer(ca *x509.Certificate, pkey *rsa.PrivateKey) *grpc.Server {
+func GetServer(ca *x509.Certificate, pkey crypto.Signer) *grpc.Server {
 	server := net.NewServer(ca, pkey, ca)
-	api.RegisterPlatformServer(server, &mockServer{})
+	api.RegisterPlatformServer(server, &mockServer{ca: ca, pkey: pkey})
 	return server
 }
 
 // Run the mock server on provided address and with provided ca
-func Run(ca *x509.Certificate, pkey *rsa.PrivateKey, addrPort string) {
+func Run(ca *x509.Certificate, pkey crypto.Signer, addrPort string) {
 	srv := GetServer(ca, pkey)
 	err := net.Listen(addrPort, srv)
 	if err != nil {
@@ -2,12 +2,12 @@ package net
 
 import (
 	"bytes"
-	"crypto/rsa"
+	"crypto"
 	"crypto/tls"
 	"crypto/x509"
 	"errors"
 	"net"
-	"strings"
+	"sync"
 	"time"
 
 	"dfss/auth"
@@ -29,7 +29,22 @@ var DefaultTimeout = 10 * time.Second
 //
 // serverCertHash will be matched against the remote server certificate.
 // If nil, Connect will consider that the remote server is the root ca.
-func Connect(addrPort string, cert *x509.Certificate, key *rsa.PrivateKey, ca *x509.Certificate, serverCertHash []byte) (*grpc.ClientConn, error) {
+//
+// The remote server certificate is rejected if it belongs to the current revocation list, see SetRevocationList.
+func Connect(addrPort string, cert *x509.Certificate, key crypto.Signer, ca *x509.Certificate, serverCertHash []byte) (*grpc.ClientConn, error) {
+	conn, _, err := ConnectWithCertificate(addrPort, cert, key, ca, serverCertHash)
+	return conn, err
+}
+
+// ConnectWithCertificate behaves like Connect, but also returns the authenticated certificate of the remote server.
+func ConnectWithCertificate(addrPort string, cert *x509.Certificate, key crypto.Signer, ca *x509.Certificate, serverCertHash []byte) (*grpc.ClientConn, *x509.Certificate, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), DefaultTimeout)
+	defer cancel()
+	return ConnectContext(ctx, addrPort, cert, key, ca, serverCertHash)
+}
+
+// ConnectContext behaves like ConnectWithCertificate, the connection attempt being bounded by the context instead of DefaultTimeout.
+func ConnectContext(ctx context.Context, addrPort string, cert *x509.Certificate, key crypto.Signer, ca *x509.Certificate, serverCertHash []byte) (*grpc.ClientConn, *x509.Certificate, error) {
 
 	var certificates = make([]tls.Certificate, 1)
 
@@ -56,12 +71,18 @@ func Connect(addrPort string, cert *x509.Certificate, key *rsa.PrivateKey, ca *x
 	}
 
 	// let's do the dialing !
-	return grpc.Dial(
+	creds := &tlsCreds{config: conf, serverCertHash: serverCertHash}
+	conn, err := grpc.DialContext(
+		ctx,
 		addrPort,
-		grpc.WithTransportCredentials(&tlsCreds{config: conf, serverCertHash: serverCertHash}),
-		grpc.WithTimeout(DefaultTimeout),
+		grpc.WithTransportCredentials(creds),
 		grpc.WithBlock(),
 	)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return conn, creds.getServerCert(), nil
 }
 
 // tlsCreds reimplements the default grpc TLS authenticator with no hostname verification.
@@ -74,6 +95,14 @@ func Connect(addrPort string, cert *x509.Certificate, key *rsa.PrivateKey, ca *x
 type tlsCreds struct {
 	config         tls.Config
 	serverCertHash []byte
+	serverCert     *x509.Certificate // last authenticated server certificate
+	mutex          sync.Mutex
+}
+
+func (c *tlsCreds) getServerCert() *x509.Certificate {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.serverCert
 }
 
 func (c *tlsCreds) Info() credentials.ProtocolInfo {
@@ -140,6 +169,15 @@ func (c *tlsCreds) ClientHandshake(addr string, rawConn net.Conn, timeout time.D
 		}
 	}
 
+	if err = verifyPeerRevocation(nil, chains); err != nil {
+		_ = rawConn.Close()
+		return nil, nil, err
+	}
+
+	c.mutex.Lock()
+	c.serverCert = serverCert
+	c.mutex.Unlock()
+
 	return conn, nil, nil
 }
 
@@ -148,28 +186,33 @@ func (c *tlsCreds) ServerHandshake(rawConn net.Conn) (net.Conn, credentials.Auth
 }
 
 // ExternalInterfaceAddr returns a list of the system's network interface addresses
-// Returns only ipv4 address if there is a lo interface, it is put at the end
+// IPv4 addresses come first, then IPv6 ones, and loopback addresses are put at the end.
+// Link-local addresses are ignored, as they cannot be used without their interface.
 func ExternalInterfaceAddr() ([]string, error) {
 	addrs, err := net.InterfaceAddrs()
 	if err != nil {
 		return nil, err
 	}
 
-	var extAddrs = make([]string, 0)
+	var ipv4Addrs = make([]string, 0)
+	var ipv6Addrs = make([]string, 0)
 	var localhostAddrs = make([]string, 0)
 
 	for _, a := range addrs {
-		if strings.ContainsRune(a.String(), ':') {
-			// ipv6, do nothing
-		} else if strings.ContainsRune(a.String(), '/') {
-			ip := strings.Split(a.String(), "/")[0]
-			if strings.HasPrefix(ip, "127") {
-				// move localhost ip at the end if present
-				localhostAddrs = append(localhostAddrs, ip)
-			} else {
-				extAddrs = append(extAddrs, ip)
-			}
+		ip, _, err := net.ParseCIDR(a.String())
+		if err != nil {
+			continue
+		}
+		switch {
+		case ip.IsLoopback():
+			localhostAddrs = append(localhostAddrs, ip.String())
+		case ip.IsLinkLocalUnicast():
+			// needs a zone, do nothing
+		case ip.To4() != nil:
+			ipv4Addrs = append(ipv4Addrs, ip.String())
+		default:
+			ipv6Addrs = append(ipv6Addrs, ip.String())
 		}
 	}
-	return append(extAddrs, localhostAddrs...), nil
+	return append(append(ipv4Addrs, ipv6Addrs...), localhostAddrs...), nil
 }
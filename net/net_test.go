@@ -1,9 +1,9 @@
 package net
 
 import (
+	"crypto/x509"
 	"fmt"
 	"net"
-	"regexp"
 	"testing"
 	"time"
 
@@ -168,6 +168,79 @@ func TestServerClientNonAuth(t *testing.T) {
 	time.Sleep(100 * time.Millisecond)
 }
 
+func TestServerClientRevokedServer(t *testing.T) {
+	// Start server
+	c := make(chan bool)
+	go startTestServer(c)
+	time.Sleep(2 * time.Second)
+
+	ca, _ := auth.PEMToCertificate([]byte(caFixture))
+	setTestRevocationList(t, ca)
+	defer SetRevocationList(nil)
+
+	_, err := Connect("localhost:9000", nil, nil, ca, nil)
+	if err == nil {
+		t.Fatal("Successfully connected to a revoked server")
+	}
+
+	c <- true
+	time.Sleep(100 * time.Millisecond)
+}
+
+func TestRevocationList(t *testing.T) {
+	ca, _ := auth.PEMToCertificate([]byte(caFixture))
+	cert, _ := auth.PEMToCertificate([]byte(clientCertFixture))
+
+	if IsRevoked(ca) || GetRevocationList(ca) != nil {
+		t.Fatal("Unexpected revocation list")
+	}
+
+	list := setTestRevocationList(t, ca)
+	if GetRevocationList(ca) != list {
+		t.Fatal("Bad revocation list")
+	}
+	if !IsRevoked(ca) || IsRevoked(cert) {
+		t.Fatal("Bad revocation status")
+	}
+	if verifyPeerRevocation(nil, [][]*x509.Certificate{{cert, ca}}) != nil {
+		t.Fatal("Valid chain rejected")
+	}
+	if verifyPeerRevocation(nil, [][]*x509.Certificate{{ca}}) == nil {
+		t.Fatal("Revoked chain accepted")
+	}
+
+	key, _ := auth.PEMToPrivateKey([]byte(serverKeyFixture))
+	data, _ := auth.GetRevocationList(0, nil, time.Hour, ca, key)
+	older, _ := auth.PEMToRevocationList(data, ca)
+	SetRevocationList(older)
+	if GetRevocationList(ca) != list || !IsRevoked(ca) {
+		t.Fatal("Revocation list replaced by an older one")
+	}
+
+	SetRevocationList(nil)
+	if IsRevoked(ca) {
+		t.Fatal("Revocation list not cleared")
+	}
+}
+
+// setTestRevocationList revokes the server certificate
+func setTestRevocationList(t *testing.T, ca *x509.Certificate) *x509.RevocationList {
+	key, _ := auth.PEMToPrivateKey([]byte(serverKeyFixture))
+	data, err := auth.GetRevocationList(1, []x509.RevocationListEntry{
+		{SerialNumber: ca.SerialNumber, RevocationTime: time.Now()},
+	}, time.Hour, ca, key)
+	if err != nil {
+		t.Fatal("Unable to create revocation list:", err)
+	}
+
+	list, err := auth.PEMToRevocationList(data, ca)
+	if err != nil {
+		t.Fatal("Unable to decode revocation list:", err)
+	}
+	SetRevocationList(list)
+	return list
+}
+
 func sharedServerClientTest(t *testing.T, client pb.TestClient, expectedAuth bool) {
 	r, err := client.Ping(context.Background(), &pb.Hop{Id: 0})
 	if err != nil {
@@ -257,10 +330,18 @@ func TestExternalInterfaces(t *testing.T) {
 		panic("Cannot read interfaces config")
 	}
 
-	isAnIPV4 := regexp.MustCompile("^(25[0-5]|2[0-4][0-9]|[01]?[0-9][0-9]?).(25[0-5]|2[0-4][0-9]|[01]?[0-9][0-9]?).(25[0-5]|2[0-4][0-9]|[01]?[0-9][0-9]?).(25[0-5]|2[0-4][0-9]|[01]?[0-9][0-9]?)$")
+	loopback := false
 	for _, ip := range ips {
-		if !isAnIPV4.MatchString(ip) {
-			panic(ip + " is not a valid IPv4!")
+		parsed := net.ParseIP(ip)
+		if parsed == nil {
+			panic(ip + " is not a valid IP!")
+		}
+		if parsed.IsLinkLocalUnicast() {
+			panic(ip + " is a link-local IP!")
+		}
+		if loopback && !parsed.IsLoopback() {
+			panic(ip + " is after a loopback IP!")
 		}
+		loopback = parsed.IsLoopback()
 	}
 }
@@ -0,0 +1,77 @@
+package net
+
+import (
+	"crypto/x509"
+	"errors"
+	"sync"
+)
+
+// revocation holds the serials of the certificates revoked by each root ca, by raw subject of the ca.
+// Peers presenting one of them are rejected by Connect and by servers created with NewServer.
+//
+// The lists are shared by the whole process, as every TLS handshake uses them: concurrent clients of the same platform
+// share its list, and a list is never replaced by an older one from the same issuer.
+var revocation struct {
+	sync.RWMutex
+	lists   map[string]*x509.RevocationList
+	serials map[string]map[string]bool
+}
+
+// SetRevocationList sets the certificate revocation list of its issuer used during TLS handshakes,
+// unless a more recent list of the same issuer is already set.
+// The list must have been authenticated beforehand, see auth.PEMToRevocationList.
+//
+// A nil list disables revocation checks.
+func SetRevocationList(list *x509.RevocationList) {
+	revocation.Lock()
+	defer revocation.Unlock()
+
+	if list == nil {
+		revocation.lists = nil
+		revocation.serials = nil
+		return
+	}
+
+	issuer := string(list.RawIssuer)
+	current := revocation.lists[issuer]
+	if current != nil && current.Number != nil && list.Number != nil && list.Number.Cmp(current.Number) < 0 {
+		return
+	}
+
+	serials := make(map[string]bool)
+	for _, entry := range list.RevokedCertificateEntries {
+		serials[entry.SerialNumber.String()] = true
+	}
+
+	if revocation.lists == nil {
+		revocation.lists = make(map[string]*x509.RevocationList)
+		revocation.serials = make(map[string]map[string]bool)
+	}
+	revocation.lists[issuer] = list
+	revocation.serials[issuer] = serials
+}
+
+// GetRevocationList returns the certificate revocation list of the ca used during TLS handshakes, or nil if there is none.
+func GetRevocationList(ca *x509.Certificate) *x509.RevocationList {
+	revocation.RLock()
+	defer revocation.RUnlock()
+	return revocation.lists[string(ca.RawSubject)]
+}
+
+// IsRevoked returns true if the certificate belongs to the current revocation list of its issuer.
+func IsRevoked(cert *x509.Certificate) bool {
+	revocation.RLock()
+	defer revocation.RUnlock()
+	return revocation.serials[string(cert.RawIssuer)][cert.SerialNumber.String()]
+}
+
+// verifyPeerRevocation rejects verified chains whose leaf certificate has been revoked.
+// It matches the tls.Config.VerifyPeerCertificate signature.
+func verifyPeerRevocation(_ [][]byte, chains [][]*x509.Certificate) error {
+	for _, chain := range chains {
+		if len(chain) > 0 && IsRevoked(chain[0]) {
+			return errors.New("credentials: Revoked remote certificate")
+		}
+	}
+	return nil
+}
@@ -2,7 +2,7 @@
 package net
 
 import (
-	"crypto/rsa"
+	"crypto"
 	"crypto/tls"
 	"crypto/x509"
 	"net"
@@ -19,10 +19,9 @@ import (
 // cert/key/ca are PEM-encoded array of bytes.
 //
 // The returned grpcServer must be used in association with server{} to
-// register APIs before calling Listen().
-func NewServer(cert *x509.Certificate, key *rsa.PrivateKey, ca *x509.Certificate) *grpc.Server {
+// register APIs before calling Listen(). Additional options, such as interceptors, can be provided.
+func NewServer(cert *x509.Certificate, key crypto.Signer, ca *x509.Certificate, opts ...grpc.ServerOption) *grpc.Server {
 	// configure gRPC
-	var opts []grpc.ServerOption
 
 	serverCert := tls.Certificate{
 		Certificate: [][]byte{cert.Raw},
@@ -38,9 +37,11 @@ func NewServer(cert *x509.Certificate, key *rsa.PrivateKey, ca *x509.Certificate
 		RootCAs:      caCertPool,
 		ClientCAs:    caCertPool,
 		ClientAuth:   tls.VerifyClientCertIfGiven,
+		// Client certificates are verified before this callback, we only need to check their revocation
+		VerifyPeerCertificate: verifyPeerRevocation,
 	})
 
-	opts = []grpc.ServerOption{grpc.Creds(ta)}
+	opts = append([]grpc.ServerOption{grpc.Creds(ta)}, opts...)
 	return grpc.NewServer(opts...)
 }
 
@@ -7,6 +7,7 @@ import (
 	"testing"
 	"time"
 
+	"dfss/auth"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -32,7 +33,7 @@ func TestRegisterAuth(t *testing.T) {
 	// Start the platform
 	workingDir, err := ioutil.TempDir("", "dfss_")
 	assert.Equal(t, nil, err)
-	_, _, _, stop, ca, err := startPlatform(workingDir)
+	_, _, _, stop, ca, err := startPlatform(workingDir, auth.KeyTypeRSA)
 	assert.Equal(t, nil, err)
 	defer stop()
 
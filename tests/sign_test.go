@@ -11,22 +11,23 @@ import (
 	"testing"
 	"time"
 
+	"dfss/auth"
 	"dfss/dfssp/contract"
 	"github.com/stretchr/testify/assert"
 )
 
 // setupSignature prepares required servers and clients to sign a contract.
-// - Start platform, ttp, demonstrator
+// - Start platform, with a private key of the provided type, ttp, demonstrator
 // - Register client1, client2 and client3
 // - Create contract `contract.txt`
-func setupSignature(t *testing.T) (stop func(), clients []*exec.Cmd, contractPath, contractFilePath string) {
+func setupSignature(t *testing.T, keyType string) (stop func(), clients []*exec.Cmd, contractPath, contractFilePath string) {
 	// Cleanup
 	eraseDatabase()
 
 	// Start the platform
 	workingDir, err := ioutil.TempDir("", "dfss_")
 	assert.Equal(t, nil, err)
-	_, _, _, stop, ca, err := startPlatform(workingDir)
+	_, _, _, stop, ca, err := startPlatform(workingDir, keyType)
 	assert.Equal(t, nil, err)
 
 	time.Sleep(2 * time.Second)
@@ -63,7 +64,7 @@ func setupSignature(t *testing.T) (stop func(), clients []*exec.Cmd, contractPat
 
 	// Get contract file
 	contractEntity := getContract("contract.txt", 0)
-	contractData, err := contract.GetJSON(contractEntity)
+	contractData, err := contract.GetJSON(contractEntity, nil)
 	assert.Equal(t, nil, err)
 	contractPath = filepath.Join(workingDir, "c.dfss")
 	err = ioutil.WriteFile(contractPath, contractData, os.ModePerm)
@@ -87,8 +88,18 @@ func setupSignature(t *testing.T) (stop func(), clients []*exec.Cmd, contractPat
 // TestSignContract unroll the whole signature process.
 // In this test, everything should work fine without any ttp call.
 func TestSignContract(t *testing.T) {
+	signContractHelper(t, auth.KeyTypeRSA)
+}
+
+// TestSignContractECDSAPlatform unroll the whole signature process with an ECDSA platform.
+// Its seals are randomized, so every signer has to get the same launch signal.
+func TestSignContractECDSAPlatform(t *testing.T) {
+	signContractHelper(t, auth.KeyTypeECDSAP256)
+}
+
+func signContractHelper(t *testing.T, keyType string) {
 	// Setup
-	stop, clients, contractPath, contractFilePath := setupSignature(t)
+	stop, clients, contractPath, contractFilePath := setupSignature(t, keyType)
 	defer stop()
 
 	// Sign!
@@ -157,7 +168,7 @@ func TestSignContractSuccess(t *testing.T) {
 // and the number of proof files expected to be generated.
 func signatureHelper(t *testing.T, failure bool) {
 	// Setup
-	stop, clients, contractPath, contractFilePath := setupSignature(t)
+	stop, clients, contractPath, contractFilePath := setupSignature(t, auth.KeyTypeRSA)
 	defer stop()
 
 	stopBefore, expectedProofFile1, expectedProofFile2 := "1", 0, 0
@@ -17,8 +17,9 @@ const testPort = "9090"
 
 var currentClient = 0
 
-// startPlatform creates root certificate for the platform and the TTP, and starts both modules
-func startPlatform(tmpDir string) (platform, ttp, demo *exec.Cmd, stop func(), ca []byte, err error) {
+// startPlatform creates root certificate for the platform and the TTP, and starts both modules.
+// The private key of the platform has the provided type.
+func startPlatform(tmpDir, keyType string) (platform, ttp, demo *exec.Cmd, stop func(), ca []byte, err error) {
 	path := filepath.Join(os.Getenv("GOPATH"), "bin", "dfssp")
 	ttpPath := filepath.Join(os.Getenv("GOPATH"), "bin", "dfsst")
 	demoPath := filepath.Join(os.Getenv("GOPATH"), "bin", "dfssd")
@@ -30,7 +31,7 @@ func startPlatform(tmpDir string) (platform, ttp, demo *exec.Cmd, stop func(), c
 	}
 
 	// Init
-	cmd := exec.Command(path, "--path", dir, "-v", "init")
+	cmd := exec.Command(path, "--path", dir, "-v", "init", "--type", keyType)
 	ttpsPath := filepath.Join(dir, "ttps")
 	err = cmd.Run()
 	if err != nil {